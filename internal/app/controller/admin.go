@@ -0,0 +1,129 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"github.com/tech4works/checker"
+	"github.com/tech4works/gopen-gateway/internal/app"
+	"github.com/tech4works/gopen-gateway/internal/app/factory"
+	"github.com/tech4works/gopen-gateway/internal/app/model/dto"
+	"github.com/tech4works/gopen-gateway/internal/domain/service"
+	"net/http"
+)
+
+type adminController struct {
+	gopen          *dto.Gopen
+	boot           app.Boot
+	cacheService   service.Cache
+	limiterService service.Limiter
+	toggleService  service.Toggle
+}
+
+type Admin interface {
+	Routes(ctx app.Context)
+	ToggleEndpoint(ctx app.Context)
+	PurgeCache(ctx app.Context)
+	ResetLimiter(ctx app.Context)
+	Reload(ctx app.Context)
+}
+
+func NewAdmin(gopen *dto.Gopen, boot app.Boot, cacheService service.Cache, limiterService service.Limiter,
+	toggleService service.Toggle) Admin {
+	return adminController{
+		gopen:          gopen,
+		boot:           boot,
+		cacheService:   cacheService,
+		limiterService: limiterService,
+		toggleService:  toggleService,
+	}
+}
+
+func (a adminController) Routes(ctx app.Context) {
+	result := []dto.RouteView{}
+	for _, config := range a.gopen.Endpoints {
+		endpoint, ok := ctx.Gopen().FindEndpoint(config.Path, config.Method)
+		if !ok {
+			continue
+		}
+		result = append(result, factory.BuildRouteView(config, endpoint, !a.toggleService.IsDisabled(endpoint)))
+	}
+	ctx.WriteJson(http.StatusOK, result)
+}
+
+func (a adminController) ToggleEndpoint(ctx app.Context) {
+	toggleEndpoint, err := factory.BuildToggleEndpoint(ctx)
+	if checker.NonNil(err) {
+		ctx.WriteError(http.StatusBadRequest, err)
+		return
+	}
+
+	config, configOk := a.findEndpointConfig(toggleEndpoint.Path, toggleEndpoint.Method)
+	endpoint, ok := ctx.Gopen().FindEndpoint(toggleEndpoint.Path, toggleEndpoint.Method)
+	if !configOk || !ok {
+		ctx.WriteStatusCode(http.StatusNotFound)
+		return
+	}
+
+	if toggleEndpoint.Enabled {
+		a.toggleService.Enable(endpoint)
+	} else {
+		a.toggleService.Disable(endpoint)
+	}
+	ctx.WriteJson(http.StatusOK, factory.BuildRouteView(config, endpoint, toggleEndpoint.Enabled))
+}
+
+func (a adminController) PurgeCache(ctx app.Context) {
+	purgeCache, err := factory.BuildPurgeCache(ctx)
+	if checker.NonNil(err) {
+		ctx.WriteError(http.StatusBadRequest, err)
+		return
+	}
+
+	keys, err := a.cacheService.Purge(ctx.Context(), purgeCache.Pattern)
+	if checker.NonNil(err) {
+		ctx.WriteError(http.StatusInternalServerError, err)
+		return
+	}
+	ctx.WriteJson(http.StatusOK, factory.BuildPurgeCacheView(purgeCache.Pattern, keys))
+}
+
+func (a adminController) ResetLimiter(ctx app.Context) {
+	buckets, err := a.limiterService.Reset(ctx.Context())
+	if checker.NonNil(err) {
+		ctx.WriteError(http.StatusInternalServerError, err)
+		return
+	}
+	ctx.WriteJson(http.StatusOK, factory.BuildResetLimiterView(buckets))
+}
+
+func (a adminController) Reload(ctx app.Context) {
+	err := a.boot.Reload()
+	if checker.NonNil(err) {
+		ctx.WriteError(http.StatusInternalServerError, err)
+		return
+	}
+	ctx.WriteStatusCode(http.StatusNoContent)
+}
+
+func (a adminController) findEndpointConfig(path, method string) (dto.Endpoint, bool) {
+	for _, config := range a.gopen.Endpoints {
+		if checker.Equals(config.Path, path) && checker.Equals(config.Method, method) {
+			return config, true
+		}
+	}
+	return dto.Endpoint{}, false
+}
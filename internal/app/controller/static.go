@@ -21,22 +21,38 @@ import (
 	"github.com/tech4works/gopen-gateway/internal/app"
 	"github.com/tech4works/gopen-gateway/internal/app/factory"
 	"github.com/tech4works/gopen-gateway/internal/app/model/dto"
+	"github.com/tech4works/gopen-gateway/internal/app/usecase"
+	"github.com/tech4works/gopen-gateway/internal/domain/service"
 	"net/http"
 )
 
 type staticController struct {
-	gopen *dto.Gopen
+	gopen            *dto.Gopen
+	healthUseCase    usecase.Health
+	readinessUseCase usecase.Readiness
+	limiterService   service.Limiter
+	metrics          app.Metrics
 }
 
 type Static interface {
 	Ping(ctx app.Context)
 	Version(ctx app.Context)
 	Settings(ctx app.Context)
+	HealthHosts(ctx app.Context)
+	HealthLive(ctx app.Context)
+	HealthReady(ctx app.Context)
+	LimiterStats(ctx app.Context)
+	Metrics(ctx app.Context)
 }
 
-func NewStatic(gopen *dto.Gopen) Static {
+func NewStatic(gopen *dto.Gopen, healthUseCase usecase.Health, readinessUseCase usecase.Readiness,
+	limiterService service.Limiter, metrics app.Metrics) Static {
 	return staticController{
-		gopen: gopen,
+		gopen:            gopen,
+		healthUseCase:    healthUseCase,
+		readinessUseCase: readinessUseCase,
+		limiterService:   limiterService,
+		metrics:          metrics,
 	}
 }
 
@@ -55,3 +71,33 @@ func (s staticController) Version(ctx app.Context) {
 func (s staticController) Settings(ctx app.Context) {
 	ctx.WriteJson(http.StatusOK, factory.BuildSettingView(*s.gopen))
 }
+
+func (s staticController) HealthHosts(ctx app.Context) {
+	ctx.WriteJson(http.StatusOK, factory.BuildHealthHostsView(s.healthUseCase.Report()))
+}
+
+func (s staticController) HealthLive(ctx app.Context) {
+	ctx.WriteJson(http.StatusOK, dto.LivenessView{Status: "UP"})
+}
+
+func (s staticController) HealthReady(ctx app.Context) {
+	report := s.readinessUseCase.Check(ctx.Context(), ctx.Gopen())
+	if report.IsReady() {
+		ctx.WriteJson(http.StatusOK, factory.BuildReadinessView(report))
+	} else {
+		ctx.WriteJson(http.StatusServiceUnavailable, factory.BuildReadinessView(report))
+	}
+}
+
+func (s staticController) LimiterStats(ctx app.Context) {
+	ctx.WriteJson(http.StatusOK, factory.BuildLimiterStatsView(s.limiterService.Stats()))
+}
+
+func (s staticController) Metrics(ctx app.Context) {
+	metrics, err := s.metrics.Export()
+	if checker.NonNil(err) {
+		ctx.WriteError(http.StatusInternalServerError, err)
+		return
+	}
+	ctx.WriteString(http.StatusOK, metrics)
+}
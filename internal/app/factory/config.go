@@ -17,27 +17,283 @@
 package factory
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
 	"fmt"
 	"github.com/tech4works/checker"
+	"github.com/tech4works/converter"
 	"github.com/tech4works/errors"
 	"github.com/tech4works/gopen-gateway/internal/app/model/dto"
 	"github.com/tech4works/gopen-gateway/internal/domain/model/enum"
 	"github.com/tech4works/gopen-gateway/internal/domain/model/vo"
+	"math/big"
+	"net/http"
+	"os"
+	"slices"
 	"strings"
 )
 
 func BuildGopen(gopen *dto.Gopen) *vo.Gopen {
 	return vo.NewGopen(
-		buildSecurityCors(gopen.SecurityCors),
+		buildSecurityCors(gopen.SecurityCors, nil),
+		buildGopenMaxConcurrent(gopen.Limiter),
+		buildReadiness(gopen.Readiness),
+		buildConsumers(gopen.Consumers),
 		buildEndpoints(gopen),
 	)
 }
 
-func buildSecurityCors(securityCors *dto.SecurityCors) *vo.SecurityCors {
-	if checker.IsNil(securityCors) {
+func buildReadiness(readiness *dto.Readiness) *vo.Readiness {
+	if checker.IsNil(readiness) {
 		return nil
 	}
-	return vo.NewSecurityCors(securityCors.AllowOrigins, securityCors.AllowMethods, securityCors.AllowHeaders)
+	return vo.NewReadiness(readiness.ProbeHosts, readiness.Timeout)
+}
+
+func buildSecurityCors(securityCors, endpointSecurityCors *dto.SecurityCors) *vo.SecurityCors {
+	if checker.IsNil(securityCors) && checker.IsNil(endpointSecurityCors) {
+		return nil
+	}
+
+	var allowOrigins, allowMethods, allowHeaders, exposeHeaders []string
+	var allowCredentials bool
+	var maxAge vo.Duration
+
+	// the endpoint security-cors overrides only the fields it configures, the rest comes from the global one
+	for _, config := range []*dto.SecurityCors{securityCors, endpointSecurityCors} {
+		if checker.IsNil(config) {
+			continue
+		}
+		if checker.NonNil(config.AllowOrigins) {
+			allowOrigins = config.AllowOrigins
+		}
+		if checker.NonNil(config.AllowMethods) {
+			allowMethods = config.AllowMethods
+		}
+		if checker.NonNil(config.AllowHeaders) {
+			allowHeaders = config.AllowHeaders
+		}
+		if checker.NonNil(config.ExposeHeaders) {
+			exposeHeaders = config.ExposeHeaders
+		}
+		if checker.NonNil(config.AllowCredentials) {
+			allowCredentials = *config.AllowCredentials
+		}
+		if checker.NonNil(config.MaxAge) {
+			maxAge = *config.MaxAge
+		}
+	}
+
+	// browsers refuse credentials with a wildcard origin, and reflecting any origin would share them with every site
+	if allowCredentials && (checker.IsNil(allowOrigins) || slices.Contains(allowOrigins, "*")) {
+		panic(errors.New("Security-cors allow-credentials requires explicit allow-origins, without \"*\"!"))
+	}
+
+	return vo.NewSecurityCors(allowOrigins, allowMethods, allowHeaders, exposeHeaders, allowCredentials, maxAge)
+}
+
+func buildJWT(auth, endpointAuth *dto.Auth) *vo.JWT {
+	// the endpoint jwt replaces the global one, it can also be disabled for public endpoints
+	var jwt *dto.JWT
+	if checker.NonNil(endpointAuth) && checker.NonNil(endpointAuth.JWT) {
+		jwt = endpointAuth.JWT
+	} else if checker.NonNil(auth) {
+		jwt = auth.JWT
+	}
+	if checker.IsNil(jwt) || jwt.Disabled {
+		return nil
+	}
+
+	if checker.IsEmpty(jwt.Secret) && checker.IsEmpty(jwt.PEMFile) && checker.IsEmpty(jwt.JWKSFile) {
+		panic(errors.New("Auth jwt requires secret, pem-file or jwks-file!"))
+	}
+
+	// the keys are parsed when the config is built, so an invalid key or algorithm never reaches the requests
+	keys := buildJWTKeys(jwt)
+	for _, algorithm := range jwt.Algorithms {
+		if !slices.ContainsFunc(keys, func(key vo.JWTKey) bool { return key.IsCompatible(algorithm) }) {
+			panic(errors.Newf("Auth jwt algorithm %s has no compatible key configured!", algorithm))
+		}
+	}
+
+	return vo.NewJWT(jwt.Algorithms, keys, jwt.Issuer, jwt.Audience, jwt.RequiredClaims, jwt.Leeway)
+}
+
+func buildJWTKeys(jwt *dto.JWT) []vo.JWTKey {
+	var keys []vo.JWTKey
+	if checker.IsNotEmpty(jwt.Secret) {
+		keys = append(keys, vo.NewJWTKey("", []byte(jwt.Secret)))
+	}
+
+	if checker.IsNotEmpty(jwt.PEMFile) {
+		value, err := parsePEMKey(readAuthFile(jwt.PEMFile))
+		if checker.NonNil(err) {
+			panic(errors.Newf("Error parse auth jwt pem-file %s: %s", jwt.PEMFile, err))
+		}
+		keys = append(keys, vo.NewJWTKey("", value))
+	}
+
+	if checker.IsNotEmpty(jwt.JWKSFile) {
+		jwksKeys, err := parseJWKSKeys(readAuthFile(jwt.JWKSFile))
+		if checker.NonNil(err) {
+			panic(errors.Newf("Error parse auth jwt jwks-file %s: %s", jwt.JWKSFile, err))
+		}
+		keys = append(keys, jwksKeys...)
+	}
+
+	return keys
+}
+
+func buildAPIKey(auth, endpointAuth *dto.Auth) *vo.APIKey {
+	var apiKey *dto.APIKey
+	if checker.NonNil(endpointAuth) && checker.NonNil(endpointAuth.APIKey) {
+		apiKey = endpointAuth.APIKey
+	} else if checker.NonNil(auth) {
+		apiKey = auth.APIKey
+	}
+	if checker.IsNil(apiKey) || apiKey.Disabled {
+		return nil
+	}
+	return vo.NewAPIKey(apiKey.Header, apiKey.Query)
+}
+
+func buildConsumers(consumers []dto.Consumer) []vo.Consumer {
+	var result []vo.Consumer
+
+	names := map[string]bool{}
+	apiKeys := map[string]bool{}
+	for _, consumer := range consumers {
+		if checker.IsEmpty(consumer.Name) || names[consumer.Name] {
+			panic(errors.Newf("Consumer name %q is empty or duplicated!", consumer.Name))
+		}
+		names[consumer.Name] = true
+
+		// the keys are configured as the hex of their sha256, so the plain keys never stay in the config
+		var hashes []string
+		for _, apiKey := range consumer.APIKeys {
+			hash := strings.ToLower(apiKey)
+			if _, err := hex.DecodeString(hash); checker.NonNil(err) || checker.NotEquals(len(hash), 64) {
+				panic(errors.Newf("Consumer %s api-key must be a sha256 hex hash!", consumer.Name))
+			} else if apiKeys[hash] {
+				panic(errors.Newf("Consumer %s api-key is already used by another consumer!", consumer.Name))
+			}
+			apiKeys[hash] = true
+			hashes = append(hashes, hash)
+		}
+
+		result = append(result, vo.NewConsumer(consumer.Name, hashes, consumer.AllowedEndpoints, consumer.Metadata))
+	}
+
+	return result
+}
+
+func readAuthFile(path string) string {
+	if checker.IsEmpty(path) {
+		return ""
+	}
+
+	bs, err := os.ReadFile(path)
+	if checker.NonNil(err) {
+		panic(errors.Newf("Error read auth file %s: %s", path, err))
+	}
+	return string(bs)
+}
+
+func parsePEMKey(raw string) (any, error) {
+	block, _ := pem.Decode([]byte(raw))
+	if checker.IsNil(block) {
+		return nil, errors.New("no pem block found")
+	}
+
+	var value any
+	var err error
+	switch block.Type {
+	case "CERTIFICATE":
+		var certificate *x509.Certificate
+		if certificate, err = x509.ParseCertificate(block.Bytes); checker.IsNil(err) {
+			value = certificate.PublicKey
+		}
+	case "RSA PUBLIC KEY":
+		value, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		value, err = x509.ParsePKIXPublicKey(block.Bytes)
+	}
+	if checker.NonNil(err) {
+		return nil, err
+	}
+
+	switch value.(type) {
+	case *rsa.PublicKey, *ecdsa.PublicKey:
+		return value, nil
+	default:
+		return nil, errors.New("expected a RSA or EC public key")
+	}
+}
+
+func parseJWKSKeys(raw string) ([]vo.JWTKey, error) {
+	var set dto.JSONWebKeySet
+	if err := json.Unmarshal([]byte(raw), &set); checker.NonNil(err) {
+		return nil, err
+	}
+
+	var keys []vo.JWTKey
+	for _, jsonWebKey := range set.Keys {
+		value, err := parseJSONWebKey(jsonWebKey)
+		if checker.NonNil(err) {
+			return nil, err
+		}
+		keys = append(keys, vo.NewJWTKey(jsonWebKey.Kid, value))
+	}
+	if checker.IsEmpty(keys) {
+		return nil, errors.New("no keys found")
+	}
+	return keys, nil
+}
+
+func parseJSONWebKey(jwk dto.JSONWebKey) (any, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeBigInt(jwk.N)
+		if checker.NonNil(err) {
+			return nil, err
+		}
+		e, err := decodeBigInt(jwk.E)
+		if checker.NonNil(err) {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		if checker.NotEquals(jwk.Crv, "P-256") {
+			return nil, errors.Newf("unsupported jwk curve %s", jwk.Crv)
+		}
+		x, err := decodeBigInt(jwk.X)
+		if checker.NonNil(err) {
+			return nil, err
+		}
+		y, err := decodeBigInt(jwk.Y)
+		if checker.NonNil(err) {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}, nil
+	case "oct":
+		return base64.RawURLEncoding.DecodeString(jwk.K)
+	default:
+		return nil, errors.Newf("unsupported jwk kty %s", jwk.Kty)
+	}
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	bs, err := base64.RawURLEncoding.DecodeString(value)
+	if checker.NonNil(err) {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(bs), nil
 }
 
 func buildEndpoints(gopen *dto.Gopen) []vo.Endpoint {
@@ -58,6 +314,7 @@ func buildEndpoints(gopen *dto.Gopen) []vo.Endpoint {
 			endpoints = append(endpoints, buildEndpoint(gopen, endpoint))
 		}
 	}
+	errs = append(errs, buildHealthCheckErrs(endpoints)...)
 
 	if checker.IsNotEmpty(errs) {
 		panic(strings.Join(errs, "\n"))
@@ -66,6 +323,31 @@ func buildEndpoints(gopen *dto.Gopen) []vo.Endpoint {
 	return endpoints
 }
 
+func buildHealthCheckErrs(endpoints []vo.Endpoint) []string {
+	var errs []string
+
+	// the health state is kept by host, so every health-check of the same host must use the same path
+	paths := map[string]string{}
+	for _, endpoint := range endpoints {
+		for _, backend := range endpoint.Backends() {
+			if !backend.HasHealthCheck() {
+				continue
+			}
+			for _, host := range backend.Hosts() {
+				path, exists := paths[host]
+				if !exists {
+					paths[host] = backend.HealthCheck().Path()
+				} else if checker.NotEquals(path, backend.HealthCheck().Path()) {
+					errs = append(errs, fmt.Sprintf("- Conflicting health-check path for host: %s paths: %s and %s", host,
+						path, backend.HealthCheck().Path()))
+				}
+			}
+		}
+	}
+
+	return errs
+}
+
 func buildEndpoint(gopen *dto.Gopen, endpoint dto.Endpoint) vo.Endpoint {
 	return vo.NewEndpoint(
 		endpoint.Path,
@@ -73,9 +355,12 @@ func buildEndpoint(gopen *dto.Gopen, endpoint dto.Endpoint) vo.Endpoint {
 		buildTimeout(gopen.Timeout, endpoint.Timeout),
 		buildLimiter(gopen.Limiter, endpoint.Limiter),
 		buildCache(gopen.Cache, endpoint.Cache),
+		buildSecurityCors(gopen.SecurityCors, endpoint.SecurityCors),
+		buildJWT(gopen.Auth, endpoint.Auth),
+		buildAPIKey(gopen.Auth, endpoint.Auth),
 		endpoint.AbortIfStatusCodes,
 		buildEndpointResponse(endpoint.Response),
-		buildBackends(gopen.Middlewares, endpoint),
+		buildBackends(gopen.Middlewares, gopen.Transport, endpoint),
 	)
 }
 
@@ -92,6 +377,7 @@ func buildLimiter(limiter *dto.Limiter, endpointLimiter *dto.EndpointLimiter) vo
 	var maxBodySize vo.Bytes
 	var maxMultipartForm vo.Bytes
 	var endpointRate, rate *dto.Rate
+	var maxConcurrent *vo.MaxConcurrent
 
 	if checker.NonNil(limiter) {
 		if checker.NonNil(limiter.MaxHeaderSize) {
@@ -117,34 +403,75 @@ func buildLimiter(limiter *dto.Limiter, endpointLimiter *dto.EndpointLimiter) vo
 			maxMultipartForm = endpointLimiter.MaxMultipartMemorySize
 		}
 		endpointRate = endpointLimiter.Rate
+		maxConcurrent = buildMaxConcurrent(endpointLimiter.MaxConcurrent)
+	}
+
+	return vo.NewLimiter(maxHeaderSize, maxBodySize, maxMultipartForm, buildLimiterRate(rate, endpointRate),
+		maxConcurrent)
+}
+
+func buildGopenMaxConcurrent(limiter *dto.Limiter) *vo.MaxConcurrent {
+	// the global max-concurrent is not a default for the endpoints, it caps the in-flight requests of the whole gateway
+	if checker.IsNil(limiter) {
+		return nil
 	}
+	return buildMaxConcurrent(limiter.MaxConcurrent)
+}
 
-	return vo.NewLimiter(maxHeaderSize, maxBodySize, maxMultipartForm, buildLimiterRate(rate, endpointRate))
+func buildMaxConcurrent(maxConcurrent *dto.MaxConcurrent) *vo.MaxConcurrent {
+	if checker.IsNil(maxConcurrent) {
+		return nil
+	} else if checker.IsLessThanOrEqual(maxConcurrent.Limit, 0) {
+		panic(errors.New("Limiter max-concurrent limit must be greater than 0!"))
+	}
+	return vo.NewMaxConcurrent(maxConcurrent.Limit, maxConcurrent.QueueSize, maxConcurrent.QueueTimeout)
 }
 
 func buildLimiterRate(rate, endpointRate *dto.Rate) vo.Rate {
 	var every vo.Duration
 	var capacity int
+	var scope enum.RateScope
+	var key *dto.RateKey
+	headers := true
 
-	if checker.NonNil(rate) {
-		if checker.NonNil(rate.Every) {
-			every = *rate.Every
+	for _, config := range []*dto.Rate{rate, endpointRate} {
+		if checker.IsNil(config) {
+			continue
+		}
+		if checker.NonNil(config.Every) {
+			every = *config.Every
+		}
+		if checker.NonNil(config.Capacity) {
+			capacity = *config.Capacity
 		}
-		if checker.NonNil(rate.Capacity) {
-			capacity = *rate.Capacity
+		if checker.IsNotEmpty(config.Scope) {
+			scope = config.Scope
+		}
+		if checker.NonNil(config.Key) {
+			key = config.Key
+		}
+		if checker.NonNil(config.Headers) {
+			headers = *config.Headers
 		}
 	}
 
-	if checker.NonNil(endpointRate) {
-		if checker.NonNil(endpointRate.Every) {
-			every = *endpointRate.Every
-		}
-		if checker.NonNil(endpointRate.Capacity) {
-			capacity = *endpointRate.Capacity
+	return vo.NewRate(every, capacity, scope, buildLimiterRateKey(key), headers)
+}
+
+func buildLimiterRateKey(key *dto.RateKey) vo.RateKey {
+	if checker.IsNil(key) {
+		return vo.RateKey{}
+	}
+
+	for _, strategy := range key.Strategies {
+		if checker.Equals(strategy, enum.RateKeyStrategyHeader) && checker.IsEmpty(key.Header) {
+			panic(errors.New("Limiter rate key strategy HEADER requires the header field!"))
+		} else if checker.Equals(strategy, enum.RateKeyStrategyJWTClaim) && checker.IsEmpty(key.Claim) {
+			panic(errors.New("Limiter rate key strategy JWT_CLAIM requires the claim field!"))
 		}
 	}
 
-	return vo.NewRate(every, capacity)
+	return vo.NewRateKey(key.Strategies, key.Header, key.Claim)
 }
 
 func buildCache(cache *dto.Cache, endpointCache *dto.EndpointCache) *vo.Cache {
@@ -201,7 +528,7 @@ func buildEndpointResponse(endpointResponse *dto.EndpointResponse) *vo.EndpointR
 	)
 }
 
-func buildBackends(middlewares map[string]dto.Backend, endpoint dto.Endpoint) []vo.Backend {
+func buildBackends(middlewares map[string]dto.Backend, transport *dto.Transport, endpoint dto.Endpoint) []vo.Backend {
 	var result []vo.Backend
 
 	propagateHeaderModifiers := &[]vo.Modifier{}
@@ -209,30 +536,30 @@ func buildBackends(middlewares map[string]dto.Backend, endpoint dto.Endpoint) []
 	propagateQueryModifiers := &[]vo.Modifier{}
 	propagateBodyModifiers := &[]vo.Modifier{}
 
-	result = append(result, buildMiddlewareBackend(endpoint.Beforewares, middlewares, enum.BackendTypeBeforeware,
-		propagateHeaderModifiers, propagateParamModifiers, propagateBodyModifiers, propagateQueryModifiers)...)
+	result = append(result, buildMiddlewareBackend(endpoint.Beforewares, middlewares, transport,
+		enum.BackendTypeBeforeware, propagateHeaderModifiers, propagateParamModifiers, propagateBodyModifiers, propagateQueryModifiers)...)
 
-	result = append(result, buildNormalBackend(endpoint.Backends, propagateHeaderModifiers, propagateParamModifiers,
-		propagateBodyModifiers, propagateQueryModifiers)...)
+	result = append(result, buildNormalBackend(endpoint.Backends, transport, propagateHeaderModifiers,
+		propagateParamModifiers, propagateBodyModifiers, propagateQueryModifiers)...)
 
-	result = append(result, buildMiddlewareBackend(endpoint.Afterwares, middlewares, enum.BackendTypeAfterware,
-		propagateHeaderModifiers, propagateParamModifiers, propagateBodyModifiers, propagateQueryModifiers)...)
+	result = append(result, buildMiddlewareBackend(endpoint.Afterwares, middlewares, transport,
+		enum.BackendTypeAfterware, propagateHeaderModifiers, propagateParamModifiers, propagateBodyModifiers, propagateQueryModifiers)...)
 
 	return result
 }
 
-func buildNormalBackend(backends []dto.Backend, propagateHeaderModifiers, propagateParamModifiers,
-	propagateBodyModifiers, propagateQueryModifiers *[]vo.Modifier) []vo.Backend {
+func buildNormalBackend(backends []dto.Backend, transport *dto.Transport, propagateHeaderModifiers,
+	propagateParamModifiers, propagateBodyModifiers, propagateQueryModifiers *[]vo.Modifier) []vo.Backend {
 	var result []vo.Backend
 	for _, backend := range backends {
-		result = append(result, buildBackend(backend, enum.BackendTypeNormal, propagateHeaderModifiers,
+		result = append(result, buildBackend(backend, enum.BackendTypeNormal, transport, propagateHeaderModifiers,
 			propagateParamModifiers, propagateBodyModifiers, propagateQueryModifiers))
 	}
 	return result
 }
 
-func buildMiddlewareBackend(middlewareKeys []string, middlewares map[string]dto.Backend, backendType enum.BackendType,
-	propagateHeaderModifiers, propagateParamModifiers, propagateBodyModifiers, propagateQueryModifiers *[]vo.Modifier,
+func buildMiddlewareBackend(middlewareKeys []string, middlewares map[string]dto.Backend, transport *dto.Transport,
+	backendType enum.BackendType, propagateHeaderModifiers, propagateParamModifiers, propagateBodyModifiers, propagateQueryModifiers *[]vo.Modifier,
 ) []vo.Backend {
 	var result []vo.Backend
 	for _, middlewareKey := range middlewareKeys {
@@ -240,8 +567,8 @@ func buildMiddlewareBackend(middlewareKeys []string, middlewares map[string]dto.
 		if !ok {
 			panic(errors.Newf("Middleware \"%s\" not configured on middlewares field!", middlewareKey))
 		}
-		result = append(result, buildBackend(middleware, backendType, propagateHeaderModifiers, propagateParamModifiers,
-			propagateBodyModifiers, propagateQueryModifiers))
+		result = append(result, buildBackend(middleware, backendType, transport, propagateHeaderModifiers,
+			propagateParamModifiers, propagateBodyModifiers, propagateQueryModifiers))
 	}
 	return result
 }
@@ -249,21 +576,204 @@ func buildMiddlewareBackend(middlewareKeys []string, middlewares map[string]dto.
 func buildBackend(
 	backend dto.Backend,
 	backendType enum.BackendType,
+	transport *dto.Transport,
 	propagateHeaderModifiers,
 	propagateParamModifiers,
 	propagateQueryModifiers,
 	propagateBodyModifiers *[]vo.Modifier,
 ) vo.Backend {
+	// the request is built first, and the fallback receives copies, so its propagated modifiers apply only to itself
+	request := buildBackendRequest(backend, propagateHeaderModifiers, propagateParamModifiers, propagateQueryModifiers,
+		propagateBodyModifiers)
+	fallback := buildBackendFallback(backend, transport, cloneModifiers(propagateHeaderModifiers),
+		cloneModifiers(propagateParamModifiers), cloneModifiers(propagateQueryModifiers),
+		cloneModifiers(propagateBodyModifiers))
+
 	return vo.NewBackend(
 		backendType,
 		backend.Hosts,
 		backend.Path,
 		backend.Method,
-		buildBackendRequest(backend, propagateHeaderModifiers, propagateParamModifiers, propagateQueryModifiers, propagateBodyModifiers),
+		buildBackendBalancer(backend),
+		buildBackendHealthCheck(backend),
+		buildBackendCircuitBreaker(backend),
+		buildBackendRetry(backend),
+		buildBackendConditions(backend, backend.OnlyIf),
+		buildBackendConditions(backend, backend.IgnoreIf),
+		buildBackendForeach(backend),
+		fallback,
+		buildTransport(transport, backend.Transport),
+		request,
 		buildBackendResponse(backend, backendType),
 	)
 }
 
+func cloneModifiers(modifiers *[]vo.Modifier) *[]vo.Modifier {
+	cloned := slices.Clone(*modifiers)
+	return &cloned
+}
+
+func buildBackendBalancer(backend dto.Backend) *vo.Balancer {
+	if checker.IsNil(backend.Balancer) {
+		return nil
+	}
+
+	balancer := backend.Balancer
+	if checker.IsNotEmpty(balancer.Weights) && checker.NotEquals(len(balancer.Weights), len(backend.Hosts)) {
+		panic(errors.Newf("Backend \"%s\" balancer weights must have the same size as hosts!", backend.Path))
+	} else if checker.Equals(balancer.HashKey, enum.BalancerHashKeyHeader) && checker.IsEmpty(balancer.HashHeader) {
+		panic(errors.Newf("Backend \"%s\" balancer hash-header is required when hash-key is HEADER!", backend.Path))
+	}
+
+	return vo.NewBalancer(balancer.Algorithm, backend.Hosts, balancer.Weights, balancer.HashKey, balancer.HashHeader)
+}
+
+func buildBackendHealthCheck(backend dto.Backend) *vo.HealthCheck {
+	if checker.IsNil(backend.HealthCheck) {
+		return nil
+	}
+
+	healthCheck := backend.HealthCheck
+	return vo.NewHealthCheck(healthCheck.Path, healthCheck.Interval, healthCheck.Timeout,
+		healthCheck.ExpectedStatusCodes, healthCheck.UnhealthyThreshold, healthCheck.HealthyThreshold)
+}
+
+func buildBackendCircuitBreaker(backend dto.Backend) *vo.CircuitBreaker {
+	if checker.IsNil(backend.CircuitBreaker) {
+		return nil
+	}
+
+	circuitBreaker := backend.CircuitBreaker
+	if checker.IsLessThanOrEqual(circuitBreaker.ErrorRatio, 0) &&
+		checker.IsLessThanOrEqual(circuitBreaker.ConsecutiveFailures, 0) {
+		panic(errors.Newf("Backend \"%s\" circuit-breaker requires error-ratio or consecutive-failures!",
+			backend.Path))
+	}
+
+	return vo.NewCircuitBreaker(circuitBreaker.Window, circuitBreaker.MinRequests, circuitBreaker.ErrorRatio,
+		circuitBreaker.ConsecutiveFailures, circuitBreaker.Cooldown, circuitBreaker.HalfOpenRequests,
+		buildFallbackResponse(circuitBreaker.Fallback))
+}
+
+func buildBackendRetry(backend dto.Backend) *vo.Retry {
+	if checker.IsNil(backend.Retry) {
+		return nil
+	}
+
+	retry := backend.Retry
+	return vo.NewRetry(retry.MaxAttempts, retry.StatusCodes, retry.OnConnectionError, retry.OnTimeout,
+		retry.InitialInterval, retry.MaxInterval, retry.Multiplier, retry.Budget, retry.AllowNonIdempotent)
+}
+
+func buildBackendConditions(backend dto.Backend, conditions []dto.BackendCondition) []vo.Condition {
+	var result []vo.Condition
+	for _, condition := range conditions {
+		if checker.Equals(condition.Operator, enum.ConditionOperatorEquals) ||
+			checker.Equals(condition.Operator, enum.ConditionOperatorNotEquals) {
+			if !checker.IsLengthEquals(condition.Expected, 1) {
+				panic(errors.Newf("Backend \"%s\" condition %s requires exactly one expected value!", backend.Path,
+					condition.Operator))
+			}
+		}
+
+		var expected []string
+		for _, value := range condition.Expected {
+			expected = append(expected, converter.ToString(value))
+		}
+		result = append(result, vo.NewCondition(condition.Value, condition.Operator, expected))
+	}
+	return result
+}
+
+func buildBackendForeach(backend dto.Backend) *vo.Foreach {
+	if checker.IsNil(backend.Foreach) {
+		return nil
+	}
+
+	foreach := backend.Foreach
+	if checker.IsEmpty(foreach.Path) {
+		panic(errors.Newf("Backend \"%s\" foreach path is required!", backend.Path))
+	}
+
+	return vo.NewForeach(foreach.Path, foreach.Concurrency, foreach.MaxItems)
+}
+
+func buildBackendFallback(
+	backend dto.Backend,
+	transport *dto.Transport,
+	propagateHeaderModifiers,
+	propagateParamModifiers,
+	propagateQueryModifiers,
+	propagateBodyModifiers *[]vo.Modifier,
+) *vo.Fallback {
+	if checker.IsNil(backend.Fallback) {
+		return nil
+	}
+
+	fallback := backend.Fallback
+	if checker.NonNil(fallback.Backend) {
+		if checker.IsGreaterThan(fallback.StatusCode, 0) || checker.IsNotEmpty(fallback.Header) ||
+			checker.NonNil(fallback.Body) {
+			panic(errors.Newf("Backend \"%s\" fallback must have a static response or a backend, not both!",
+				backend.Path))
+		}
+		fallbackBackend := buildBackend(*fallback.Backend, enum.BackendTypeNormal, transport, propagateHeaderModifiers,
+			propagateParamModifiers, propagateQueryModifiers, propagateBodyModifiers)
+		return vo.NewFallback(nil, &fallbackBackend)
+	}
+
+	// unlike the circuit breaker, the backend fallback degrades gracefully, so it succeeds by default
+	statusCode := fallback.StatusCode
+	if checker.IsLessThanOrEqual(statusCode, 0) {
+		statusCode = http.StatusOK
+	}
+	return vo.NewFallback(vo.NewFallbackResponse(statusCode, fallback.Header, fallback.Body), nil)
+}
+
+func buildTransport(transport, backendTransport *dto.Transport) vo.Transport {
+	var maxIdleConnsPerHost int
+	var idleConnTimeout, dialTimeout, tlsHandshakeTimeout, responseHeaderTimeout vo.Duration
+	keepAlive, http2 := true, true
+
+	// the backend transport overrides only the fields it configures, the rest comes from the global one
+	for _, config := range []*dto.Transport{transport, backendTransport} {
+		if checker.IsNil(config) {
+			continue
+		}
+		if checker.NonNil(config.MaxIdleConnsPerHost) {
+			maxIdleConnsPerHost = *config.MaxIdleConnsPerHost
+		}
+		if checker.NonNil(config.IdleConnTimeout) {
+			idleConnTimeout = *config.IdleConnTimeout
+		}
+		if checker.NonNil(config.DialTimeout) {
+			dialTimeout = *config.DialTimeout
+		}
+		if checker.NonNil(config.TLSHandshakeTimeout) {
+			tlsHandshakeTimeout = *config.TLSHandshakeTimeout
+		}
+		if checker.NonNil(config.ResponseHeaderTimeout) {
+			responseHeaderTimeout = *config.ResponseHeaderTimeout
+		}
+		if checker.NonNil(config.KeepAlive) {
+			keepAlive = *config.KeepAlive
+		}
+		if checker.NonNil(config.HTTP2) {
+			http2 = *config.HTTP2
+		}
+	}
+
+	return vo.NewTransport(maxIdleConnsPerHost, idleConnTimeout, dialTimeout, tlsHandshakeTimeout,
+		responseHeaderTimeout, keepAlive, http2)
+}
+
+func buildFallbackResponse(fallback *dto.FallbackResponse) *vo.FallbackResponse {
+	if checker.IsNil(fallback) {
+		return nil
+	}
+	return vo.NewFallbackResponse(fallback.StatusCode, fallback.Header, fallback.Body)
+}
+
 func buildBackendRequest(
 	backend dto.Backend,
 	propagateHeaderModifiers,
@@ -0,0 +1,330 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package factory
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"github.com/tech4works/gopen-gateway/internal/app/model/dto"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/enum"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/vo"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBuildTransport(t *testing.T) {
+	maxIdleConnsPerHost, otherMaxIdleConnsPerHost := 10, 20
+	dialTimeout := vo.NewDuration(5 * time.Second)
+	disabled := false
+
+	tests := []struct {
+		name             string
+		transport        *dto.Transport
+		backendTransport *dto.Transport
+		want             vo.Transport
+	}{
+		{
+			name: "without transports",
+			want: vo.NewTransport(0, 0, 0, 0, 0, true, true),
+		},
+		{
+			name:      "global transport",
+			transport: &dto.Transport{MaxIdleConnsPerHost: &maxIdleConnsPerHost, KeepAlive: &disabled},
+			want:      vo.NewTransport(10, 0, 0, 0, 0, false, true),
+		},
+		{
+			name:             "backend transport",
+			backendTransport: &dto.Transport{DialTimeout: &dialTimeout, HTTP2: &disabled},
+			want:             vo.NewTransport(0, 0, dialTimeout, 0, 0, true, false),
+		},
+		{
+			name:             "backend transport overrides configured fields",
+			transport:        &dto.Transport{MaxIdleConnsPerHost: &maxIdleConnsPerHost, DialTimeout: &dialTimeout},
+			backendTransport: &dto.Transport{MaxIdleConnsPerHost: &otherMaxIdleConnsPerHost},
+			want:             vo.NewTransport(20, 0, dialTimeout, 0, 0, true, true),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := buildTransport(tt.transport, tt.backendTransport); got != tt.want {
+				t.Errorf("buildTransport() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildHealthCheckErrs(t *testing.T) {
+	health := vo.NewHealthCheck("/health", 0, 0, nil, 0, 0)
+	status := vo.NewHealthCheck("/status", 0, 0, nil, 0, 0)
+
+	tests := []struct {
+		name     string
+		backends []vo.Backend
+		want     int
+	}{
+		{
+			name:     "same path",
+			backends: []vo.Backend{newTestHealthBackend("http://a", health), newTestHealthBackend("http://a", health)},
+			want:     0,
+		},
+		{
+			name:     "different hosts",
+			backends: []vo.Backend{newTestHealthBackend("http://a", health), newTestHealthBackend("http://b", status)},
+			want:     0,
+		},
+		{
+			name:     "without health-check",
+			backends: []vo.Backend{newTestHealthBackend("http://a", health), newTestHealthBackend("http://a", nil)},
+			want:     0,
+		},
+		{
+			name:     "conflicting paths",
+			backends: []vo.Backend{newTestHealthBackend("http://a", health), newTestHealthBackend("http://a", status)},
+			want:     1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoint := vo.NewEndpoint("/users", "GET", 0, vo.Limiter{}, nil, nil, nil, nil, nil, nil, tt.backends)
+			if got := buildHealthCheckErrs([]vo.Endpoint{endpoint}); len(got) != tt.want {
+				t.Errorf("buildHealthCheckErrs() = %v, want %d errors", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildJWT(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	rsaPEMFile := newTestAuthFile(t, newTestPEM("PUBLIC KEY", &rsaKey.PublicKey))
+	pkcs1PEMFile := newTestAuthFile(t, newTestPEM("RSA PUBLIC KEY", &rsaKey.PublicKey))
+	ecPEMFile := newTestAuthFile(t, newTestPEM("PUBLIC KEY", &ecKey.PublicKey))
+	jwksFile := newTestAuthFile(t, fmt.Sprintf(`{"keys":[%s,{"kty":"oct","kid":"key-2","k":"czNjcmV0"}]}`,
+		newTestRSAJSONWebKey("key-1", &rsaKey.PublicKey)))
+
+	tests := []struct {
+		name           string
+		jwt            *dto.JWT
+		wantAlgorithms []string
+		wantKeyIDs     []string
+		wantPanic      bool
+	}{
+		{
+			name:           "secret",
+			jwt:            &dto.JWT{Secret: "s3cret"},
+			wantAlgorithms: []string{"HS256"},
+			wantKeyIDs:     []string{""},
+		},
+		{
+			name:           "rsa pem",
+			jwt:            &dto.JWT{PEMFile: rsaPEMFile},
+			wantAlgorithms: []string{"RS256"},
+			wantKeyIDs:     []string{""},
+		},
+		{
+			name:           "pkcs1 rsa pem",
+			jwt:            &dto.JWT{PEMFile: pkcs1PEMFile},
+			wantAlgorithms: []string{"RS256"},
+			wantKeyIDs:     []string{""},
+		},
+		{
+			name:           "ec pem with configured algorithms",
+			jwt:            &dto.JWT{Algorithms: []string{"ES256"}, PEMFile: ecPEMFile},
+			wantAlgorithms: []string{"ES256"},
+			wantKeyIDs:     []string{""},
+		},
+		{
+			name:           "jwks",
+			jwt:            &dto.JWT{JWKSFile: jwksFile},
+			wantAlgorithms: []string{"RS256", "HS256"},
+			wantKeyIDs:     []string{"key-1", "key-2"},
+		},
+		{
+			name:           "secret and pem",
+			jwt:            &dto.JWT{Algorithms: []string{"HS512", "PS256"}, Secret: "s3cret", PEMFile: rsaPEMFile},
+			wantAlgorithms: []string{"HS512", "PS256"},
+			wantKeyIDs:     []string{"", ""},
+		},
+		{
+			name: "disabled",
+			jwt:  &dto.JWT{Disabled: true, Secret: "s3cret"},
+		},
+		{
+			name:      "without keys",
+			jwt:       &dto.JWT{Issuer: "https://auth.example.com"},
+			wantPanic: true,
+		},
+		{
+			name:      "invalid pem",
+			jwt:       &dto.JWT{PEMFile: newTestAuthFile(t, "invalid")},
+			wantPanic: true,
+		},
+		{
+			name:      "missing pem file",
+			jwt:       &dto.JWT{PEMFile: filepath.Join(t.TempDir(), "missing.pem")},
+			wantPanic: true,
+		},
+		{
+			name:      "invalid jwks",
+			jwt:       &dto.JWT{JWKSFile: newTestAuthFile(t, `{"keys":`)},
+			wantPanic: true,
+		},
+		{
+			name:      "jwks without keys",
+			jwt:       &dto.JWT{JWKSFile: newTestAuthFile(t, `{"keys":[]}`)},
+			wantPanic: true,
+		},
+		{
+			name:      "unsupported jwk kty",
+			jwt:       &dto.JWT{JWKSFile: newTestAuthFile(t, `{"keys":[{"kty":"OKP","crv":"Ed25519"}]}`)},
+			wantPanic: true,
+		},
+		{
+			name:      "hs256 with only a rsa pem",
+			jwt:       &dto.JWT{Algorithms: []string{"HS256"}, PEMFile: rsaPEMFile},
+			wantPanic: true,
+		},
+		{
+			name:      "es256 with only a secret",
+			jwt:       &dto.JWT{Algorithms: []string{"HS256", "ES256"}, Secret: "s3cret"},
+			wantPanic: true,
+		},
+		{
+			name:      "none algorithm",
+			jwt:       &dto.JWT{Algorithms: []string{"none"}, Secret: "s3cret"},
+			wantPanic: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); (r != nil) != tt.wantPanic {
+					t.Errorf("buildJWT() panic = %v, wantPanic %v", r, tt.wantPanic)
+				}
+			}()
+
+			got := buildJWT(&dto.Auth{JWT: tt.jwt}, nil)
+			if tt.wantPanic {
+				return
+			} else if tt.jwt.Disabled {
+				if got != nil {
+					t.Errorf("buildJWT() = %v, want nil", got)
+				}
+				return
+			}
+
+			if !reflect.DeepEqual(got.Algorithms(), tt.wantAlgorithms) {
+				t.Errorf("Algorithms() = %v, want %v", got.Algorithms(), tt.wantAlgorithms)
+			}
+			var keyIDs []string
+			for _, key := range got.Keys() {
+				keyIDs = append(keyIDs, key.ID())
+			}
+			if !reflect.DeepEqual(keyIDs, tt.wantKeyIDs) {
+				t.Errorf("Keys() ids = %v, want %v", keyIDs, tt.wantKeyIDs)
+			}
+		})
+	}
+}
+
+func newTestHealthBackend(host string, healthCheck *vo.HealthCheck) vo.Backend {
+	return vo.NewBackend(enum.BackendTypeNormal, []string{host}, "/users", "GET", nil, healthCheck, nil, nil, nil, nil,
+		nil, nil, vo.Transport{}, nil, nil)
+}
+
+func TestBuildSecurityCors(t *testing.T) {
+	enabled, disabled := true, false
+
+	tests := []struct {
+		name                 string
+		securityCors         *dto.SecurityCors
+		endpointSecurityCors *dto.SecurityCors
+		wantPanic            bool
+	}{
+		{
+			name:         "credentials with explicit origins",
+			securityCors: &dto.SecurityCors{AllowOrigins: []string{"https://app.example.com"}, AllowCredentials: &enabled},
+		},
+		{
+			name:         "any origin without credentials",
+			securityCors: &dto.SecurityCors{AllowOrigins: []string{"*"}, AllowCredentials: &disabled},
+		},
+		{
+			name:         "credentials without origins",
+			securityCors: &dto.SecurityCors{AllowCredentials: &enabled},
+			wantPanic:    true,
+		},
+		{
+			name: "credentials with wildcard origin",
+			securityCors: &dto.SecurityCors{
+				AllowOrigins:     []string{"https://app.example.com", "*"},
+				AllowCredentials: &enabled,
+			},
+			wantPanic: true,
+		},
+		{
+			name:                 "endpoint credentials with global wildcard origin",
+			securityCors:         &dto.SecurityCors{AllowOrigins: []string{"*"}},
+			endpointSecurityCors: &dto.SecurityCors{AllowCredentials: &enabled},
+			wantPanic:            true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); (r != nil) != tt.wantPanic {
+					t.Errorf("buildSecurityCors() panic = %v, wantPanic %v", r, tt.wantPanic)
+				}
+			}()
+			buildSecurityCors(tt.securityCors, tt.endpointSecurityCors)
+		})
+	}
+}
+
+func newTestAuthFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "auth")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func newTestPEM(blockType string, publicKey any) string {
+	var bs []byte
+	if rsaPublicKey, ok := publicKey.(*rsa.PublicKey); ok && blockType == "RSA PUBLIC KEY" {
+		bs = x509.MarshalPKCS1PublicKey(rsaPublicKey)
+	} else {
+		bs, _ = x509.MarshalPKIXPublicKey(publicKey)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: bs}))
+}
+
+func newTestRSAJSONWebKey(kid string, publicKey *rsa.PublicKey) string {
+	n := base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes())
+	e := base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes())
+	return fmt.Sprintf(`{"kty":"RSA","kid":"%s","n":"%s","e":"%s"}`, kid, n, e)
+}
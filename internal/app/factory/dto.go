@@ -17,6 +17,9 @@
 package factory
 
 import (
+	"github.com/tech4works/checker"
+	"github.com/tech4works/converter"
+	"github.com/tech4works/errors"
 	"github.com/tech4works/gopen-gateway/internal/app"
 	"github.com/tech4works/gopen-gateway/internal/app/model/dto"
 )
@@ -30,3 +33,35 @@ func BuildExecuteEndpoint(ctx app.Context) dto.ExecuteEndpoint {
 		Request:  ctx.Request(),
 	}
 }
+
+func BuildPurgeCache(ctx app.Context) (*dto.PurgeCache, error) {
+	var purgeCache dto.PurgeCache
+	if err := buildCommand(ctx, &purgeCache); checker.NonNil(err) {
+		return nil, err
+	} else if checker.IsEmpty(purgeCache.Pattern) {
+		return nil, errors.New("Purge cache requires the pattern field!")
+	}
+	return &purgeCache, nil
+}
+
+func BuildToggleEndpoint(ctx app.Context) (*dto.ToggleEndpoint, error) {
+	var toggleEndpoint dto.ToggleEndpoint
+	if err := buildCommand(ctx, &toggleEndpoint); checker.NonNil(err) {
+		return nil, err
+	} else if checker.IsEmpty(toggleEndpoint.Method) || checker.IsEmpty(toggleEndpoint.Path) {
+		return nil, errors.New("Toggle endpoint requires the method and path fields!")
+	}
+	return &toggleEndpoint, nil
+}
+
+func buildCommand(ctx app.Context, dest any) error {
+	if !ctx.Request().HasBody() {
+		return errors.New("Request body is required!")
+	}
+
+	bs, err := ctx.Request().Body().Bytes()
+	if checker.NonNil(err) {
+		return err
+	}
+	return converter.ToDestWithErr(bs, dest)
+}
@@ -19,11 +19,22 @@ package factory
 import (
 	"github.com/tech4works/checker"
 	"github.com/tech4works/gopen-gateway/internal/app/model/dto"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/vo"
 )
 
 func BuildSettingView(gopen dto.Gopen) dto.SettingView {
+	// credentials don't leave the gateway, so the store, tracer, admin, auth and consumers are hidden
 	copied := gopen
 	copied.Store = nil
+	copied.Tracer = nil
+	copied.Admin = nil
+	copied.Auth = nil
+	copied.Consumers = nil
+	copied.Endpoints = nil
+	for _, endpoint := range gopen.Endpoints {
+		endpoint.Auth = nil
+		copied.Endpoints = append(copied.Endpoints, endpoint)
+	}
 
 	return dto.SettingView{
 		Version:      "v1.0.0",
@@ -37,6 +48,126 @@ func BuildSettingView(gopen dto.Gopen) dto.SettingView {
 	}
 }
 
+func BuildRuntimeGopen(gopen dto.Gopen) dto.Gopen {
+	// the runtime file keeps the whole config for troubleshooting, only the secrets are removed
+	copied := gopen
+	if checker.NonNil(gopen.Store) && checker.NonNil(gopen.Store.Redis) {
+		redis := *gopen.Store.Redis
+		redis.Password = ""
+		copied.Store = &dto.Store{Redis: &redis}
+	}
+	if checker.NonNil(gopen.Tracer) && checker.NonNil(gopen.Tracer.OTLP) {
+		tracer, otlp := *gopen.Tracer, *gopen.Tracer.OTLP
+		otlp.Headers = nil
+		tracer.OTLP = &otlp
+		copied.Tracer = &tracer
+	}
+	if checker.NonNil(gopen.Admin) {
+		admin := *gopen.Admin
+		admin.Token = ""
+		copied.Admin = &admin
+	}
+	copied.Auth = buildRuntimeAuth(gopen.Auth)
+	copied.Endpoints = nil
+	for _, endpoint := range gopen.Endpoints {
+		endpoint.Auth = buildRuntimeAuth(endpoint.Auth)
+		copied.Endpoints = append(copied.Endpoints, endpoint)
+	}
+	return copied
+}
+
+func BuildHealthHostsView(report []vo.HostHealth) dto.HealthHostsView {
+	result := dto.HealthHostsView{
+		Hosts: []dto.HostHealthView{},
+	}
+	for _, hostHealth := range report {
+		status := "UP"
+		if hostHealth.IsDown() {
+			status = "DOWN"
+			result.Down++
+		} else {
+			result.Up++
+		}
+		result.Hosts = append(result.Hosts, dto.HostHealthView{
+			Host:      hostHealth.Host(),
+			Status:    status,
+			Failures:  hostHealth.Failures(),
+			Message:   hostHealth.Message(),
+			CheckedAt: hostHealth.CheckedAt(),
+		})
+	}
+	return result
+}
+
+func BuildReadinessView(report vo.ReadinessReport) dto.ReadinessView {
+	result := dto.ReadinessView{
+		Status:    "READY",
+		Reloading: report.IsReloading(),
+		Checks:    []dto.DependencyCheckView{},
+	}
+	if !report.IsReady() {
+		result.Status = "NOT_READY"
+	}
+	for _, check := range report.Checks() {
+		status := "UP"
+		if check.IsDown() {
+			status = "DOWN"
+		}
+		result.Checks = append(result.Checks, dto.DependencyCheckView{
+			Kind:     check.Kind(),
+			Name:     check.Name(),
+			Status:   status,
+			Message:  check.Message(),
+			Duration: vo.NewDuration(check.Duration()),
+		})
+	}
+	return result
+}
+
+func BuildRouteView(config dto.Endpoint, endpoint *vo.Endpoint, enabled bool) dto.RouteView {
+	// the endpoint auth may carry credentials, so it is hidden like in the settings
+	config.Auth = nil
+
+	var cacheDuration vo.Duration
+	if !endpoint.NoCache() {
+		cacheDuration = endpoint.Cache().Duration()
+	}
+	return dto.RouteView{
+		Method:        endpoint.Method(),
+		Path:          endpoint.Path(),
+		Enabled:       enabled,
+		Timeout:       endpoint.Timeout(),
+		CacheDuration: cacheDuration,
+		SecurityCors:  endpoint.HasSecurityCors(),
+		JWT:           endpoint.HasJWT(),
+		APIKey:        endpoint.HasAPIKey(),
+		Beforewares:   endpoint.CountBeforewares(),
+		Afterwares:    endpoint.CountAfterwares(),
+		Backends:      endpoint.CountBackends(),
+		Config:        config,
+	}
+}
+
+func BuildPurgeCacheView(pattern string, keys int) dto.PurgeCacheView {
+	return dto.PurgeCacheView{
+		Pattern: pattern,
+		Keys:    keys,
+	}
+}
+
+func BuildResetLimiterView(buckets int) dto.ResetLimiterView {
+	return dto.ResetLimiterView{
+		Buckets: buckets,
+	}
+}
+
+func BuildLimiterStatsView(stats vo.LimiterStats) dto.LimiterStatsView {
+	return dto.LimiterStatsView{
+		Buckets:   stats.Buckets(),
+		Evictions: stats.Evictions(),
+	}
+}
+
 func countEndpoints(gopen dto.Gopen) int {
 	return len(gopen.Endpoints)
 }
@@ -54,3 +185,14 @@ func countBackends(gopen dto.Gopen) (count int) {
 	}
 	return count
 }
+
+func buildRuntimeAuth(auth *dto.Auth) *dto.Auth {
+	if checker.IsNil(auth) || checker.IsNil(auth.JWT) {
+		return auth
+	}
+
+	copied, jwt := *auth, *auth.JWT
+	jwt.Secret = ""
+	copied.JWT = &jwt
+	return &copied
+}
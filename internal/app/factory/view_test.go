@@ -0,0 +1,65 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package factory
+
+import (
+	"encoding/json"
+	"github.com/tech4works/gopen-gateway/internal/app/model/dto"
+	"strings"
+	"testing"
+)
+
+func TestBuildRuntimeGopen(t *testing.T) {
+	gopen := dto.Gopen{
+		Store: &dto.Store{Redis: &dto.Redis{Address: "localhost:6379", Password: "redis-s3cret"}},
+		Tracer: &dto.Tracer{
+			ServiceName: "gateway",
+			OTLP:        &dto.OTLP{Endpoint: "localhost:4318", Headers: map[string]string{"Authorization": "otlp-s3cret"}},
+		},
+		Admin: &dto.Admin{Port: 9090, Token: "admin-s3cret"},
+		Auth:  &dto.Auth{JWT: &dto.JWT{Secret: "jwt-s3cret", Issuer: "https://auth.example.com"}},
+		Endpoints: []dto.Endpoint{
+			{Path: "/users", Method: "GET", Auth: &dto.Auth{JWT: &dto.JWT{Secret: "endpoint-s3cret"}}},
+			{Path: "/orders", Method: "GET"},
+		},
+	}
+
+	got := BuildRuntimeGopen(gopen)
+
+	bs, err := json.Marshal(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	runtimeJson := string(bs)
+	for _, secret := range []string{"redis-s3cret", "otlp-s3cret", "admin-s3cret", "jwt-s3cret", "endpoint-s3cret"} {
+		if strings.Contains(runtimeJson, secret) {
+			t.Errorf("BuildRuntimeGopen() contains %s: %s", secret, runtimeJson)
+		}
+	}
+	for _, value := range []string{"localhost:6379", "localhost:4318", "9090", "https://auth.example.com", "/orders"} {
+		if !strings.Contains(runtimeJson, value) {
+			t.Errorf("BuildRuntimeGopen() lost %s: %s", value, runtimeJson)
+		}
+	}
+
+	// the config is still used to build the gateway, so it must keep its secrets
+	if gopen.Store.Redis.Password != "redis-s3cret" || gopen.Tracer.OTLP.Headers == nil ||
+		gopen.Admin.Token != "admin-s3cret" || gopen.Auth.JWT.Secret != "jwt-s3cret" ||
+		gopen.Endpoints[0].Auth.JWT.Secret != "endpoint-s3cret" {
+		t.Errorf("BuildRuntimeGopen() changed the config: %+v", gopen)
+	}
+}
@@ -27,6 +27,7 @@ import (
 type Boot interface {
 	Init() *dto.Gopen
 	Start(gopen *dto.Gopen)
+	Reload() error
 	Stop()
 }
 
@@ -59,7 +60,9 @@ type Context interface {
 	Gopen() *vo.Gopen
 	Endpoint() *vo.Endpoint
 	Request() *vo.HTTPRequest
+	WithRequest(request *vo.HTTPRequest)
 	Response() *vo.HTTPResponse
+	AddResponseHeader(header vo.Header)
 	Write(response *vo.HTTPResponse)
 	WriteCacheResponse(cacheResponse *vo.CacheResponse)
 	WriteError(code int, err error)
@@ -69,7 +72,8 @@ type Context interface {
 }
 
 type HTTPClient interface {
-	MakeRequest(ctx context.Context, request *vo.HTTPBackendRequest) (*http.Response, error)
+	MakeRequest(ctx context.Context, transport vo.Transport, request *vo.HTTPBackendRequest) (*http.Response, error)
+	CloseIdleConnections()
 }
 
 type HTTPLog interface {
@@ -96,3 +100,33 @@ type BackendLog interface {
 	PrintErrorf(executeData dto.ExecuteEndpoint, backend *vo.Backend, request *vo.HTTPBackendRequest, format string, msg ...any)
 	PrintError(executeData dto.ExecuteEndpoint, backend *vo.Backend, request *vo.HTTPBackendRequest, msg ...any)
 }
+
+type Metrics interface {
+	IncrementInFlight(endpoint *vo.Endpoint)
+	DecrementInFlight(endpoint *vo.Endpoint)
+	ObserveRequest(endpoint *vo.Endpoint, statusCode vo.StatusCode, duration time.Duration)
+	IncrementBackendInFlight(host string)
+	DecrementBackendInFlight(host string)
+	ObserveBackendRequest(host string, statusCode vo.StatusCode, duration time.Duration, err error)
+	IncrementCacheHit(endpoint *vo.Endpoint)
+	IncrementCacheMiss(endpoint *vo.Endpoint)
+	IncrementCacheWrite(endpoint *vo.Endpoint)
+	IncrementLimiterRejection(endpoint *vo.Endpoint, reason string)
+	IncrementTimeout(endpoint *vo.Endpoint)
+	IncrementPanicRecovered(endpoint *vo.Endpoint)
+	Export() (string, error)
+}
+
+type Tracer interface {
+	Handler(handler http.Handler) http.Handler
+	RoundTripper(ctx context.Context, roundTripper http.RoundTripper) http.RoundTripper
+	StartSpan(ctx context.Context, name, spanType string) (context.Context, Span)
+	TraceID(ctx context.Context) string
+	CaptureError(ctx context.Context, err error)
+	Shutdown(ctx context.Context) error
+}
+
+type Span interface {
+	SetLabel(key string, value any)
+	End()
+}
@@ -0,0 +1,53 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package middleware
+
+import (
+	"github.com/tech4works/checker"
+	"github.com/tech4works/gopen-gateway/internal/app"
+	"github.com/tech4works/gopen-gateway/internal/domain/mapper"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/vo"
+	"github.com/tech4works/gopen-gateway/internal/domain/service"
+	"net/http"
+)
+
+type adminMiddleware struct {
+	service service.Auth
+	token   string
+}
+
+type Admin interface {
+	Do(ctx app.Context)
+}
+
+func NewAdmin(service service.Auth, token string) Admin {
+	return adminMiddleware{
+		service: service,
+		token:   token,
+	}
+}
+
+func (a adminMiddleware) Do(ctx app.Context) {
+	err := a.service.AuthenticateAdmin(a.token, ctx.Request())
+	if checker.NonNil(err) {
+		ctx.AddResponseHeader(vo.NewHeader(map[string][]string{mapper.WWWAuthenticate: {"Bearer"}}))
+		ctx.WriteError(http.StatusUnauthorized, err)
+		return
+	}
+
+	ctx.Next()
+}
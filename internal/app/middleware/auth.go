@@ -0,0 +1,75 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package middleware
+
+import (
+	"github.com/tech4works/checker"
+	"github.com/tech4works/errors"
+	"github.com/tech4works/gopen-gateway/internal/app"
+	"github.com/tech4works/gopen-gateway/internal/domain/mapper"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/vo"
+	"github.com/tech4works/gopen-gateway/internal/domain/service"
+	"net/http"
+)
+
+type authMiddleware struct {
+	service service.Auth
+}
+
+type Auth interface {
+	Do(ctx app.Context)
+}
+
+func NewAuth(service service.Auth) Auth {
+	return authMiddleware{
+		service: service,
+	}
+}
+
+func (a authMiddleware) Do(ctx app.Context) {
+	if ctx.Endpoint().HasAPIKey() {
+		consumer, err := a.service.AuthenticateAPIKey(ctx.Gopen(), ctx.Endpoint(), ctx.Request())
+		if checker.NonNil(err) {
+			a.writeError(ctx, err, "")
+			return
+		}
+		ctx.WithRequest(ctx.Request().WithConsumer(consumer))
+	}
+
+	if ctx.Endpoint().HasJWT() {
+		claims, err := a.service.AuthenticateJWT(ctx.Endpoint().JWT(), ctx.Request())
+		if checker.NonNil(err) {
+			a.writeError(ctx, err, "Bearer")
+			return
+		}
+		ctx.WithRequest(ctx.Request().WithJWTClaims(claims))
+	}
+
+	ctx.Next()
+}
+
+func (a authMiddleware) writeError(ctx app.Context, err error, challenge string) {
+	if errors.Is(err, mapper.ErrForbidden) {
+		ctx.WriteError(http.StatusForbidden, err)
+		return
+	}
+
+	if checker.IsNotEmpty(challenge) {
+		ctx.AddResponseHeader(vo.NewHeader(map[string][]string{mapper.WWWAuthenticate: {challenge}}))
+	}
+	ctx.WriteError(http.StatusUnauthorized, err)
+}
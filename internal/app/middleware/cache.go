@@ -25,16 +25,18 @@ import (
 type cacheMiddleware struct {
 	service service.Cache
 	log     app.EndpointLog
+	metrics app.Metrics
 }
 
 type Cache interface {
 	Do(ctx app.Context)
 }
 
-func NewCache(service service.Cache, log app.EndpointLog) Cache {
+func NewCache(service service.Cache, log app.EndpointLog, metrics app.Metrics) Cache {
 	return cacheMiddleware{
 		service: service,
 		log:     log,
+		metrics: metrics,
 	}
 }
 
@@ -44,19 +46,29 @@ func (c cacheMiddleware) Do(ctx app.Context) {
 		return
 	}
 
-	response, err := c.service.Read(ctx.Context(), ctx.Endpoint().Cache(), ctx.Request())
+	cache := ctx.Endpoint().Cache()
+	response, err := c.service.Read(ctx.Context(), cache, ctx.Request())
 	if checker.NonNil(err) {
 		c.printWarnf(ctx, "Error read cache err: %s", err)
 	} else if checker.NonNil(response) {
+		c.metrics.IncrementCacheHit(ctx.Endpoint())
 		ctx.WriteCacheResponse(response)
 		return
+	} else if c.service.CanRead(cache, ctx.Request()) {
+		c.metrics.IncrementCacheMiss(ctx.Endpoint())
 	}
 
 	ctx.Next()
 
-	err = c.service.Write(ctx.Context(), ctx.Endpoint().Cache(), ctx.Request(), ctx.Response())
+	if !c.service.CanWrite(cache, ctx.Request(), ctx.Response()) {
+		return
+	}
+
+	err = c.service.Write(ctx.Context(), cache, ctx.Request(), ctx.Response())
 	if checker.NonNil(err) {
 		c.printWarnf(ctx, "Error write cache err: %s", err)
+	} else {
+		c.metrics.IncrementCacheWrite(ctx.Endpoint())
 	}
 }
 
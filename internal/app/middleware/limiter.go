@@ -21,37 +21,68 @@ import (
 	"github.com/tech4works/errors"
 	"github.com/tech4works/gopen-gateway/internal/app"
 	"github.com/tech4works/gopen-gateway/internal/domain/mapper"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/vo"
 	"github.com/tech4works/gopen-gateway/internal/domain/service"
 	"net/http"
 )
 
 type limiterMiddleware struct {
 	service service.Limiter
+	log     app.EndpointLog
+	metrics app.Metrics
 }
 
 type Limiter interface {
 	Do(ctx app.Context)
 }
 
-func NewLimiter(service service.Limiter) Limiter {
+func NewLimiter(service service.Limiter, log app.EndpointLog, metrics app.Metrics) Limiter {
 	return limiterMiddleware{
 		service: service,
+		log:     log,
+		metrics: metrics,
 	}
 }
 
 func (l limiterMiddleware) Do(ctx app.Context) {
-	err := l.service.AllowRate(ctx.Request(), ctx.Endpoint().Limiter().Rate())
+	state, err := l.service.AllowRate(ctx.Context(), ctx.Endpoint(), ctx.Request())
+	if errors.Is(err, mapper.ErrLimiterStore) {
+		l.printWarnf(ctx, "Error limiter store, using local limiter err: %s", err)
+		state, err = l.service.AllowRateLocal(ctx.Endpoint(), ctx.Request())
+	}
+	if checker.NonNil(state) && ctx.Endpoint().Limiter().Rate().Headers() {
+		ctx.AddResponseHeader(l.service.BuildRateHeader(state))
+	}
 	if checker.NonNil(err) {
+		l.metrics.IncrementLimiterRejection(ctx.Endpoint(), "rate")
 		ctx.WriteError(http.StatusTooManyRequests, err)
 		return
 	}
 
 	err = l.service.AllowSize(ctx.Request(), ctx.Endpoint().Limiter())
 	if errors.Contains(err, mapper.ErrPayloadTooLarge) {
+		l.metrics.IncrementLimiterRejection(ctx.Endpoint(), "body-size")
 		ctx.WriteError(http.StatusRequestEntityTooLarge, err)
+		return
 	} else if errors.Contains(err, mapper.ErrHeaderTooLarge) {
+		l.metrics.IncrementLimiterRejection(ctx.Endpoint(), "header-size")
 		ctx.WriteError(http.StatusRequestHeaderFieldsTooLarge, err)
-	} else {
-		ctx.Next()
+		return
 	}
+
+	release, err := l.service.AcquireConcurrent(ctx.Context(), ctx.Gopen(), ctx.Endpoint())
+	if checker.NonNil(err) {
+		// the request was shed at a peak, so the client can try again in a moment
+		l.metrics.IncrementLimiterRejection(ctx.Endpoint(), "max-concurrent")
+		ctx.AddResponseHeader(vo.NewHeader(map[string][]string{mapper.RetryAfter: {"1"}}))
+		ctx.WriteError(http.StatusServiceUnavailable, err)
+		return
+	}
+	defer release()
+
+	ctx.Next()
+}
+
+func (l limiterMiddleware) printWarnf(ctx app.Context, format string, msg ...any) {
+	l.log.PrintWarnf(ctx.Endpoint(), ctx.Request(), ctx.ClientIP(), ctx.TraceID(), format, msg...)
 }
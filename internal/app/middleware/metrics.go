@@ -0,0 +1,47 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package middleware
+
+import (
+	"github.com/tech4works/checker"
+	"github.com/tech4works/gopen-gateway/internal/app"
+)
+
+type metricsMiddleware struct {
+	metrics app.Metrics
+}
+
+type Metrics interface {
+	Do(ctx app.Context)
+}
+
+func NewMetrics(metrics app.Metrics) Metrics {
+	return metricsMiddleware{
+		metrics: metrics,
+	}
+}
+
+func (m metricsMiddleware) Do(ctx app.Context) {
+	m.metrics.IncrementInFlight(ctx.Endpoint())
+	defer m.metrics.DecrementInFlight(ctx.Endpoint())
+
+	ctx.Next()
+
+	if checker.NonNil(ctx.Response()) {
+		m.metrics.ObserveRequest(ctx.Endpoint(), ctx.Response().StatusCode(), ctx.Duration())
+	}
+}
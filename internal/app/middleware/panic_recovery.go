@@ -20,22 +20,25 @@ import (
 	"github.com/tech4works/checker"
 	"github.com/tech4works/errors"
 	"github.com/tech4works/gopen-gateway/internal/app"
-	"go.elastic.co/apm/v2"
 	"net/http"
 	"runtime/debug"
 )
 
 type panicRecoveryMiddleware struct {
-	log app.EndpointLog
+	log     app.EndpointLog
+	metrics app.Metrics
+	tracer  app.Tracer
 }
 
 type PanicRecovery interface {
 	Do(ctx app.Context)
 }
 
-func NewPanicRecovery(log app.EndpointLog) PanicRecovery {
+func NewPanicRecovery(log app.EndpointLog, metrics app.Metrics, tracer app.Tracer) PanicRecovery {
 	return panicRecoveryMiddleware{
-		log: log,
+		log:     log,
+		metrics: metrics,
+		tracer:  tracer,
 	}
 }
 
@@ -43,15 +46,10 @@ func (p panicRecoveryMiddleware) Do(ctx app.Context) {
 	defer func() {
 		if r := recover(); checker.NonNil(r) {
 			p.printErrorf(ctx, "%s:%s", r, string(debug.Stack()))
+			p.metrics.IncrementPanicRecovered(ctx.Endpoint())
 
 			err := errors.New("Gateway panic error occurred! detail:", r)
-
-			tx := apm.TransactionFromContext(ctx.Context())
-			if checker.NonNil(tx) {
-				apmErr := apm.DefaultTracer().NewError(err)
-				apmErr.SetTransaction(tx)
-				apmErr.Send()
-			}
+			p.tracer.CaptureError(ctx.Context(), err)
 
 			ctx.WriteError(http.StatusInternalServerError, err)
 		}
@@ -18,7 +18,9 @@ package middleware
 
 import (
 	"github.com/tech4works/checker"
+	"github.com/tech4works/errors"
 	"github.com/tech4works/gopen-gateway/internal/app"
+	"github.com/tech4works/gopen-gateway/internal/domain/mapper"
 	"github.com/tech4works/gopen-gateway/internal/domain/service"
 	"net/http"
 )
@@ -29,6 +31,7 @@ type securityCorsMiddleware struct {
 
 type SecurityCors interface {
 	Do(ctx app.Context)
+	Preflight(ctx app.Context)
 }
 
 func NewSecurityCors(service service.SecurityCors) SecurityCors {
@@ -38,16 +41,32 @@ func NewSecurityCors(service service.SecurityCors) SecurityCors {
 }
 
 func (s securityCorsMiddleware) Do(ctx app.Context) {
-	if !ctx.Gopen().HasSecurityCors() {
+	securityCors := ctx.Endpoint().SecurityCors()
+	if !ctx.Endpoint().HasSecurityCors() {
 		ctx.Next()
-	} else if err := s.service.ValidateOrigin(ctx.Gopen().SecurityCors(), ctx.Request()); checker.NonNil(err) {
+	} else if err := s.service.ValidateOrigin(securityCors, ctx.Request()); checker.NonNil(err) {
 		ctx.WriteError(http.StatusForbidden, err)
-	} else if err = s.service.ValidateMethod(ctx.Gopen().SecurityCors(), ctx.Request()); checker.NonNil(err) {
+	} else if err = s.service.ValidateMethod(securityCors, ctx.Request()); checker.NonNil(err) {
 		ctx.WriteError(http.StatusForbidden, err)
-	} else if err = s.service.ValidateHeaders(ctx.Gopen().SecurityCors(), ctx.Request()); checker.NonNil(err) {
+	} else if err = s.service.ValidateHeaders(securityCors, ctx.Request()); checker.NonNil(err) {
 		ctx.WriteError(http.StatusForbidden, err)
 	} else {
+		ctx.AddResponseHeader(s.service.BuildHeader(securityCors, ctx.Request()))
 		ctx.Next()
 	}
+}
 
+func (s securityCorsMiddleware) Preflight(ctx app.Context) {
+	// the preflight route is shared by the path, so the security-cors comes from the endpoint of the requested method
+	method := ctx.Request().Header().Get(mapper.AccessControlRequestMethod)
+	endpoint, ok := ctx.Gopen().FindEndpoint(ctx.Endpoint().Path(), method)
+	if !ok || !endpoint.HasSecurityCors() {
+		ctx.WriteError(http.StatusForbidden, errors.Newf("Method %s not mapped on path %s", method,
+			ctx.Endpoint().Path()))
+	} else if err := s.service.ValidatePreflight(endpoint.SecurityCors(), ctx.Request()); checker.NonNil(err) {
+		ctx.WriteError(http.StatusForbidden, err)
+	} else {
+		ctx.AddResponseHeader(s.service.BuildPreflightHeader(endpoint.SecurityCors(), ctx.Request()))
+		ctx.WriteStatusCode(http.StatusNoContent)
+	}
 }
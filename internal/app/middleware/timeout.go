@@ -24,14 +24,17 @@ import (
 )
 
 type timeoutMiddleware struct {
+	metrics app.Metrics
 }
 
 type Timeout interface {
 	Do(ctx app.Context)
 }
 
-func NewTimeout() Timeout {
-	return timeoutMiddleware{}
+func NewTimeout(metrics app.Metrics) Timeout {
+	return timeoutMiddleware{
+		metrics: metrics,
+	}
 }
 
 func (t timeoutMiddleware) Do(ctx app.Context) {
@@ -50,6 +53,9 @@ func (t timeoutMiddleware) Do(ctx app.Context) {
 	select {
 	case <-finishChan:
 	case <-ctx.Done():
+		if errors.Is(timeoutCtx.Err(), context.DeadlineExceeded) {
+			t.metrics.IncrementTimeout(ctx.Endpoint())
+		}
 		ctx.WriteError(http.StatusGatewayTimeout, errors.New("gateway timeout:", timeout.String()))
 	}
 }
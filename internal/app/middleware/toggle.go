@@ -0,0 +1,48 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package middleware
+
+import (
+	"github.com/tech4works/gopen-gateway/internal/app"
+	"github.com/tech4works/gopen-gateway/internal/domain/mapper"
+	"github.com/tech4works/gopen-gateway/internal/domain/service"
+	"net/http"
+)
+
+type toggleMiddleware struct {
+	service service.Toggle
+}
+
+type Toggle interface {
+	Do(ctx app.Context)
+}
+
+func NewToggle(service service.Toggle) Toggle {
+	return toggleMiddleware{
+		service: service,
+	}
+}
+
+func (t toggleMiddleware) Do(ctx app.Context) {
+	if t.service.IsDisabled(ctx.Endpoint()) {
+		err := mapper.NewErrEndpointDisabled(ctx.Endpoint().Method(), ctx.Endpoint().Path())
+		ctx.WriteError(http.StatusServiceUnavailable, err)
+		return
+	}
+
+	ctx.Next()
+}
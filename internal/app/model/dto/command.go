@@ -27,3 +27,13 @@ type ExecuteEndpoint struct {
 	Endpoint *vo.Endpoint
 	Request  *vo.HTTPRequest
 }
+
+type PurgeCache struct {
+	Pattern string `json:"pattern"`
+}
+
+type ToggleEndpoint struct {
+	Method  string `json:"method"`
+	Path    string `json:"path"`
+	Enabled bool   `json:"enabled"`
+}
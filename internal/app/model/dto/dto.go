@@ -27,10 +27,17 @@ type Gopen struct {
 	Version      string             `json:"version,omitempty"`
 	HotReload    bool               `json:"hot-reload,omitempty"`
 	Store        *Store             `json:"store,omitempty"`
+	Tracer       *Tracer            `json:"tracer,omitempty"`
+	Log          *Log               `json:"log,omitempty"`
+	Readiness    *Readiness         `json:"readiness,omitempty"`
+	Admin        *Admin             `json:"admin,omitempty"`
 	Timeout      vo.Duration        `json:"timeout,omitempty"`
 	Cache        *Cache             `json:"cache,omitempty"`
 	Limiter      *Limiter           `json:"limiter,omitempty"`
 	SecurityCors *SecurityCors      `json:"security-cors,omitempty"`
+	Transport    *Transport         `json:"transport,omitempty"`
+	Auth         *Auth              `json:"auth,omitempty"`
+	Consumers    []Consumer         `json:"consumers,omitempty"`
 	Middlewares  map[string]Backend `json:"middlewares,omitempty"`
 	Endpoints    []Endpoint         `json:"endpoints,omitempty"`
 }
@@ -44,6 +51,47 @@ type Redis struct {
 	Password string `json:"password,omitempty"`
 }
 
+type Tracer struct {
+	Comment     string              `json:"@comment,omitempty"`
+	Provider    enum.TracerProvider `json:"provider,omitempty"`
+	ServiceName string              `json:"service-name,omitempty"`
+	SampleRatio *float64            `json:"sample-ratio,omitempty"`
+	OTLP        *OTLP               `json:"otlp,omitempty"`
+}
+
+type OTLP struct {
+	Endpoint string            `json:"endpoint,omitempty"`
+	Headers  map[string]string `json:"headers,omitempty"`
+}
+
+type Log struct {
+	Comment string         `json:"@comment,omitempty"`
+	Level   enum.LogLevel  `json:"level,omitempty"`
+	Format  enum.LogFormat `json:"format,omitempty"`
+	File    *LogFile       `json:"file,omitempty"`
+}
+
+type LogFile struct {
+	Comment    string       `json:"@comment,omitempty"`
+	Path       string       `json:"path,omitempty"`
+	MaxSize    *vo.Bytes    `json:"max-size,omitempty"`
+	MaxBackups int          `json:"max-backups,omitempty"`
+	MaxAge     *vo.Duration `json:"max-age,omitempty"`
+	Compress   bool         `json:"compress,omitempty"`
+}
+
+type Readiness struct {
+	Comment    string      `json:"@comment,omitempty"`
+	ProbeHosts bool        `json:"probe-hosts,omitempty"`
+	Timeout    vo.Duration `json:"timeout,omitempty"`
+}
+
+type Admin struct {
+	Comment string `json:"@comment,omitempty"`
+	Port    int    `json:"port,omitempty"`
+	Token   string `json:"token,omitempty"`
+}
+
 type Cache struct {
 	Duration          vo.Duration `json:"duration,omitempty"`
 	StrategyHeaders   []string    `json:"strategy-headers,omitempty"`
@@ -62,28 +110,117 @@ type EndpointCache struct {
 }
 
 type Limiter struct {
-	MaxHeaderSize          *vo.Bytes `json:"max-header-size,omitempty"`
-	MaxBodySize            *vo.Bytes `json:"max-body-size,omitempty"`
-	MaxMultipartMemorySize *vo.Bytes `json:"max-multipart-memory-size,omitempty"`
-	Rate                   *Rate     `json:"rate,omitempty"`
+	MaxHeaderSize          *vo.Bytes      `json:"max-header-size,omitempty"`
+	MaxBodySize            *vo.Bytes      `json:"max-body-size,omitempty"`
+	MaxMultipartMemorySize *vo.Bytes      `json:"max-multipart-memory-size,omitempty"`
+	Rate                   *Rate          `json:"rate,omitempty"`
+	MaxConcurrent          *MaxConcurrent `json:"max-concurrent,omitempty"`
+	Buckets                *Buckets       `json:"buckets,omitempty"`
+}
+
+type Buckets struct {
+	Comment    string       `json:"@comment,omitempty"`
+	TTL        *vo.Duration `json:"ttl,omitempty"`
+	MaxEntries *int         `json:"max-entries,omitempty"`
 }
 
 type Rate struct {
-	Capacity *int         `json:"capacity,omitempty"`
-	Every    *vo.Duration `json:"every,omitempty"`
+	Capacity *int           `json:"capacity,omitempty"`
+	Every    *vo.Duration   `json:"every,omitempty"`
+	Scope    enum.RateScope `json:"scope,omitempty"`
+	Key      *RateKey       `json:"key,omitempty"`
+	Headers  *bool          `json:"headers,omitempty"`
+}
+
+type RateKey struct {
+	Comment    string                 `json:"@comment,omitempty"`
+	Strategies []enum.RateKeyStrategy `json:"strategies,omitempty"`
+	Header     string                 `json:"header,omitempty"`
+	Claim      string                 `json:"claim,omitempty"`
 }
 
 type EndpointLimiter struct {
-	MaxHeaderSize          vo.Bytes `json:"max-header-size,omitempty"`
-	MaxBodySize            vo.Bytes `json:"max-body-size,omitempty"`
-	MaxMultipartMemorySize vo.Bytes `json:"max-multipart-memory-size,omitempty"`
-	Rate                   *Rate    `json:"rate,omitempty"`
+	MaxHeaderSize          vo.Bytes       `json:"max-header-size,omitempty"`
+	MaxBodySize            vo.Bytes       `json:"max-body-size,omitempty"`
+	MaxMultipartMemorySize vo.Bytes       `json:"max-multipart-memory-size,omitempty"`
+	Rate                   *Rate          `json:"rate,omitempty"`
+	MaxConcurrent          *MaxConcurrent `json:"max-concurrent,omitempty"`
+}
+
+type MaxConcurrent struct {
+	Comment      string      `json:"@comment,omitempty"`
+	Limit        int         `json:"limit,omitempty"`
+	QueueSize    int         `json:"queue-size,omitempty"`
+	QueueTimeout vo.Duration `json:"queue-timeout,omitempty"`
+}
+
+type Transport struct {
+	Comment               string       `json:"@comment,omitempty"`
+	MaxIdleConnsPerHost   *int         `json:"max-idle-conns-per-host,omitempty"`
+	IdleConnTimeout       *vo.Duration `json:"idle-conn-timeout,omitempty"`
+	DialTimeout           *vo.Duration `json:"dial-timeout,omitempty"`
+	TLSHandshakeTimeout   *vo.Duration `json:"tls-handshake-timeout,omitempty"`
+	ResponseHeaderTimeout *vo.Duration `json:"response-header-timeout,omitempty"`
+	KeepAlive             *bool        `json:"keep-alive,omitempty"`
+	HTTP2                 *bool        `json:"http2,omitempty"`
+}
+
+type Auth struct {
+	Comment string  `json:"@comment,omitempty"`
+	JWT     *JWT    `json:"jwt,omitempty"`
+	APIKey  *APIKey `json:"api-key,omitempty"`
+}
+
+type JWT struct {
+	Comment        string      `json:"@comment,omitempty"`
+	Disabled       bool        `json:"disabled,omitempty"`
+	Algorithms     []string    `json:"algorithms,omitempty"`
+	Secret         string      `json:"secret,omitempty"`
+	PEMFile        string      `json:"pem-file,omitempty"`
+	JWKSFile       string      `json:"jwks-file,omitempty"`
+	Issuer         string      `json:"issuer,omitempty"`
+	Audience       []string    `json:"audience,omitempty"`
+	RequiredClaims []string    `json:"required-claims,omitempty"`
+	Leeway         vo.Duration `json:"leeway,omitempty"`
+}
+
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	K   string `json:"k"`
+}
+
+type APIKey struct {
+	Comment  string `json:"@comment,omitempty"`
+	Disabled bool   `json:"disabled,omitempty"`
+	Header   string `json:"header,omitempty"`
+	Query    string `json:"query,omitempty"`
+}
+
+type Consumer struct {
+	Comment          string            `json:"@comment,omitempty"`
+	Name             string            `json:"name,omitempty"`
+	APIKeys          []string          `json:"api-keys,omitempty"`
+	AllowedEndpoints []string          `json:"allowed-endpoints,omitempty"`
+	Metadata         map[string]string `json:"metadata,omitempty"`
 }
 
 type SecurityCors struct {
-	AllowOrigins []string `json:"allow-origins"`
-	AllowMethods []string `json:"allow-methods"`
-	AllowHeaders []string `json:"allow-headers"`
+	AllowOrigins     []string     `json:"allow-origins"`
+	AllowMethods     []string     `json:"allow-methods"`
+	AllowHeaders     []string     `json:"allow-headers"`
+	ExposeHeaders    []string     `json:"expose-headers,omitempty"`
+	AllowCredentials *bool        `json:"allow-credentials,omitempty"`
+	MaxAge           *vo.Duration `json:"max-age,omitempty"`
 }
 
 type Middlewares map[string]Backend
@@ -95,6 +232,8 @@ type Endpoint struct {
 	Timeout            vo.Duration       `json:"timeout,omitempty"`
 	Limiter            *EndpointLimiter  `json:"limiter,omitempty"`
 	Cache              *EndpointCache    `json:"cache,omitempty"`
+	SecurityCors       *SecurityCors     `json:"security-cors,omitempty"`
+	Auth               *Auth             `json:"auth,omitempty"`
 	AbortIfStatusCodes *[]int            `json:"abort-if-status-codes,omitempty"`
 	Response           *EndpointResponse `json:"response,omitempty"`
 	Beforewares        []string          `json:"beforewares,omitempty"`
@@ -112,12 +251,92 @@ type EndpointResponse struct {
 }
 
 type Backend struct {
-	Comment  string           `json:"@comment,omitempty"`
-	Hosts    []string         `json:"hosts,omitempty"`
-	Path     string           `json:"path,omitempty"`
-	Method   string           `json:"method,omitempty"`
-	Request  *BackendRequest  `json:"request,omitempty"`
-	Response *BackendResponse `json:"response,omitempty"`
+	Comment        string                 `json:"@comment,omitempty"`
+	Hosts          []string               `json:"hosts,omitempty"`
+	Path           string                 `json:"path,omitempty"`
+	Method         string                 `json:"method,omitempty"`
+	Balancer       *BackendBalancer       `json:"balancer,omitempty"`
+	HealthCheck    *BackendHealthCheck    `json:"health-check,omitempty"`
+	CircuitBreaker *BackendCircuitBreaker `json:"circuit-breaker,omitempty"`
+	Retry          *BackendRetry          `json:"retry,omitempty"`
+	OnlyIf         []BackendCondition     `json:"only-if,omitempty"`
+	IgnoreIf       []BackendCondition     `json:"ignore-if,omitempty"`
+	Foreach        *BackendForeach        `json:"foreach,omitempty"`
+	Fallback       *BackendFallback       `json:"fallback,omitempty"`
+	Transport      *Transport             `json:"transport,omitempty"`
+	Request        *BackendRequest        `json:"request,omitempty"`
+	Response       *BackendResponse       `json:"response,omitempty"`
+}
+
+type BackendBalancer struct {
+	Comment    string                 `json:"@comment,omitempty"`
+	Algorithm  enum.BalancerAlgorithm `json:"algorithm,omitempty"`
+	Weights    []int                  `json:"weights,omitempty"`
+	HashKey    enum.BalancerHashKey   `json:"hash-key,omitempty"`
+	HashHeader string                 `json:"hash-header,omitempty"`
+}
+
+type BackendHealthCheck struct {
+	Comment             string      `json:"@comment,omitempty"`
+	Path                string      `json:"path,omitempty"`
+	Interval            vo.Duration `json:"interval,omitempty"`
+	Timeout             vo.Duration `json:"timeout,omitempty"`
+	ExpectedStatusCodes []int       `json:"expected-status-codes,omitempty"`
+	UnhealthyThreshold  int         `json:"unhealthy-threshold,omitempty"`
+	HealthyThreshold    int         `json:"healthy-threshold,omitempty"`
+}
+
+type BackendCircuitBreaker struct {
+	Comment             string            `json:"@comment,omitempty"`
+	Window              vo.Duration       `json:"window,omitempty"`
+	MinRequests         int               `json:"min-requests,omitempty"`
+	ErrorRatio          float64           `json:"error-ratio,omitempty"`
+	ConsecutiveFailures int               `json:"consecutive-failures,omitempty"`
+	Cooldown            vo.Duration       `json:"cooldown,omitempty"`
+	HalfOpenRequests    int               `json:"half-open-requests,omitempty"`
+	Fallback            *FallbackResponse `json:"fallback,omitempty"`
+}
+
+type BackendRetry struct {
+	Comment            string      `json:"@comment,omitempty"`
+	MaxAttempts        int         `json:"max-attempts,omitempty"`
+	StatusCodes        []int       `json:"status-codes,omitempty"`
+	OnConnectionError  bool        `json:"on-connection-error,omitempty"`
+	OnTimeout          bool        `json:"on-timeout,omitempty"`
+	InitialInterval    vo.Duration `json:"initial-interval,omitempty"`
+	MaxInterval        vo.Duration `json:"max-interval,omitempty"`
+	Multiplier         float64     `json:"multiplier,omitempty"`
+	Budget             vo.Duration `json:"budget,omitempty"`
+	AllowNonIdempotent bool        `json:"allow-non-idempotent,omitempty"`
+}
+
+type BackendCondition struct {
+	Comment  string                 `json:"@comment,omitempty"`
+	Value    string                 `json:"value,omitempty"`
+	Operator enum.ConditionOperator `json:"operator,omitempty"`
+	Expected []any                  `json:"expected,omitempty"`
+}
+
+type BackendForeach struct {
+	Comment     string `json:"@comment,omitempty"`
+	Path        string `json:"path,omitempty"`
+	Concurrency int    `json:"concurrency,omitempty"`
+	MaxItems    int    `json:"max-items,omitempty"`
+}
+
+type BackendFallback struct {
+	Comment    string            `json:"@comment,omitempty"`
+	StatusCode int               `json:"status-code,omitempty"`
+	Header     map[string]string `json:"header,omitempty"`
+	Body       any               `json:"body,omitempty"`
+	Backend    *Backend          `json:"backend,omitempty"`
+}
+
+type FallbackResponse struct {
+	Comment    string            `json:"@comment,omitempty"`
+	StatusCode int               `json:"status-code,omitempty"`
+	Header     map[string]string `json:"header,omitempty"`
+	Body       any               `json:"body,omitempty"`
 }
 
 type BackendRequest struct {
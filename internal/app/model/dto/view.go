@@ -16,6 +16,12 @@
 
 package dto
 
+import (
+	"github.com/tech4works/gopen-gateway/internal/domain/model/enum"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/vo"
+	"time"
+)
+
 type SettingView struct {
 	Version      string `json:"version,omitempty"`
 	VersionDate  string `json:"version-date,omitempty"`
@@ -26,3 +32,64 @@ type SettingView struct {
 	Backends     int    `json:"backends"`
 	Setting      Gopen  `json:"setting"`
 }
+
+type LimiterStatsView struct {
+	Buckets   int    `json:"buckets"`
+	Evictions uint64 `json:"evictions"`
+}
+
+type HealthHostsView struct {
+	Up    int              `json:"up"`
+	Down  int              `json:"down"`
+	Hosts []HostHealthView `json:"hosts"`
+}
+
+type HostHealthView struct {
+	Host      string    `json:"host"`
+	Status    string    `json:"status"`
+	Failures  int       `json:"failures,omitempty"`
+	Message   string    `json:"message,omitempty"`
+	CheckedAt time.Time `json:"checked-at"`
+}
+
+type LivenessView struct {
+	Status string `json:"status"`
+}
+
+type ReadinessView struct {
+	Status    string                `json:"status"`
+	Reloading bool                  `json:"reloading"`
+	Checks    []DependencyCheckView `json:"checks"`
+}
+
+type DependencyCheckView struct {
+	Kind     enum.DependencyKind `json:"kind"`
+	Name     string              `json:"name"`
+	Status   string              `json:"status"`
+	Message  string              `json:"message,omitempty"`
+	Duration vo.Duration         `json:"duration,omitempty"`
+}
+
+type RouteView struct {
+	Method        string      `json:"method"`
+	Path          string      `json:"path"`
+	Enabled       bool        `json:"enabled"`
+	Timeout       vo.Duration `json:"timeout"`
+	CacheDuration vo.Duration `json:"cache-duration,omitempty"`
+	SecurityCors  bool        `json:"security-cors"`
+	JWT           bool        `json:"jwt"`
+	APIKey        bool        `json:"api-key"`
+	Beforewares   int         `json:"beforewares"`
+	Afterwares    int         `json:"afterwares"`
+	Backends      int         `json:"backends"`
+	Config        Endpoint    `json:"config"`
+}
+
+type PurgeCacheView struct {
+	Pattern string `json:"pattern"`
+	Keys    int    `json:"keys"`
+}
+
+type ResetLimiterView struct {
+	Buckets int `json:"buckets"`
+}
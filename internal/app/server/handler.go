@@ -0,0 +1,86 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"context"
+	net "net/http"
+	"sync/atomic"
+	"time"
+)
+
+const drainInterval = 100 * time.Millisecond
+
+type handler struct {
+	current atomic.Pointer[generation]
+}
+
+type generation struct {
+	owner    *http
+	engine   net.Handler
+	inFlight atomic.Int64
+}
+
+func newHandler(owner *http, engine net.Handler) *handler {
+	h := &handler{}
+	h.current.Store(newGeneration(owner, engine))
+	return h
+}
+
+func newGeneration(owner *http, engine net.Handler) *generation {
+	return &generation{
+		owner:  owner,
+		engine: engine,
+	}
+}
+
+func (h *handler) ServeHTTP(writer net.ResponseWriter, request *net.Request) {
+	current := h.acquire()
+	defer current.inFlight.Add(-1)
+
+	current.engine.ServeHTTP(writer, request)
+}
+
+func (h *handler) Swap(owner *http, engine net.Handler) *generation {
+	return h.current.Swap(newGeneration(owner, engine))
+}
+
+func (h *handler) acquire() *generation {
+	for {
+		current := h.current.Load()
+		current.inFlight.Add(1)
+		if h.current.Load() == current {
+			return current
+		}
+		// the generation was swapped between load and increment, so we release it and try again with the new one
+		current.inFlight.Add(-1)
+	}
+}
+
+func (g *generation) Drain(ctx context.Context) error {
+	ticker := time.NewTicker(drainInterval)
+	defer ticker.Stop()
+
+	for g.inFlight.Load() > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
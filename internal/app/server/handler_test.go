@@ -0,0 +1,104 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"context"
+	net "net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHandlerServeHTTP(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	h := newHandler(nil, net.HandlerFunc(func(writer net.ResponseWriter, request *net.Request) {
+		close(started)
+		<-release
+	}))
+	current := h.current.Load()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(net.MethodGet, "/", nil))
+	}()
+
+	<-started
+	if got := current.inFlight.Load(); got != 1 {
+		t.Errorf("inFlight while serving = %d, want 1", got)
+	}
+	close(release)
+	<-done
+	if got := current.inFlight.Load(); got != 0 {
+		t.Errorf("inFlight after serving = %d, want 0", got)
+	}
+}
+
+func TestHandlerSwap(t *testing.T) {
+	var served []string
+	engine := func(name string) net.Handler {
+		return net.HandlerFunc(func(writer net.ResponseWriter, request *net.Request) {
+			served = append(served, name)
+		})
+	}
+	h := newHandler(nil, engine("previous"))
+	first := h.current.Load()
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(net.MethodGet, "/", nil))
+	if previous := h.Swap(nil, engine("next")); previous != first {
+		t.Errorf("Swap() returned another generation than the previous one")
+	}
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(net.MethodGet, "/", nil))
+
+	if len(served) != 2 || served[0] != "previous" || served[1] != "next" {
+		t.Errorf("served = %v, want [previous next]", served)
+	}
+}
+
+func TestGenerationDrain(t *testing.T) {
+	tests := []struct {
+		name      string
+		inFlight  int64
+		releaseIn time.Duration
+		timeout   time.Duration
+		wantErr   error
+	}{
+		{"without requests in flight", 0, 0, time.Second, nil},
+		{"requests finished before the timeout", 2, 2 * drainInterval, time.Second, nil},
+		{"requests still in flight at the timeout", 1, 0, 2 * drainInterval, context.DeadlineExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newGeneration(nil, nil)
+			g.inFlight.Add(tt.inFlight)
+			if tt.releaseIn > 0 {
+				time.AfterFunc(tt.releaseIn, func() {
+					g.inFlight.Add(-tt.inFlight)
+				})
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), tt.timeout)
+			defer cancel()
+
+			if err := g.Drain(ctx); err != tt.wantErr {
+				t.Errorf("Drain() err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"github.com/tech4works/checker"
 	"github.com/tech4works/converter"
+	"github.com/tech4works/errors"
 	"github.com/tech4works/gopen-gateway/internal/app"
 	"github.com/tech4works/gopen-gateway/internal/app/controller"
 	"github.com/tech4works/gopen-gateway/internal/app/factory"
@@ -31,35 +32,61 @@ import (
 	domainFactory "github.com/tech4works/gopen-gateway/internal/domain/factory"
 	"github.com/tech4works/gopen-gateway/internal/domain/model/vo"
 	"github.com/tech4works/gopen-gateway/internal/domain/service"
-	"go.elastic.co/apm/module/apmhttp/v2"
 	net "net/http"
 	"os"
 )
 
 type http struct {
 	net                     *net.Server
+	handler                 *handler
+	adminNet                *net.Server
+	adminHandler            *handler
 	gopen                   *vo.Gopen
+	admin                   *dto.Admin
+	store                   domain.Store
+	limiterStore            domain.LimiterStore
+	localLimiterStore       domain.LocalLimiterStore
 	log                     app.BootLog
+	tracer                  app.Tracer
 	router                  app.Router
+	adminRouter             app.Router
+	httpClient              app.HTTPClient
+	metricsMiddleware       middleware.Metrics
 	panicRecoveryMiddleware middleware.PanicRecovery
 	logMiddleware           middleware.Log
+	toggleMiddleware        middleware.Toggle
 	securityCorsMiddleware  middleware.SecurityCors
+	authMiddleware          middleware.Auth
 	timeoutMiddleware       middleware.Timeout
 	limiterMiddleware       middleware.Limiter
 	cacheMiddleware         middleware.Cache
+	adminMiddleware         middleware.Admin
+	healthUseCase           usecase.Health
+	readinessUseCase        usecase.Readiness
+	limiterService          service.Limiter
+	healthService           service.Health
+	balancerService         service.Balancer
+	circuitBreakerService   service.CircuitBreaker
+	toggleService           service.Toggle
 	staticController        controller.Static
 	endpointController      controller.Endpoint
+	adminController         controller.Admin
 }
 
 type HTTP interface {
 	ListenAndServe()
+	Reload(ctx context.Context, next HTTP) error
+	MarkReloading(reloading bool)
 	Shutdown(ctx context.Context) error
 }
 
 func New(
 	gopen *dto.Gopen,
+	previous HTTP,
+	boot app.Boot,
 	log app.BootLog,
 	router app.Router,
+	adminRouter app.Router,
 	httpClient app.HTTPClient,
 	endpointLog app.EndpointLog,
 	backendLog app.BackendLog,
@@ -67,7 +94,12 @@ func New(
 	jsonPath domain.JSONPath,
 	converter domain.Converter,
 	store domain.Store,
+	limiterStore domain.LimiterStore,
+	localLimiterStore domain.LocalLimiterStore,
 	nomenclature domain.Nomenclature,
+	jwt domain.JWT,
+	metrics app.Metrics,
+	tracer app.Tracer,
 ) HTTP {
 	log.PrintInfo("Building domain...")
 	mapperService := service.NewMapper(jsonPath)
@@ -78,56 +110,118 @@ func New(
 	nomenclatureService := service.NewNomenclature(jsonPath, nomenclature)
 	contentService := service.NewContent(converter)
 	aggregatorService := service.NewAggregator(jsonPath)
-	limiterService := service.NewLimiter()
 	securityCorsService := service.NewSecurityCors()
+	authService := service.NewAuth(jwt, jsonPath)
 	cacheService := service.NewCache(store)
+	conditionService := service.NewCondition(dynamicValueService)
+	limiterService, healthService, balancerService, circuitBreakerService := buildStatefulServices(previous,
+		limiterStore, localLimiterStore, jsonPath)
+	toggleService := service.NewToggle()
 
 	log.PrintInfo("Building factories...")
 	httpBackendFactory := domainFactory.NewHTTPBackend(mapperService, projectorService, dynamicValueService,
-		modifierService, omitterService, nomenclatureService, contentService, aggregatorService)
+		modifierService, omitterService, nomenclatureService, contentService, aggregatorService, balancerService)
 	httpResponseFactory := domainFactory.NewHTTPResponse(aggregatorService, omitterService, nomenclatureService,
 		contentService, httpBackendFactory)
 
 	log.PrintInfo("Building use cases...")
-	endpointUseCase := usecase.NewEndpoint(httpBackendFactory, httpResponseFactory, httpClient, endpointLog, backendLog)
+	endpointUseCase := usecase.NewEndpoint(httpBackendFactory, httpResponseFactory, balancerService, healthService,
+		circuitBreakerService, dynamicValueService, conditionService, httpClient, endpointLog, backendLog, metrics,
+		tracer)
+	healthUseCase := usecase.NewHealth(healthService, httpClient, log)
+	readinessUseCase := usecase.NewReadiness(store, limiterStore, healthUseCase)
 
 	log.PrintInfo("Building middlewares...")
-	panicRecoveryMiddleware := middleware.NewPanicRecovery(endpointLog)
+	metricsMiddleware := middleware.NewMetrics(metrics)
+	panicRecoveryMiddleware := middleware.NewPanicRecovery(endpointLog, metrics, tracer)
 	logMiddleware := middleware.NewLog(httpLog)
+	toggleMiddleware := middleware.NewToggle(toggleService)
 	securityCorsMiddleware := middleware.NewSecurityCors(securityCorsService)
-	timeoutMiddleware := middleware.NewTimeout()
-	limiterMiddleware := middleware.NewLimiter(limiterService)
-	cacheMiddleware := middleware.NewCache(cacheService, endpointLog)
+	authMiddleware := middleware.NewAuth(authService)
+	timeoutMiddleware := middleware.NewTimeout(metrics)
+	limiterMiddleware := middleware.NewLimiter(limiterService, endpointLog, metrics)
+	cacheMiddleware := middleware.NewCache(cacheService, endpointLog, metrics)
+	var adminMiddleware middleware.Admin
+	if checker.NonNil(gopen.Admin) {
+		adminMiddleware = middleware.NewAdmin(authService, gopen.Admin.Token)
+	}
 
 	log.PrintInfo("Building controllers...")
-	staticController := controller.NewStatic(gopen)
+	staticController := controller.NewStatic(gopen, healthUseCase, readinessUseCase, limiterService, metrics)
 	endpointController := controller.NewEndpoint(endpointUseCase)
+	adminController := controller.NewAdmin(gopen, boot, cacheService, limiterService, toggleService)
 
 	log.PrintInfo("Building value objects...")
 	return &http{
 		gopen:                   factory.BuildGopen(gopen),
+		admin:                   gopen.Admin,
+		store:                   store,
+		limiterStore:            limiterStore,
+		localLimiterStore:       localLimiterStore,
 		log:                     log,
+		tracer:                  tracer,
 		router:                  router,
+		adminRouter:             adminRouter,
+		httpClient:              httpClient,
+		metricsMiddleware:       metricsMiddleware,
 		panicRecoveryMiddleware: panicRecoveryMiddleware,
 		logMiddleware:           logMiddleware,
+		toggleMiddleware:        toggleMiddleware,
 		timeoutMiddleware:       timeoutMiddleware,
 		limiterMiddleware:       limiterMiddleware,
 		cacheMiddleware:         cacheMiddleware,
+		adminMiddleware:         adminMiddleware,
+		healthUseCase:           healthUseCase,
+		readinessUseCase:        readinessUseCase,
+		limiterService:          limiterService,
+		healthService:           healthService,
+		balancerService:         balancerService,
+		circuitBreakerService:   circuitBreakerService,
+		toggleService:           toggleService,
 		securityCorsMiddleware:  securityCorsMiddleware,
+		authMiddleware:          authMiddleware,
 		staticController:        staticController,
 		endpointController:      endpointController,
+		adminController:         adminController,
 	}
 }
 
-func (h *http) ListenAndServe() {
-	h.log.PrintInfo("Configuring routes...")
+func buildStatefulServices(previous HTTP, limiterStore domain.LimiterStore, localLimiterStore domain.LocalLimiterStore,
+	jsonPath domain.JSONPath) (service.Limiter, service.Health, service.Balancer, service.CircuitBreaker) {
+	// the services that count requests and failures are kept on reload, otherwise every reload would reset the
+	// open circuits, the down hosts, the balancer counters and the concurrency slots in use
+	previousHTTP, ok := previous.(*http)
+	if !ok {
+		healthService := service.NewHealth()
+		return service.NewLimiter(limiterStore, localLimiterStore, jsonPath), healthService,
+			service.NewBalancer(healthService), service.NewCircuitBreaker()
+	}
+	current := previousHTTP.currentGeneration()
 
-	h.buildStaticRoutes()
-	h.buildRoutes()
+	limiterService := current.limiterService
+	if current.limiterStore != limiterStore || current.localLimiterStore != localLimiterStore {
+		limiterService = service.NewLimiter(limiterStore, localLimiterStore, jsonPath)
+	}
+	return limiterService, current.healthService, current.balancerService, current.circuitBreakerService
+}
+
+func (h *http) ListenAndServe() {
+	h.buildAllRoutes()
+	h.healthUseCase.Start(h.gopen)
 
+	h.handler = newHandler(h, h.router.Engine())
 	h.net = &net.Server{
 		Addr:    fmt.Sprint(":", os.Getenv("GOPEN_PORT")),
-		Handler: apmhttp.Wrap(h.router.Engine()),
+		Handler: h.tracer.Handler(h.handler),
+	}
+
+	if checker.NonNil(h.admin) {
+		h.adminHandler = newHandler(h, h.adminRouter.Engine())
+		h.adminNet = &net.Server{
+			Addr:    fmt.Sprint(":", h.admin.Port),
+			Handler: h.adminHandler,
+		}
+		go h.listenAndServeAdmin()
 	}
 
 	h.log.SkipLine()
@@ -136,11 +230,120 @@ func (h *http) ListenAndServe() {
 	h.net.ListenAndServe()
 }
 
+func (h *http) Reload(ctx context.Context, next HTTP) error {
+	nextHTTP, ok := next.(*http)
+	if !ok || checker.IsNil(h.handler) {
+		return errors.New("Server not listening or next server incompatible to reload!")
+	}
+
+	// the next generation answers not ready until the reload that is marking the current one is finished
+	nextHTTP.readinessUseCase.MarkReloading(true)
+
+	defer func() {
+		if r := recover(); checker.NonNil(r) {
+			nextHTTP.healthUseCase.Stop()
+			nextHTTP.close()
+			panic(r)
+		}
+	}()
+	nextHTTP.buildAllRoutes()
+	nextHTTP.keepDisabledEndpoints(h.handler.current.Load().owner)
+
+	h.log.PrintInfo("Swapping routes...")
+	previous := h.handler.Swap(nextHTTP, nextHTTP.router.Engine())
+
+	// the health service is shared, so the next checks only start after the previous ones stop marking the hosts
+	previous.owner.healthUseCase.Stop()
+	nextHTTP.healthUseCase.Start(nextHTTP.gopen)
+
+	// the admin routes are not drained, the reload itself can be running on one of them
+	if checker.NonNil(h.adminHandler) {
+		h.adminHandler.Swap(nextHTTP, nextHTTP.adminRouter.Engine())
+	}
+	if checker.NonNil(nextHTTP.admin) && (checker.IsNil(h.adminNet) ||
+		checker.NotEquals(h.adminNet.Addr, fmt.Sprint(":", nextHTTP.admin.Port))) {
+		h.log.PrintWarn("Admin listener changes are only applied on restart!")
+	}
+
+	h.log.PrintInfo("Draining previous routes...")
+	err := previous.Drain(ctx)
+	if checker.NonNil(err) {
+		h.log.PrintWarnf("Error drain previous routes: %s!", err)
+	}
+
+	previous.owner.close()
+
+	return nil
+}
+
+func (h *http) MarkReloading(reloading bool) {
+	h.currentGeneration().readinessUseCase.MarkReloading(reloading)
+}
+
 func (h *http) Shutdown(ctx context.Context) error {
+	// a server that never listened, like one discarded by a failed reload, only has its resources to release
 	if checker.IsNil(h.net) {
+		h.close()
 		return nil
 	}
-	return h.net.Shutdown(ctx)
+
+	err := h.net.Shutdown(ctx)
+	if checker.NonNil(err) {
+		return err
+	}
+
+	if checker.NonNil(h.adminNet) {
+		err = h.adminNet.Shutdown(ctx)
+		if checker.NonNil(err) {
+			return err
+		}
+	}
+
+	current := h.currentGeneration()
+	current.healthUseCase.Stop()
+	current.close()
+
+	return nil
+}
+
+func (h *http) listenAndServeAdmin() {
+	h.log.PrintInfof("Admin listening on %s", h.adminNet.Addr)
+
+	err := h.adminNet.ListenAndServe()
+	if errors.IsNot(err, net.ErrServerClosed) {
+		h.log.PrintError("Error listen and serve admin:", err)
+	}
+}
+
+func (h *http) currentGeneration() *http {
+	if checker.IsNil(h.handler) {
+		return h
+	}
+	return h.handler.current.Load().owner
+}
+
+func (h *http) close() {
+	// the previous generation keeps its own connection pools, which would stay idle forever after the drain, the
+	// stores are not closed here, they belong to the boot, which keeps them on reload when their config is unchanged
+	h.httpClient.CloseIdleConnections()
+}
+
+func (h *http) keepDisabledEndpoints(previous *http) {
+	// the endpoints disabled by the admin stay disabled on reload, unless they were removed from the config
+	for _, endpoint := range h.gopen.Endpoints() {
+		if previous.toggleService.IsDisabled(&endpoint) {
+			h.toggleService.Disable(&endpoint)
+		}
+	}
+}
+
+func (h *http) buildAllRoutes() {
+	h.log.PrintInfo("Configuring routes...")
+
+	h.buildStaticRoutes()
+	h.buildRoutes()
+	h.buildPreflightRoutes()
+	h.buildAdminRoutes()
 }
 
 func (h *http) buildRoutes() {
@@ -152,8 +355,26 @@ func (h *http) buildRoutes() {
 	}
 }
 
+func (h *http) buildPreflightRoutes() {
+	formatLog := "Registered route with 6 handles: %s --> \"%s\" (preflight)"
+
+	paths := map[string]bool{}
+	for _, endpoint := range h.gopen.Endpoints() {
+		if !endpoint.HasSecurityCors() || paths[endpoint.Path()] {
+			continue
+		} else if _, ok := h.gopen.FindEndpoint(endpoint.Path(), net.MethodOptions); ok {
+			continue
+		}
+		paths[endpoint.Path()] = true
+
+		preflightEndpoint := vo.NewEndpointStatic(endpoint.Path(), net.MethodOptions)
+		h.buildStaticRoute(&preflightEndpoint, h.securityCorsMiddleware.Preflight)
+		h.log.PrintInfof(formatLog, preflightEndpoint.Method(), preflightEndpoint.Path())
+	}
+}
+
 func (h *http) buildStaticRoutes() {
-	formatLog := "Registered route with 5 handles: %s --> \"%s\""
+	formatLog := "Registered route with 6 handles: %s --> \"%s\""
 
 	pingEndpoint := h.buildStaticPingRoute()
 	h.log.PrintInfof(formatLog, pingEndpoint.Method(), pingEndpoint.Path())
@@ -163,6 +384,44 @@ func (h *http) buildStaticRoutes() {
 
 	settingsEndpoint := h.buildStaticSettingsRoute()
 	h.log.PrintInfof(formatLog, settingsEndpoint.Method(), settingsEndpoint.Path())
+
+	healthHostsEndpoint := h.buildStaticHealthHostsRoute()
+	h.log.PrintInfof(formatLog, healthHostsEndpoint.Method(), healthHostsEndpoint.Path())
+
+	healthLiveEndpoint := h.buildStaticHealthLiveRoute()
+	h.log.PrintInfof(formatLog, healthLiveEndpoint.Method(), healthLiveEndpoint.Path())
+
+	healthReadyEndpoint := h.buildStaticHealthReadyRoute()
+	h.log.PrintInfof(formatLog, healthReadyEndpoint.Method(), healthReadyEndpoint.Path())
+
+	limiterStatsEndpoint := h.buildStaticLimiterStatsRoute()
+	h.log.PrintInfof(formatLog, limiterStatsEndpoint.Method(), limiterStatsEndpoint.Path())
+
+	metricsEndpoint := h.buildStaticMetricsRoute()
+	h.log.PrintInfof(formatLog, metricsEndpoint.Method(), metricsEndpoint.Path())
+}
+
+func (h *http) buildAdminRoutes() {
+	if checker.IsNil(h.admin) {
+		return
+	}
+
+	formatLog := "Registered admin route with 4 handles: %s --> \"%s\""
+
+	routesEndpoint := h.buildAdminRoutesRoute()
+	h.log.PrintInfof(formatLog, routesEndpoint.Method(), routesEndpoint.Path())
+
+	toggleEndpoint := h.buildAdminToggleEndpointRoute()
+	h.log.PrintInfof(formatLog, toggleEndpoint.Method(), toggleEndpoint.Path())
+
+	purgeCacheEndpoint := h.buildAdminPurgeCacheRoute()
+	h.log.PrintInfof(formatLog, purgeCacheEndpoint.Method(), purgeCacheEndpoint.Path())
+
+	resetLimiterEndpoint := h.buildAdminResetLimiterRoute()
+	h.log.PrintInfof(formatLog, resetLimiterEndpoint.Method(), resetLimiterEndpoint.Path())
+
+	reloadEndpoint := h.buildAdminReloadRoute()
+	h.log.PrintInfof(formatLog, reloadEndpoint.Method(), reloadEndpoint.Path())
 }
 
 func (h *http) buildStaticPingRoute() *vo.Endpoint {
@@ -183,22 +442,109 @@ func (h *http) buildStaticSettingsRoute() *vo.Endpoint {
 	return &endpoint
 }
 
+func (h *http) buildStaticHealthHostsRoute() *vo.Endpoint {
+	endpoint := vo.NewEndpointStatic("/health/hosts", net.MethodGet)
+	h.buildStaticRoute(&endpoint, h.staticController.HealthHosts)
+	return &endpoint
+}
+
+func (h *http) buildStaticHealthLiveRoute() *vo.Endpoint {
+	endpoint := vo.NewEndpointStatic("/health/live", net.MethodGet)
+	h.buildStaticRoute(&endpoint, h.staticController.HealthLive)
+	return &endpoint
+}
+
+func (h *http) buildStaticHealthReadyRoute() *vo.Endpoint {
+	endpoint := vo.NewEndpointStatic("/health/ready", net.MethodGet)
+	h.buildStaticRoute(&endpoint, h.staticController.HealthReady)
+	return &endpoint
+}
+
+func (h *http) buildStaticLimiterStatsRoute() *vo.Endpoint {
+	endpoint := vo.NewEndpointStatic("/limiter/stats", net.MethodGet)
+	h.buildStaticRoute(&endpoint, h.staticController.LimiterStats)
+	return &endpoint
+}
+
+func (h *http) buildStaticMetricsRoute() *vo.Endpoint {
+	endpoint := vo.NewEndpointStatic("/metrics", net.MethodGet)
+	h.buildStaticRoute(&endpoint, h.staticController.Metrics)
+	return &endpoint
+}
+
+func (h *http) buildAdminRoutesRoute() *vo.Endpoint {
+	endpoint := vo.NewEndpointStatic("/routes", net.MethodGet)
+	h.buildAdminRoute(&endpoint, h.adminController.Routes)
+	return &endpoint
+}
+
+func (h *http) buildAdminToggleEndpointRoute() *vo.Endpoint {
+	endpoint := vo.NewEndpointStatic("/routes/toggle", net.MethodPost)
+	h.buildAdminRoute(&endpoint, h.adminController.ToggleEndpoint)
+	return &endpoint
+}
+
+func (h *http) buildAdminPurgeCacheRoute() *vo.Endpoint {
+	endpoint := vo.NewEndpointStatic("/cache/purge", net.MethodPost)
+	h.buildAdminRoute(&endpoint, h.adminController.PurgeCache)
+	return &endpoint
+}
+
+func (h *http) buildAdminResetLimiterRoute() *vo.Endpoint {
+	endpoint := vo.NewEndpointStatic("/limiter/reset", net.MethodPost)
+	h.buildAdminRoute(&endpoint, h.adminController.ResetLimiter)
+	return &endpoint
+}
+
+func (h *http) buildAdminReloadRoute() *vo.Endpoint {
+	endpoint := vo.NewEndpointStatic("/reload", net.MethodPost)
+	h.buildAdminRoute(&endpoint, h.adminController.Reload)
+	return &endpoint
+}
+
+func (h *http) buildAdminRoute(endpointAdmin *vo.Endpoint, handler app.HandlerFunc) {
+	panicHandler := h.panicRecoveryMiddleware.Do
+	logHandler := h.logMiddleware.Do
+	adminHandler := h.adminMiddleware.Do
+	h.adminRouter.Handle(h.gopen, endpointAdmin, panicHandler, logHandler, adminHandler, handler)
+}
+
 func (h *http) buildStaticRoute(endpointStatic *vo.Endpoint, handler app.HandlerFunc) {
+	metricsHandler := h.metricsMiddleware.Do
 	timeoutHandler := h.timeoutMiddleware.Do
 	panicHandler := h.panicRecoveryMiddleware.Do
 	logHandler := h.logMiddleware.Do
 	limiterHandler := h.limiterMiddleware.Do
-	h.router.Handle(h.gopen, endpointStatic, timeoutHandler, panicHandler, logHandler, limiterHandler, handler)
+	h.router.Handle(h.gopen, endpointStatic, metricsHandler, timeoutHandler, panicHandler, logHandler, limiterHandler, handler)
 }
 
 func (h *http) buildEndpointHandles() []app.HandlerFunc {
 	return []app.HandlerFunc{
-		h.timeoutMiddleware.Do,
-		h.panicRecoveryMiddleware.Do,
-		h.logMiddleware.Do,
-		h.securityCorsMiddleware.Do,
-		h.limiterMiddleware.Do,
-		h.cacheMiddleware.Do,
+		h.metricsMiddleware.Do,
+		h.buildSpanHandle("Timeout", h.timeoutMiddleware.Do),
+		h.buildSpanHandle("Panic recovery", h.panicRecoveryMiddleware.Do),
+		h.buildSpanHandle("Log", h.logMiddleware.Do),
+		h.buildSpanHandle("Toggle", h.toggleMiddleware.Do),
+		h.buildSpanHandle("Security cors", h.securityCorsMiddleware.Do),
+		h.buildSpanHandle("Auth", h.authMiddleware.Do),
+		h.buildSpanHandle("Limiter", h.limiterMiddleware.Do),
+		h.buildSpanHandle("Cache", h.cacheMiddleware.Do),
 		h.endpointController.Execute,
 	}
 }
+
+func (h *http) buildSpanHandle(name string, handle app.HandlerFunc) app.HandlerFunc {
+	return func(ctx app.Context) {
+		previousCtx := ctx.Context()
+		spanCtx, span := h.tracer.StartSpan(previousCtx, name, "middleware")
+		defer span.End()
+
+		ctx.WithContext(spanCtx)
+		handle(ctx)
+
+		// the context is only restored if the middleware didn't replace it, so the timeout deadline is never lost
+		if ctx.Context() == spanCtx {
+			ctx.WithContext(previousCtx)
+		}
+	}
+}
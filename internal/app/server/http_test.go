@@ -0,0 +1,99 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"github.com/tech4works/gopen-gateway/internal/domain"
+	"github.com/tech4works/gopen-gateway/internal/domain/service"
+	"github.com/tech4works/gopen-gateway/internal/infra/jsonpath"
+	"testing"
+)
+
+type fakeLimiterStore struct {
+	domain.LimiterStore
+	name string
+}
+
+type fakeLocalLimiterStore struct {
+	domain.LocalLimiterStore
+	name string
+}
+
+func TestBuildStatefulServices(t *testing.T) {
+	limiterStore := &fakeLimiterStore{name: "redis"}
+	localLimiterStore := &fakeLocalLimiterStore{name: "memory"}
+
+	tests := []struct {
+		name              string
+		limiterStore      domain.LimiterStore
+		localLimiterStore domain.LocalLimiterStore
+		swapped           bool
+		wantLimiterKept   bool
+	}{
+		{"same stores", limiterStore, localLimiterStore, false, true},
+		{"same stores on a swapped generation", limiterStore, localLimiterStore, true, true},
+		{"another limiter store", &fakeLimiterStore{name: "redis"}, localLimiterStore, false, false},
+		{"another local limiter store", limiterStore, &fakeLocalLimiterStore{name: "memory"}, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := newTestStatefulHTTP(limiterStore, localLimiterStore)
+			previous := current
+			if tt.swapped {
+				previous = newTestStatefulHTTP(limiterStore, localLimiterStore)
+				previous.handler = newHandler(previous, nil)
+				previous.handler.Swap(current, nil)
+			}
+
+			limiterService, healthService, balancerService, circuitBreakerService := buildStatefulServices(previous,
+				tt.limiterStore, tt.localLimiterStore, jsonpath.New())
+			if (limiterService == current.limiterService) != tt.wantLimiterKept {
+				t.Errorf("limiter service kept = %v, want %v", limiterService == current.limiterService,
+					tt.wantLimiterKept)
+			}
+			if healthService != current.healthService {
+				t.Errorf("health service not kept from the current generation")
+			}
+			if balancerService != current.balancerService {
+				t.Errorf("balancer service not kept from the current generation")
+			}
+			if circuitBreakerService != current.circuitBreakerService {
+				t.Errorf("circuit breaker service not kept from the current generation")
+			}
+		})
+	}
+}
+
+func TestBuildStatefulServicesWithoutPrevious(t *testing.T) {
+	limiterService, healthService, balancerService, circuitBreakerService := buildStatefulServices(nil, nil,
+		&fakeLocalLimiterStore{}, jsonpath.New())
+	if limiterService == nil || healthService == nil || balancerService == nil || circuitBreakerService == nil {
+		t.Errorf("buildStatefulServices() without previous server returned a nil service")
+	}
+}
+
+func newTestStatefulHTTP(limiterStore domain.LimiterStore, localLimiterStore domain.LocalLimiterStore) *http {
+	healthService := service.NewHealth()
+	return &http{
+		limiterStore:          limiterStore,
+		localLimiterStore:     localLimiterStore,
+		limiterService:        service.NewLimiter(limiterStore, localLimiterStore, jsonpath.New()),
+		healthService:         healthService,
+		balancerService:       service.NewBalancer(healthService),
+		circuitBreakerService: service.NewCircuitBreaker(),
+	}
+}
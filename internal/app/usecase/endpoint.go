@@ -20,61 +20,361 @@ import (
 	"context"
 	berrors "errors"
 	"github.com/tech4works/checker"
+	"github.com/tech4works/errors"
 	"github.com/tech4works/gopen-gateway/internal/app"
 	"github.com/tech4works/gopen-gateway/internal/app/model/dto"
+	"github.com/tech4works/gopen-gateway/internal/domain"
 	"github.com/tech4works/gopen-gateway/internal/domain/factory"
 	"github.com/tech4works/gopen-gateway/internal/domain/mapper"
 	"github.com/tech4works/gopen-gateway/internal/domain/model/vo"
-	"go.elastic.co/apm/v2"
+	"github.com/tech4works/gopen-gateway/internal/domain/service"
+	"net/http"
 	"net/url"
+	"slices"
+	"sync"
 	"time"
 )
 
 type endpointUseCase struct {
-	httpBackendFactory  factory.HTTPBackend
-	httpResponseFactory factory.HTTPResponse
-	httpClient          app.HTTPClient
-	endpointLog         app.EndpointLog
-	backendLog          app.BackendLog
+	httpBackendFactory    factory.HTTPBackend
+	httpResponseFactory   factory.HTTPResponse
+	balancerService       service.Balancer
+	healthService         service.Health
+	circuitBreakerService service.CircuitBreaker
+	dynamicValueService   service.DynamicValue
+	conditionService      service.Condition
+	httpClient            app.HTTPClient
+	endpointLog           app.EndpointLog
+	backendLog            app.BackendLog
+	metrics               app.Metrics
+	tracer                app.Tracer
+}
+
+type backendExecution struct {
+	executed bool
+	skipped  bool
+	aborted  bool
+	request  *vo.HTTPBackendRequest
+	response *vo.HTTPBackendResponse
 }
 
 type Endpoint interface {
 	Execute(ctx context.Context, executeData dto.ExecuteEndpoint) *vo.HTTPResponse
 }
 
-func NewEndpoint(backendFactory factory.HTTPBackend, responseFactory factory.HTTPResponse, httpClient app.HTTPClient,
-	endpointLog app.EndpointLog, backendLog app.BackendLog) Endpoint {
+func NewEndpoint(backendFactory factory.HTTPBackend, responseFactory factory.HTTPResponse,
+	balancerService service.Balancer, healthService service.Health, circuitBreakerService service.CircuitBreaker,
+	dynamicValueService service.DynamicValue, conditionService service.Condition, httpClient app.HTTPClient,
+	endpointLog app.EndpointLog, backendLog app.BackendLog, metrics app.Metrics, tracer app.Tracer,
+) Endpoint {
 	return endpointUseCase{
-		httpBackendFactory:  backendFactory,
-		httpResponseFactory: responseFactory,
-		httpClient:          httpClient,
-		endpointLog:         endpointLog,
-		backendLog:          backendLog,
+		httpBackendFactory:    backendFactory,
+		httpResponseFactory:   responseFactory,
+		balancerService:       balancerService,
+		healthService:         healthService,
+		circuitBreakerService: circuitBreakerService,
+		dynamicValueService:   dynamicValueService,
+		conditionService:      conditionService,
+		httpClient:            httpClient,
+		endpointLog:           endpointLog,
+		backendLog:            backendLog,
+		metrics:               metrics,
+		tracer:                tracer,
 	}
 }
 
 func (e endpointUseCase) Execute(ctx context.Context, executeData dto.ExecuteEndpoint) *vo.HTTPResponse {
-	history := vo.NewEmptyHistory()
+	executeCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
-	for _, backend := range executeData.Endpoint.Backends() {
-		httpBackendRequest := e.buildHTTPBackendRequest(ctx, executeData, &backend, history)
+	backends := executeData.Endpoint.Backends()
+	dependencies := e.buildDependencies(backends)
+	executions := make([]backendExecution, len(backends))
+
+	// every backend has its own context, so an abort only stops the backends declared after the aborted one
+	done := make([]chan struct{}, len(backends))
+	backendCtxs := make([]context.Context, len(backends))
+	backendCancels := make([]context.CancelFunc, len(backends))
+	for i := range backends {
+		done[i] = make(chan struct{})
+		backendCtxs[i], backendCancels[i] = context.WithCancel(executeCtx)
+	}
+	panics := make([]any, len(backends))
+
+	// every backend runs as soon as the backends it depends on are finished, the results are kept by index
+	for i := range backends {
+		go func(i int) {
+			defer close(done[i])
+			// the panic is raised again on the request goroutine, so the panic recovery middleware can handle it
+			defer func() {
+				if r := recover(); checker.NonNil(r) {
+					panics[i] = r
+					cancel()
+				}
+			}()
+			backendCtx := backendCtxs[i]
+			if !e.waitDependencies(backendCtx, dependencies[i], executions, done) {
+				return
+			}
+
+			backend := &backends[i]
+			history := e.buildDependencyHistory(backends, executions, dependencies[i], i)
+			if e.checkSkipBackend(executeData, backend, history) {
+				executions[i] = backendExecution{skipped: true}
+				return
+			}
+
+			var httpBackendRequest *vo.HTTPBackendRequest
+			var httpBackendResponse *vo.HTTPBackendResponse
+			if backend.HasForeach() {
+				httpBackendRequest, httpBackendResponse = e.makeForeachBackendRequest(backendCtx, executeData, backend,
+					history)
+			} else {
+				httpBackendRequest, httpBackendResponse = e.executeBackend(backendCtx, executeData, backend, history)
+			}
+
+			aborted := checker.NonNil(httpBackendResponse) &&
+				e.checkAbortBackendResponse(executeData.Endpoint, httpBackendResponse)
+			executions[i] = backendExecution{
+				executed: true,
+				aborted:  aborted,
+				request:  httpBackendRequest,
+				response: httpBackendResponse,
+			}
+			if aborted {
+				for _, cancelBackend := range backendCancels[i+1:] {
+					cancelBackend()
+				}
+			}
+		}(i)
+	}
+	for i := range done {
+		<-done[i]
+	}
+	for _, r := range panics {
+		if checker.NonNil(r) {
+			panic(r)
+		}
+	}
 
-		var httpBackendResponse *vo.HTTPBackendResponse
-		if backend.HasRequest() && backend.Request().IsConcurrent() {
-			httpBackendResponse = e.makeConcurrentBackendRequest(ctx, &backend, executeData, httpBackendRequest)
-		} else {
-			httpBackendResponse = e.makeBackendRequest(ctx, executeData, &backend, httpBackendRequest)
+	history := vo.NewEmptyHistory()
+	for i := range backends {
+		execution := executions[i]
+		if execution.skipped {
+			history = history.Skip(&backends[i])
+			continue
+		} else if !execution.executed {
+			continue
 		}
 
-		history = history.Add(&backend, httpBackendRequest, httpBackendResponse)
-		if e.checkAbortBackendResponse(executeData.Endpoint, httpBackendResponse) {
-			return e.buildAbortedHTTPResponse(executeData, history)
+		history = history.Add(&backends[i], execution.request, execution.response)
+		if execution.aborted {
+			return e.buildAbortedHTTPResponse(executeData, history.Compact())
 		}
 	}
 
 	return e.buildHTTPResponse(ctx, executeData, history)
 }
 
+func (e endpointUseCase) buildDependencies(backends []vo.Backend) [][]int {
+	dependencies := make([][]int, len(backends))
+	for i, backend := range backends {
+		indexes, all := e.dynamicValueService.FindResponseIndexes(backend.DynamicValues())
+
+		// middlewares keep running in sequence, beforewares before every backend and afterwares after them
+		for j := 0; j < i; j++ {
+			if all || !backend.IsNormal() || backends[j].IsBeforeware() || slices.Contains(indexes, j) {
+				dependencies[i] = append(dependencies[i], j)
+			}
+		}
+	}
+	return dependencies
+}
+
+func (e endpointUseCase) waitDependencies(ctx context.Context, dependencies []int, executions []backendExecution,
+	done []chan struct{}) bool {
+	for _, dependency := range dependencies {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-done[dependency]:
+		}
+
+		// a skipped dependency has no response, but the backends that depend on it can still run
+		execution := executions[dependency]
+		if (!execution.executed && !execution.skipped) || execution.aborted {
+			return false
+		}
+	}
+	return checker.IsNil(ctx.Err())
+}
+
+func (e endpointUseCase) buildDependencyHistory(backends []vo.Backend, executions []backendExecution,
+	dependencies []int, size int) *vo.History {
+	historyBackends := make([]*vo.Backend, size)
+	historyRequests := make([]*vo.HTTPBackendRequest, size)
+	historyResponses := make([]*vo.HTTPBackendResponse, size)
+
+	for _, dependency := range dependencies {
+		historyBackends[dependency] = &backends[dependency]
+		historyRequests[dependency] = executions[dependency].request
+		historyResponses[dependency] = executions[dependency].response
+	}
+
+	return vo.NewHistory(historyBackends, historyRequests, historyResponses, nil)
+}
+
+func (e endpointUseCase) checkSkipBackend(executeData dto.ExecuteEndpoint, backend *vo.Backend, history *vo.History,
+) bool {
+	if backend.HasOnlyIf() {
+		result, errs := e.conditionService.Evaluate(backend.OnlyIf(), executeData.Request, history)
+		e.printEndpointWarns(executeData, errs)
+		if !result {
+			e.printEndpointInfof(executeData, "Backend %s skipped by only-if", backend.Path())
+			return true
+		}
+	}
+	if backend.HasIgnoreIf() {
+		result, errs := e.conditionService.Evaluate(backend.IgnoreIf(), executeData.Request, history)
+		e.printEndpointWarns(executeData, errs)
+		if result {
+			e.printEndpointInfof(executeData, "Backend %s skipped by ignore-if", backend.Path())
+			return true
+		}
+	}
+	return false
+}
+
+func (e endpointUseCase) executeBackend(ctx context.Context, executeData dto.ExecuteEndpoint, backend *vo.Backend,
+	history *vo.History) (*vo.HTTPBackendRequest, *vo.HTTPBackendResponse) {
+	httpBackendRequest := e.buildHTTPBackendRequest(ctx, executeData, backend, history)
+
+	var httpBackendResponse *vo.HTTPBackendResponse
+	if backend.HasRequest() && backend.Request().IsConcurrent() {
+		httpBackendResponse = e.makeConcurrentBackendRequest(ctx, backend, executeData, httpBackendRequest)
+	} else {
+		httpBackendResponse = e.makeBackendRequest(ctx, executeData, backend, httpBackendRequest)
+	}
+
+	if backend.HasFallback() && checker.NonNil(httpBackendResponse) && httpBackendResponse.StatusCode().ServerError() {
+		return e.makeFallbackBackendRequest(ctx, executeData, backend, httpBackendRequest, httpBackendResponse, history)
+	}
+	return httpBackendRequest, httpBackendResponse
+}
+
+func (e endpointUseCase) makeFallbackBackendRequest(
+	ctx context.Context,
+	executeData dto.ExecuteEndpoint,
+	backend *vo.Backend,
+	httpBackendRequest *vo.HTTPBackendRequest,
+	httpBackendResponse *vo.HTTPBackendResponse,
+	history *vo.History,
+) (*vo.HTTPBackendRequest, *vo.HTTPBackendResponse) {
+	e.backendLog.PrintWarnf(executeData, backend, httpBackendRequest, "FALLBACK cause: %s",
+		httpBackendResponse.StatusCode().String())
+
+	fallback := backend.Fallback()
+	if fallback.HasBackend() {
+		return e.executeBackend(ctx, executeData, fallback.Backend(), history)
+	}
+	return httpBackendRequest, e.httpBackendFactory.BuildFallbackResponse(fallback.Response())
+}
+
+func (e endpointUseCase) makeForeachBackendRequest(ctx context.Context, executeData dto.ExecuteEndpoint,
+	backend *vo.Backend, history *vo.History) (*vo.HTTPBackendRequest, *vo.HTTPBackendResponse) {
+	items, err := e.findForeachItems(executeData, backend, history)
+	if checker.NonNil(err) {
+		httpBackendRequest := e.buildHTTPBackendRequest(ctx, executeData, backend, history)
+		e.backendLog.PrintWarn(executeData, backend, httpBackendRequest, err)
+		return httpBackendRequest, e.httpBackendFactory.BuildTemporaryResponseByErr(executeData.Endpoint, err)
+	} else if checker.IsEmpty(items) {
+		httpBackendRequest := e.buildHTTPBackendRequest(ctx, executeData, backend, history)
+		return httpBackendRequest, e.buildForeachResponse(executeData, backend, httpBackendRequest, nil)
+	}
+
+	httpBackendRequests := make([]*vo.HTTPBackendRequest, len(items))
+	httpBackendResponses := make([]*vo.HTTPBackendResponse, len(items))
+	panics := make([]any, len(items))
+
+	// a fixed number of workers, given by the foreach concurrency, takes the item indexes in order
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range min(backend.Foreach().Concurrency(), len(items)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				httpBackendRequests[i], httpBackendResponses[i], panics[i] = e.executeForeachItem(ctx, executeData,
+					backend, history.WithItem(items[i]))
+			}
+		}()
+	}
+feed:
+	for i := range items {
+		select {
+		case <-ctx.Done():
+			break feed
+		case indexes <- i:
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	for _, r := range panics {
+		if checker.NonNil(r) {
+			panic(r)
+		}
+	}
+	if slices.Contains(httpBackendResponses, nil) {
+		return httpBackendRequests[0], nil
+	}
+	return httpBackendRequests[0], e.buildForeachResponse(executeData, backend, httpBackendRequests[0],
+		httpBackendResponses)
+}
+
+func (e endpointUseCase) executeForeachItem(ctx context.Context, executeData dto.ExecuteEndpoint, backend *vo.Backend,
+	history *vo.History) (httpBackendRequest *vo.HTTPBackendRequest, httpBackendResponse *vo.HTTPBackendResponse,
+	recovered any) {
+	// the panic is raised after the wait, so the worker keeps taking the next items
+	defer func() {
+		recovered = recover()
+	}()
+
+	httpBackendRequest, httpBackendResponse = e.executeBackend(ctx, executeData, backend, history)
+	return httpBackendRequest, httpBackendResponse, nil
+}
+
+func (e endpointUseCase) findForeachItems(executeData dto.ExecuteEndpoint, backend *vo.Backend, history *vo.History,
+) ([]string, error) {
+	path := backend.Foreach().Path()
+	value, err := e.dynamicValueService.GetJSONValue(path, executeData.Request, history)
+	if checker.NonNil(err) {
+		return nil, err
+	} else if !value.IsArray() {
+		return nil, mapper.NewErrIncompatibleForeachValue(path)
+	}
+
+	var items []string
+	value.ForEach(func(_ string, item domain.JSONValue) bool {
+		items = append(items, item.Raw())
+		return true
+	})
+	if checker.IsGreaterThan(len(items), backend.Foreach().MaxItems()) {
+		return nil, mapper.NewErrForeachMaxItems(path, len(items), backend.Foreach().MaxItems())
+	}
+	return items, nil
+}
+
+func (e endpointUseCase) buildForeachResponse(executeData dto.ExecuteEndpoint, backend *vo.Backend,
+	httpBackendRequest *vo.HTTPBackendRequest, httpBackendResponses []*vo.HTTPBackendResponse,
+) *vo.HTTPBackendResponse {
+	httpBackendResponse, errs := e.httpBackendFactory.BuildForeachResponse(httpBackendResponses)
+	for _, err := range errs {
+		e.backendLog.PrintWarn(executeData, backend, httpBackendRequest, err)
+	}
+	return httpBackendResponse
+}
+
 func (e endpointUseCase) makeConcurrentBackendRequest(
 	ctx context.Context,
 	backend *vo.Backend,
@@ -84,9 +384,16 @@ func (e endpointUseCase) makeConcurrentBackendRequest(
 	concurrentCtx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	responseChan := make(chan *vo.HTTPBackendResponse)
+	// the channels are buffered so the requests that lose the race can finish without being read
+	responseChan := make(chan *vo.HTTPBackendResponse, backend.Request().Concurrent())
+	panicChan := make(chan any, backend.Request().Concurrent())
 	for i := 0; i < backend.Request().Concurrent(); i++ {
 		go func() {
+			defer func() {
+				if r := recover(); checker.NonNil(r) {
+					panicChan <- r
+				}
+			}()
 			httpBackendResponse := e.makeBackendRequest(concurrentCtx, executeData, backend, httpBackendRequest)
 			responseChan <- httpBackendResponse
 		}()
@@ -95,6 +402,8 @@ func (e endpointUseCase) makeConcurrentBackendRequest(
 	select {
 	case httpBackendResponse := <-responseChan:
 		return httpBackendResponse
+	case r := <-panicChan:
+		panic(r)
 	}
 }
 
@@ -104,10 +413,63 @@ func (e endpointUseCase) makeBackendRequest(
 	backend *vo.Backend,
 	httpBackendRequest *vo.HTTPBackendRequest,
 ) *vo.HTTPBackendResponse {
+	if e.healthService.AllDown(backend.Hosts()) {
+		err := mapper.NewErrServiceUnavailable(backend.Hosts())
+		e.backendLog.PrintWarn(executeData, backend, httpBackendRequest, err)
+		return e.httpBackendFactory.BuildTemporaryResponseByErr(executeData.Endpoint, err)
+	}
+
+	if !e.circuitBreakerService.Allow(backend) {
+		return e.buildCircuitOpenResponse(executeData, backend, httpBackendRequest)
+	}
+
+	httpBackendResponse := e.makeBackendRequestWithRetry(ctx, executeData, backend, httpBackendRequest)
+	e.circuitBreakerService.Record(backend, httpBackendResponse)
+
+	return httpBackendResponse
+}
+
+func (e endpointUseCase) makeBackendRequestWithRetry(
+	ctx context.Context,
+	executeData dto.ExecuteEndpoint,
+	backend *vo.Backend,
+	httpBackendRequest *vo.HTTPBackendRequest,
+) *vo.HTTPBackendResponse {
+	startTime := time.Now()
+	for attempt := 1; ; attempt++ {
+		httpBackendResponse, err := e.makeBackendAttempt(ctx, executeData, backend, httpBackendRequest)
+
+		backoff, retry := e.checkRetry(ctx, backend, httpBackendRequest, httpBackendResponse, err, attempt, startTime)
+		if !retry {
+			return httpBackendResponse
+		}
+
+		e.backendLog.PrintWarnf(executeData, backend, httpBackendRequest, "RETRY attempt: %v/%v | backoff: %vms | cause: %s",
+			attempt+1, backend.Retry().MaxAttempts(), backoff.Milliseconds(), e.buildRetryCause(httpBackendResponse, err))
+
+		select {
+		case <-ctx.Done():
+			return httpBackendResponse
+		case <-time.After(backoff):
+		}
+
+		// the next attempt goes through the balancer again, so we can reach another host
+		httpBackendRequest = vo.NewHTTPBackendRequest(e.balancerService.SelectHost(backend, executeData.Request),
+			httpBackendRequest.Method(), httpBackendRequest.Path(), httpBackendRequest.Header(),
+			httpBackendRequest.Query(), httpBackendRequest.Body())
+	}
+}
+
+func (e endpointUseCase) makeBackendAttempt(
+	ctx context.Context,
+	executeData dto.ExecuteEndpoint,
+	backend *vo.Backend,
+	httpBackendRequest *vo.HTTPBackendRequest,
+) (*vo.HTTPBackendResponse, error) {
 	e.backendLog.PrintRequest(executeData, backend, httpBackendRequest)
 
 	startTime := time.Now()
-	httpResponse, err := e.httpClient.MakeRequest(ctx, httpBackendRequest)
+	httpResponse, err := e.makeHTTPRequest(ctx, backend, httpBackendRequest)
 	duration := time.Since(startTime)
 
 	var httpBackendResponse *vo.HTTPBackendResponse
@@ -119,7 +481,84 @@ func (e endpointUseCase) makeBackendRequest(
 
 	e.backendLog.PrintResponse(executeData, backend, httpBackendRequest, httpBackendResponse, duration)
 
-	return httpBackendResponse
+	// a canceled request has no response, so it is observed with the status code 0
+	statusCode := vo.NewStatusCode(0)
+	if checker.NonNil(httpBackendResponse) {
+		statusCode = httpBackendResponse.StatusCode()
+	}
+	e.metrics.ObserveBackendRequest(httpBackendRequest.Host(), statusCode, duration, err)
+
+	return httpBackendResponse, err
+}
+
+func (e endpointUseCase) makeHTTPRequest(ctx context.Context, backend *vo.Backend,
+	httpBackendRequest *vo.HTTPBackendRequest) (*http.Response, error) {
+	e.balancerService.Acquire(httpBackendRequest.Host())
+	defer e.balancerService.Release(httpBackendRequest.Host())
+
+	e.metrics.IncrementBackendInFlight(httpBackendRequest.Host())
+	defer e.metrics.DecrementBackendInFlight(httpBackendRequest.Host())
+
+	return e.httpClient.MakeRequest(ctx, backend.Transport(), httpBackendRequest)
+}
+
+func (e endpointUseCase) checkRetry(
+	ctx context.Context,
+	backend *vo.Backend,
+	httpBackendRequest *vo.HTTPBackendRequest,
+	httpBackendResponse *vo.HTTPBackendResponse,
+	err error,
+	attempt int,
+	startTime time.Time,
+) (time.Duration, bool) {
+	if !backend.HasRetry() || checker.NonNil(ctx.Err()) || checker.IsNil(httpBackendResponse) {
+		return 0, false
+	}
+
+	retry := backend.Retry()
+	if checker.IsGreaterThanOrEqual(attempt, retry.MaxAttempts()) ||
+		!retry.IsRetryableMethod(httpBackendRequest.Method()) {
+		return 0, false
+	}
+
+	if errors.Is(err, mapper.ErrGatewayTimeout) && !retry.OnTimeout() {
+		return 0, false
+	} else if errors.Is(err, mapper.ErrBadGateway) && !retry.OnConnectionError() {
+		return 0, false
+	} else if checker.IsNil(err) && !retry.IsRetryableStatusCode(httpBackendResponse.StatusCode()) {
+		return 0, false
+	}
+
+	// the next attempt must start within the retry budget and the endpoint deadline
+	backoff := retry.Backoff(attempt)
+	nextAttempt := time.Now().Add(backoff)
+	if retry.HasBudget() && nextAttempt.After(startTime.Add(retry.Budget())) {
+		return 0, false
+	}
+	if deadline, ok := ctx.Deadline(); ok && nextAttempt.After(deadline) {
+		return 0, false
+	}
+
+	return backoff, true
+}
+
+func (e endpointUseCase) buildRetryCause(httpBackendResponse *vo.HTTPBackendResponse, err error) string {
+	if checker.NonNil(err) {
+		return errors.Details(err).Message()
+	}
+	return httpBackendResponse.StatusCode().String()
+}
+
+func (e endpointUseCase) buildCircuitOpenResponse(executeData dto.ExecuteEndpoint, backend *vo.Backend,
+	httpBackendRequest *vo.HTTPBackendRequest) *vo.HTTPBackendResponse {
+	err := mapper.NewErrCircuitOpen(backend.Path())
+	e.backendLog.PrintWarn(executeData, backend, httpBackendRequest, err)
+
+	circuitBreaker := backend.CircuitBreaker()
+	if circuitBreaker.HasFallback() {
+		return e.httpBackendFactory.BuildFallbackResponse(circuitBreaker.Fallback())
+	}
+	return e.httpBackendFactory.BuildTemporaryResponseByErr(executeData.Endpoint, err)
 }
 
 func (e endpointUseCase) treatHTTPClientErr(err error) error {
@@ -146,11 +585,9 @@ func (e endpointUseCase) checkAbortBackendResponse(endpoint *vo.Endpoint, respon
 
 func (e endpointUseCase) buildHTTPBackendRequest(ctx context.Context, executeData dto.ExecuteEndpoint, backend *vo.Backend,
 	history *vo.History) *vo.HTTPBackendRequest {
-	span, _ := apm.StartSpan(ctx, "Backend request", "factory")
-	if checker.NonNil(span) {
-		span.Context.SetLabel("transformations", backend.CountRequestDataTransforms())
-		defer span.End()
-	}
+	_, span := e.tracer.StartSpan(ctx, "Backend request", "factory")
+	span.SetLabel("transformations", backend.CountRequestDataTransforms())
+	defer span.End()
 
 	httpBackendRequest, errs := e.httpBackendFactory.BuildRequest(backend, executeData.Request, history)
 	for _, err := range errs {
@@ -159,13 +596,18 @@ func (e endpointUseCase) buildHTTPBackendRequest(ctx context.Context, executeDat
 	return httpBackendRequest
 }
 
-func (e endpointUseCase) buildHTTPBackendResponse(executeData dto.ExecuteEndpoint, backend *vo.Backend,
-	httpBackendRequest *vo.HTTPBackendRequest, httpBackendResponse *vo.HTTPBackendResponse, history *vo.History,
+func (e endpointUseCase) buildHTTPBackendResponse(ctx context.Context, executeData dto.ExecuteEndpoint,
+	backend *vo.Backend, httpBackendRequest *vo.HTTPBackendRequest, httpBackendResponse *vo.HTTPBackendResponse,
+	history *vo.History,
 ) *vo.HTTPBackendResponse {
 	if !backend.HasResponse() {
 		return httpBackendResponse
 	}
 
+	_, span := e.tracer.StartSpan(ctx, "Backend response", "factory")
+	span.SetLabel("transformations", backend.CountResponseDataTransforms())
+	defer span.End()
+
 	httpBackendResponse, errors := e.httpBackendFactory.BuildResponse(backend, httpBackendResponse, executeData.Request, history)
 	for _, err := range errors {
 		e.backendLog.PrintWarn(executeData, backend, httpBackendRequest, err)
@@ -192,10 +634,8 @@ func (e endpointUseCase) buildHTTPResponse(ctx context.Context, executeData dto.
 
 func (e endpointUseCase) filterHistory(ctx context.Context, executeData dto.ExecuteEndpoint, history *vo.History,
 ) *vo.History {
-	span, _ := apm.StartSpan(ctx, "Response", "factory")
-	if checker.NonNil(span) {
-		defer span.End()
-	}
+	ctx, span := e.tracer.StartSpan(ctx, "Response", "factory")
+	defer span.End()
 
 	var backends []*vo.Backend
 	var requests []*vo.HTTPBackendRequest
@@ -203,8 +643,11 @@ func (e endpointUseCase) filterHistory(ctx context.Context, executeData dto.Exec
 
 	for i := 0; i < history.Size(); i++ {
 		backend, httpBackendRequest, httpBackendTemporaryResponse := history.Get(i)
+		if checker.IsNil(httpBackendTemporaryResponse) {
+			continue
+		}
 
-		httpBackendResponse := e.buildHTTPBackendResponse(executeData, backend, httpBackendRequest,
+		httpBackendResponse := e.buildHTTPBackendResponse(ctx, executeData, backend, httpBackendRequest,
 			httpBackendTemporaryResponse, history)
 
 		if checker.NonNil(httpBackendResponse) {
@@ -214,9 +657,20 @@ func (e endpointUseCase) filterHistory(ctx context.Context, executeData dto.Exec
 		}
 	}
 
-	return vo.NewHistory(backends, requests, responses)
+	return vo.NewHistory(backends, requests, responses, history.Skipped())
 }
 
 func (e endpointUseCase) printEndpointWarn(executeData dto.ExecuteEndpoint, err error) {
 	e.endpointLog.PrintWarn(executeData.Endpoint, executeData.Request, executeData.ClientIP, executeData.TraceID, err)
 }
+
+func (e endpointUseCase) printEndpointWarns(executeData dto.ExecuteEndpoint, errs []error) {
+	for _, err := range errs {
+		e.printEndpointWarn(executeData, err)
+	}
+}
+
+func (e endpointUseCase) printEndpointInfof(executeData dto.ExecuteEndpoint, format string, msg ...any) {
+	e.endpointLog.PrintInfof(executeData.Endpoint, executeData.Request, executeData.ClientIP, executeData.TraceID,
+		format, msg...)
+}
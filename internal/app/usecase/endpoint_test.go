@@ -0,0 +1,454 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"github.com/tech4works/errors"
+	"github.com/tech4works/gopen-gateway/internal/app"
+	"github.com/tech4works/gopen-gateway/internal/app/model/dto"
+	"github.com/tech4works/gopen-gateway/internal/domain/factory"
+	"github.com/tech4works/gopen-gateway/internal/domain/mapper"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/enum"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/vo"
+	"github.com/tech4works/gopen-gateway/internal/domain/service"
+	"github.com/tech4works/gopen-gateway/internal/infra/jsonpath"
+	"github.com/tech4works/gopen-gateway/internal/infra/tracer"
+	"net/http"
+	"net/url"
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeBackendCall struct {
+	statusCode int
+	delay      time.Duration
+}
+
+type fakeBackendHTTPClient struct {
+	app.HTTPClient
+	mutex       *sync.Mutex
+	calls       map[string]fakeBackendCall
+	completed   []string
+	inFlight    int
+	maxInFlight int
+}
+
+type fakeHTTPBackendFactory struct {
+	factory.HTTPBackend
+}
+
+type fakeHTTPResponseFactory struct {
+	factory.HTTPResponse
+	aborted bool
+	history *vo.History
+}
+
+type fakeBackendLog struct {
+	app.BackendLog
+}
+
+type fakeMetrics struct {
+	app.Metrics
+}
+
+func (f *fakeBackendHTTPClient) MakeRequest(ctx context.Context, _ vo.Transport, request *vo.HTTPBackendRequest) (
+	*http.Response, error) {
+	path := request.Path().String()
+	call := f.calls[path]
+
+	f.mutex.Lock()
+	f.inFlight++
+	f.maxInFlight = max(f.maxInFlight, f.inFlight)
+	f.mutex.Unlock()
+	defer func() {
+		f.mutex.Lock()
+		f.inFlight--
+		f.mutex.Unlock()
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil, &url.Error{Op: request.Method(), URL: path, Err: ctx.Err()}
+	case <-time.After(call.delay):
+		f.mutex.Lock()
+		f.completed = append(f.completed, path)
+		f.mutex.Unlock()
+		return &http.Response{StatusCode: call.statusCode, Header: http.Header{}}, nil
+	}
+}
+
+func (f fakeHTTPBackendFactory) BuildRequest(backend *vo.Backend, _ *vo.HTTPRequest, _ *vo.History) (
+	*vo.HTTPBackendRequest, []error) {
+	return vo.NewHTTPBackendRequest(backend.Hosts()[0], backend.Method(), vo.NewURLPath(backend.Path(), nil),
+		vo.NewHeader(nil), vo.NewEmptyQuery(), nil), nil
+}
+
+func (f fakeHTTPBackendFactory) BuildTemporaryResponse(httpResponse *http.Response) *vo.HTTPBackendResponse {
+	return vo.NewHTTPBackendResponse(vo.NewStatusCode(httpResponse.StatusCode), vo.NewHeader(nil), nil)
+}
+
+func (f fakeHTTPBackendFactory) BuildTemporaryResponseByErr(_ *vo.Endpoint, err error) *vo.HTTPBackendResponse {
+	if errors.Is(err, mapper.ErrConcurrentCanceled) {
+		return nil
+	}
+	return vo.NewHTTPBackendResponse(vo.NewStatusCode(http.StatusBadGateway), vo.NewHeader(nil), nil)
+}
+
+func (f fakeHTTPBackendFactory) BuildForeachResponse(responses []*vo.HTTPBackendResponse) (*vo.HTTPBackendResponse,
+	[]error) {
+	return vo.NewHTTPBackendResponse(vo.NewStatusCode(http.StatusOK), vo.NewHeader(nil), nil), nil
+}
+
+func (f *fakeHTTPResponseFactory) BuildAbortedResponse(_ *vo.Endpoint, history *vo.History) *vo.HTTPResponse {
+	f.aborted = true
+	f.history = history
+	return nil
+}
+
+func (f *fakeHTTPResponseFactory) BuildResponse(_ *vo.Endpoint, history *vo.History) (*vo.HTTPResponse, []error) {
+	f.history = history
+	return nil, nil
+}
+
+func (f fakeBackendLog) PrintRequest(dto.ExecuteEndpoint, *vo.Backend, *vo.HTTPBackendRequest) {}
+
+func (f fakeBackendLog) PrintResponse(dto.ExecuteEndpoint, *vo.Backend, *vo.HTTPBackendRequest,
+	*vo.HTTPBackendResponse, time.Duration) {
+}
+
+func (f fakeBackendLog) PrintWarn(dto.ExecuteEndpoint, *vo.Backend, *vo.HTTPBackendRequest, ...any) {}
+
+func (f fakeMetrics) IncrementBackendInFlight(string) {}
+
+func (f fakeMetrics) DecrementBackendInFlight(string) {}
+
+func (f fakeMetrics) ObserveBackendRequest(string, vo.StatusCode, time.Duration, error) {}
+
+func TestEndpointBuildDependencies(t *testing.T) {
+	tests := []struct {
+		name     string
+		backends []vo.Backend
+		want     [][]int
+	}{
+		{
+			name: "independent backends",
+			backends: []vo.Backend{
+				newTestBackend(enum.BackendTypeNormal, ""),
+				newTestBackend(enum.BackendTypeNormal, ""),
+				newTestBackend(enum.BackendTypeNormal, ""),
+			},
+			want: [][]int{nil, nil, nil},
+		},
+		{
+			name: "field of a previous response",
+			backends: []vo.Backend{
+				newTestBackend(enum.BackendTypeNormal, ""),
+				newTestBackend(enum.BackendTypeNormal, ""),
+				newTestBackend(enum.BackendTypeNormal, "#responses.0.body.id"),
+			},
+			want: [][]int{nil, nil, {0}},
+		},
+		{
+			name: "whole previous response",
+			backends: []vo.Backend{
+				newTestBackend(enum.BackendTypeNormal, ""),
+				newTestBackend(enum.BackendTypeNormal, ""),
+				newTestBackend(enum.BackendTypeNormal, "#responses.1"),
+			},
+			want: [][]int{nil, nil, {1}},
+		},
+		{
+			name: "every previous response",
+			backends: []vo.Backend{
+				newTestBackend(enum.BackendTypeNormal, ""),
+				newTestBackend(enum.BackendTypeNormal, ""),
+				newTestBackend(enum.BackendTypeNormal, "#responses.#.body"),
+			},
+			want: [][]int{nil, nil, {0, 1}},
+		},
+		{
+			name: "chained responses",
+			backends: []vo.Backend{
+				newTestBackend(enum.BackendTypeNormal, ""),
+				newTestBackend(enum.BackendTypeNormal, "#responses.0.body.id"),
+				newTestBackend(enum.BackendTypeNormal, "#responses.1.body.id"),
+			},
+			want: [][]int{nil, {0}, {1}},
+		},
+		{
+			name: "fallback backend of a previous response",
+			backends: []vo.Backend{
+				newTestBackend(enum.BackendTypeNormal, ""),
+				newTestFallbackBackend(newTestBackend(enum.BackendTypeNormal, "#responses.0.body.id")),
+			},
+			want: [][]int{nil, {0}},
+		},
+		{
+			name: "request values only",
+			backends: []vo.Backend{
+				newTestBackend(enum.BackendTypeNormal, ""),
+				newTestBackend(enum.BackendTypeNormal, "#request.header.X-Id"),
+			},
+			want: [][]int{nil, nil},
+		},
+		{
+			name: "beforeware",
+			backends: []vo.Backend{
+				newTestBackend(enum.BackendTypeBeforeware, ""),
+				newTestBackend(enum.BackendTypeNormal, ""),
+				newTestBackend(enum.BackendTypeNormal, ""),
+			},
+			want: [][]int{nil, {0}, {0}},
+		},
+		{
+			name: "afterware",
+			backends: []vo.Backend{
+				newTestBackend(enum.BackendTypeNormal, ""),
+				newTestBackend(enum.BackendTypeNormal, ""),
+				newTestBackend(enum.BackendTypeAfterware, ""),
+			},
+			want: [][]int{nil, nil, {0, 1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useCase := endpointUseCase{dynamicValueService: service.NewDynamicValue(jsonpath.New())}
+			if got := useCase.buildDependencies(tt.backends); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("buildDependencies() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEndpointFindForeachItems(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		maxItems int
+		want     []string
+		wantErr  *error
+	}{
+		{"array of numbers", "#request.body.ids", 0, []string{"1", "2", "3"}, nil},
+		{"array of objects", "#request.body.users", 0, []string{`{"id":1}`, `{"id":2}`}, nil},
+		{"empty array", "#request.body.empty", 0, nil, nil},
+		{"items at the limit", "#request.body.ids", 3, []string{"1", "2", "3"}, nil},
+		{"too many items", "#request.body.ids", 2, nil, &mapper.ErrForeachMaxItems},
+		{"object", "#request.body.user", 0, nil, &mapper.ErrIncompatibleForeachValue},
+		{"missing value", "#request.body.missing", 0, nil, &mapper.ErrValueNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useCase := endpointUseCase{dynamicValueService: service.NewDynamicValue(jsonpath.New())}
+			backend := newTestForeachBackend(tt.path, 0, tt.maxItems)
+			executeData := dto.ExecuteEndpoint{
+				Request: vo.NewHTTPRequest(vo.NewURLPath("/users", nil), "/users", "POST", vo.NewHeader(nil),
+					vo.NewEmptyQuery(), vo.NewBodyJson(bytes.NewBufferString(
+						`{"ids":[1,2,3],"users":[{"id":1},{"id":2}],"empty":[],"user":{"id":1}}`))),
+			}
+
+			got, err := useCase.findForeachItems(executeData, &backend, vo.NewEmptyHistory())
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("findForeachItems() err = %v, want nil", err)
+			} else if tt.wantErr != nil && !errors.Is(err, *tt.wantErr) {
+				t.Fatalf("findForeachItems() err = %v, want %v", err, *tt.wantErr)
+			} else if !slices.Equal(got, tt.want) {
+				t.Errorf("findForeachItems() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEndpointMakeForeachBackendRequest(t *testing.T) {
+	tests := []struct {
+		name            string
+		path            string
+		concurrency     int
+		maxItems        int
+		wantStatus      int
+		wantCompleted   int
+		wantMaxInFlight int
+	}{
+		{"every item", "#request.body.ids", 2, 0, http.StatusOK, 7, 2},
+		{"concurrency above the items", "#request.body.ids", 10, 0, http.StatusOK, 7, 7},
+		{"empty array", "#request.body.empty", 2, 0, http.StatusOK, 0, 0},
+		{"too many items", "#request.body.ids", 2, 5, http.StatusBadGateway, 0, 0},
+		{"object", "#request.body.user", 2, 0, http.StatusBadGateway, 0, 0},
+		{"missing value", "#request.body.missing", 2, 0, http.StatusBadGateway, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpClient := &fakeBackendHTTPClient{mutex: &sync.Mutex{}, calls: map[string]fakeBackendCall{
+				"/users/#item": {http.StatusOK, 20 * time.Millisecond},
+			}}
+			useCase := newTestEndpointUseCase(httpClient, &fakeHTTPResponseFactory{}).(endpointUseCase)
+			backend := newTestForeachBackend(tt.path, tt.concurrency, tt.maxItems)
+			endpoint := vo.NewEndpoint("/users", "POST", 0, vo.Limiter{}, nil, nil, nil, nil, nil, nil,
+				[]vo.Backend{backend})
+			executeData := dto.ExecuteEndpoint{
+				Endpoint: &endpoint,
+				Request: vo.NewHTTPRequest(vo.NewURLPath("/users", nil), "/users", "POST", vo.NewHeader(nil),
+					vo.NewEmptyQuery(), vo.NewBodyJson(bytes.NewBufferString(
+						`{"ids":[1,2,3,4,5,6,7],"empty":[],"user":{"id":1}}`))),
+			}
+
+			_, got := useCase.makeForeachBackendRequest(context.Background(), executeData, &backend,
+				vo.NewEmptyHistory())
+			if got == nil {
+				t.Fatalf("makeForeachBackendRequest() response = nil, want status %d", tt.wantStatus)
+			} else if got.StatusCode().Code() != tt.wantStatus {
+				t.Errorf("makeForeachBackendRequest() status code = %d, want %d", got.StatusCode().Code(),
+					tt.wantStatus)
+			}
+			if len(httpClient.completed) != tt.wantCompleted {
+				t.Errorf("completed = %d, want %d", len(httpClient.completed), tt.wantCompleted)
+			}
+			if httpClient.maxInFlight != tt.wantMaxInFlight {
+				t.Errorf("max in flight = %d, want %d", httpClient.maxInFlight, tt.wantMaxInFlight)
+			}
+		})
+	}
+}
+
+func TestEndpointExecuteAbort(t *testing.T) {
+	tests := []struct {
+		name          string
+		calls         map[string]fakeBackendCall
+		wantAborted   bool
+		wantStatus    []int
+		wantCompleted []string
+	}{
+		{
+			name: "without abort",
+			calls: map[string]fakeBackendCall{
+				"/a": {http.StatusOK, 10 * time.Millisecond},
+				"/b": {http.StatusOK, 0},
+				"/c": {http.StatusCreated, 0},
+			},
+			wantStatus:    []int{200, 200, 201},
+			wantCompleted: []string{"/a", "/b", "/c"},
+		},
+		{
+			name: "later abort keeps the previous backends running",
+			calls: map[string]fakeBackendCall{
+				"/a": {http.StatusOK, 50 * time.Millisecond},
+				"/b": {http.StatusInternalServerError, 0},
+				"/c": {http.StatusOK, 50 * time.Millisecond},
+			},
+			wantAborted:   true,
+			wantStatus:    []int{200, 500},
+			wantCompleted: []string{"/a", "/b"},
+		},
+		{
+			name: "abort stops the next backends",
+			calls: map[string]fakeBackendCall{
+				"/a": {http.StatusInternalServerError, 10 * time.Millisecond},
+				"/b": {http.StatusOK, time.Second},
+				"/c": {http.StatusOK, time.Second},
+			},
+			wantAborted:   true,
+			wantStatus:    []int{500},
+			wantCompleted: []string{"/a"},
+		},
+		{
+			name: "first abort in declared order",
+			calls: map[string]fakeBackendCall{
+				"/a": {http.StatusOK, 10 * time.Millisecond},
+				"/b": {http.StatusBadGateway, 30 * time.Millisecond},
+				"/c": {http.StatusInternalServerError, 0},
+			},
+			wantAborted:   true,
+			wantStatus:    []int{200, 502},
+			wantCompleted: []string{"/a", "/b", "/c"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpClient := &fakeBackendHTTPClient{mutex: &sync.Mutex{}, calls: tt.calls}
+			responseFactory := &fakeHTTPResponseFactory{}
+			useCase := newTestEndpointUseCase(httpClient, responseFactory)
+			endpoint := vo.NewEndpoint("/users", "GET", 0, vo.Limiter{}, nil, nil, nil, nil, nil, nil, []vo.Backend{
+				newTestPathBackend("/a"),
+				newTestPathBackend("/b"),
+				newTestPathBackend("/c"),
+			})
+
+			startTime := time.Now()
+			useCase.Execute(context.Background(), dto.ExecuteEndpoint{
+				Endpoint: &endpoint,
+				Request: vo.NewHTTPRequest(vo.NewURLPath("/users", nil), "/users", "GET", vo.NewHeader(nil),
+					vo.NewEmptyQuery(), nil),
+			})
+			if elapsed := time.Since(startTime); elapsed > 500*time.Millisecond {
+				t.Errorf("Execute() took %s, want the aborted backends canceled", elapsed)
+			}
+
+			if responseFactory.aborted != tt.wantAborted {
+				t.Errorf("aborted = %v, want %v", responseFactory.aborted, tt.wantAborted)
+			}
+			var gotStatus []int
+			for i := 0; i < responseFactory.history.Size(); i++ {
+				_, _, response := responseFactory.history.Get(i)
+				gotStatus = append(gotStatus, response.StatusCode().Code())
+			}
+			if !slices.Equal(gotStatus, tt.wantStatus) {
+				t.Errorf("history status codes = %v, want %v", gotStatus, tt.wantStatus)
+			}
+			slices.Sort(httpClient.completed)
+			if !slices.Equal(httpClient.completed, tt.wantCompleted) {
+				t.Errorf("completed = %v, want %v", httpClient.completed, tt.wantCompleted)
+			}
+		})
+	}
+}
+
+func newTestBackend(kind enum.BackendType, dynamicValue string) vo.Backend {
+	var request *vo.BackendRequest
+	if dynamicValue != "" {
+		request = vo.NewBackendRequestOnlyModifiers([]vo.Modifier{
+			vo.NewModifier(enum.ModifierActionSet, false, "X-Value", dynamicValue),
+		}, nil, nil, nil)
+	}
+	return vo.NewBackend(kind, []string{"http://localhost:8080"}, "/users", "GET", nil, nil, nil, nil, nil, nil, nil, nil,
+		vo.Transport{}, request, nil)
+}
+
+func newTestForeachBackend(path string, concurrency, maxItems int) vo.Backend {
+	return vo.NewBackend(enum.BackendTypeNormal, []string{"http://localhost:8080"}, "/users/#item", "GET", nil, nil,
+		nil, nil, nil, nil, vo.NewForeach(path, concurrency, maxItems), nil, vo.Transport{}, nil, nil)
+}
+
+func newTestFallbackBackend(backend vo.Backend) vo.Backend {
+	return vo.NewBackend(enum.BackendTypeNormal, []string{"http://localhost:8080"}, "/users", "GET", nil, nil, nil,
+		nil, nil, nil, nil, vo.NewFallback(nil, &backend), vo.Transport{}, nil, nil)
+}
+
+func newTestPathBackend(path string) vo.Backend {
+	return vo.NewBackend(enum.BackendTypeNormal, []string{"http://localhost:8080"}, path, "GET", nil, nil, nil, nil, nil,
+		nil, nil, nil, vo.Transport{}, nil, nil)
+}
+
+func newTestEndpointUseCase(httpClient app.HTTPClient, responseFactory factory.HTTPResponse) Endpoint {
+	healthService := service.NewHealth()
+	return NewEndpoint(fakeHTTPBackendFactory{}, responseFactory, service.NewBalancer(healthService), healthService,
+		service.NewCircuitBreaker(), service.NewDynamicValue(jsonpath.New()), nil, httpClient, nil, fakeBackendLog{},
+		fakeMetrics{}, tracer.NewNoop())
+}
@@ -0,0 +1,238 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package usecase
+
+import (
+	"context"
+	"github.com/tech4works/checker"
+	"github.com/tech4works/errors"
+	"github.com/tech4works/gopen-gateway/internal/app"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/vo"
+	"github.com/tech4works/gopen-gateway/internal/domain/service"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+type healthUseCase struct {
+	healthService service.Health
+	httpClient    app.HTTPClient
+	log           app.BootLog
+	cancel        context.CancelFunc
+	waitGroup     *sync.WaitGroup
+}
+
+type Health interface {
+	Start(gopen *vo.Gopen)
+	Stop()
+	Report() []vo.HostHealth
+	Probe(ctx context.Context, gopen *vo.Gopen) []vo.HostHealth
+}
+
+type healthTarget struct {
+	host        string
+	healthCheck *vo.HealthCheck
+	transport   vo.Transport
+}
+
+func NewHealth(healthService service.Health, httpClient app.HTTPClient, log app.BootLog) Health {
+	return &healthUseCase{
+		healthService: healthService,
+		httpClient:    httpClient,
+		log:           log,
+		waitGroup:     &sync.WaitGroup{},
+	}
+}
+
+func (h *healthUseCase) Start(gopen *vo.Gopen) {
+	targets := h.buildTargets(gopen)
+
+	var hosts []string
+	for _, target := range targets {
+		hosts = append(hosts, target.host)
+	}
+	h.healthService.Retain(hosts)
+
+	if checker.IsEmpty(targets) {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	h.cancel = cancel
+
+	for _, target := range targets {
+		h.log.PrintInfof("Health checking host %s every %s", target.host, target.healthCheck.Interval())
+
+		h.waitGroup.Add(1)
+		go h.watch(ctx, target)
+	}
+}
+
+func (h *healthUseCase) Stop() {
+	if checker.IsNil(h.cancel) {
+		return
+	}
+	h.cancel()
+	h.waitGroup.Wait()
+}
+
+func (h *healthUseCase) Report() []vo.HostHealth {
+	return h.healthService.Report()
+}
+
+func (h *healthUseCase) Probe(ctx context.Context, gopen *vo.Gopen) []vo.HostHealth {
+	targets := h.buildProbeTargets(gopen)
+	result := make([]vo.HostHealth, len(targets))
+
+	var waitGroup sync.WaitGroup
+	for i, target := range targets {
+		waitGroup.Add(1)
+		go func(i int, target healthTarget) {
+			defer waitGroup.Done()
+
+			if err := h.probe(ctx, target); checker.NonNil(err) {
+				result[i] = vo.NewHostHealthDown(target.host, 1, err.Error())
+			} else {
+				result[i] = vo.NewHostHealthUp(target.host)
+			}
+		}(i, target)
+	}
+	waitGroup.Wait()
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Host() < result[j].Host()
+	})
+	return result
+}
+
+func (h *healthUseCase) buildTargets(gopen *vo.Gopen) []healthTarget {
+	var targets []healthTarget
+
+	keys := map[string]bool{}
+	for _, endpoint := range gopen.Endpoints() {
+		for _, backend := range endpoint.Backends() {
+			if !backend.HasHealthCheck() {
+				continue
+			}
+			for _, host := range backend.Hosts() {
+				if keys[host] {
+					continue
+				}
+				keys[host] = true
+				targets = append(targets, healthTarget{
+					host:        host,
+					healthCheck: backend.HealthCheck(),
+					transport:   backend.Transport(),
+				})
+			}
+		}
+	}
+
+	return targets
+}
+
+func (h *healthUseCase) buildProbeTargets(gopen *vo.Gopen) []healthTarget {
+	var targets []healthTarget
+
+	indexes := map[string]int{}
+	for _, endpoint := range gopen.Endpoints() {
+		for _, backend := range endpoint.Backends() {
+			for _, host := range backend.Hosts() {
+				index, exists := indexes[host]
+				if exists && checker.NonNil(targets[index].healthCheck) {
+					continue
+				}
+				// a host configured with health-check is probed by it, otherwise only its reachability is verified
+				target := healthTarget{
+					host:        host,
+					healthCheck: backend.HealthCheck(),
+					transport:   backend.Transport(),
+				}
+				if exists {
+					targets[index] = target
+					continue
+				}
+				indexes[host] = len(targets)
+				targets = append(targets, target)
+			}
+		}
+	}
+
+	return targets
+}
+
+func (h *healthUseCase) watch(ctx context.Context, target healthTarget) {
+	defer h.waitGroup.Done()
+
+	ticker := time.NewTicker(target.healthCheck.Interval())
+	defer ticker.Stop()
+
+	for {
+		h.check(ctx, target)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (h *healthUseCase) check(ctx context.Context, target healthTarget) {
+	err := h.probe(ctx, target)
+	if checker.NonNil(ctx.Err()) {
+		return
+	}
+
+	if checker.NonNil(err) {
+		if h.healthService.MarkDown(target.host, err.Error(), target.healthCheck.UnhealthyThreshold()) {
+			h.log.PrintWarnf("Host %s is down: %s", target.host, err)
+		}
+	} else if h.healthService.MarkUp(target.host, target.healthCheck.HealthyThreshold()) {
+		h.log.PrintInfof("Host %s is up again!", target.host)
+	}
+}
+
+func (h *healthUseCase) probe(ctx context.Context, target healthTarget) error {
+	path := "/"
+	if checker.NonNil(target.healthCheck) {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, target.healthCheck.Timeout())
+		defer cancel()
+
+		path = target.healthCheck.Path()
+	}
+
+	urlPath := vo.NewURLPath(path, nil)
+	request := vo.NewHTTPBackendRequest(target.host, http.MethodGet, urlPath, vo.NewHeader(nil), vo.NewEmptyQuery(),
+		nil)
+
+	httpResponse, err := h.httpClient.MakeRequest(ctx, target.transport, request)
+	if checker.NonNil(err) {
+		return err
+	}
+	defer httpResponse.Body.Close()
+	io.Copy(io.Discard, httpResponse.Body)
+
+	statusCode := vo.NewStatusCode(httpResponse.StatusCode)
+	if checker.NonNil(target.healthCheck) && !target.healthCheck.IsExpectedStatusCode(statusCode) {
+		return errors.Newf("unexpected status code %s", statusCode)
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package usecase
+
+import (
+	"context"
+	"github.com/tech4works/errors"
+	"github.com/tech4works/gopen-gateway/internal/app"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/enum"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/vo"
+	"github.com/tech4works/gopen-gateway/internal/domain/service"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeHTTPClient struct {
+	app.HTTPClient
+	statusCodes []int
+	requests    []*vo.HTTPBackendRequest
+}
+
+type fakeBootLog struct {
+	app.BootLog
+}
+
+func (f *fakeHTTPClient) MakeRequest(_ context.Context, _ vo.Transport, request *vo.HTTPBackendRequest) (
+	*http.Response, error) {
+	f.requests = append(f.requests, request)
+
+	// the last status code is kept for the next probes
+	statusCode := f.statusCodes[0]
+	if len(f.statusCodes) > 1 {
+		f.statusCodes = f.statusCodes[1:]
+	}
+	if statusCode == 0 {
+		return nil, errors.New("connection refused")
+	}
+	return &http.Response{StatusCode: statusCode, Body: io.NopCloser(strings.NewReader(""))}, nil
+}
+
+func (f fakeBootLog) PrintInfof(string, ...any) {}
+
+func (f fakeBootLog) PrintWarnf(string, ...any) {}
+
+func TestHealthCheck(t *testing.T) {
+	tests := []struct {
+		name        string
+		healthCheck *vo.HealthCheck
+		statusCodes []int
+		want        []bool
+	}{
+		{"up", newTestHealthCheck(nil), []int{200}, []bool{false}},
+		{"connection error", newTestHealthCheck(nil), []int{0}, []bool{true}},
+		{"server error", newTestHealthCheck(nil), []int{503}, []bool{true}},
+		{"expected status code", newTestHealthCheck([]int{204}), []int{200, 204}, []bool{true, false}},
+		{"down and up again", newTestHealthCheck(nil), []int{500, 500, 200}, []bool{true, true, false}},
+		{
+			name:        "default thresholds",
+			healthCheck: vo.NewHealthCheck("/health", 0, 0, nil, 0, 0),
+			statusCodes: []int{500, 500, 500, 200, 500, 200, 200},
+			want:        []bool{false, false, true, true, true, true, false},
+		},
+		{
+			name:        "configured thresholds",
+			healthCheck: vo.NewHealthCheck("/health", 0, 0, nil, 2, 3),
+			statusCodes: []int{0, 200, 0, 0, 200, 200, 200},
+			want:        []bool{false, false, false, true, true, true, false},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			healthService := service.NewHealth()
+			httpClient := &fakeHTTPClient{statusCodes: tt.statusCodes}
+			useCase := NewHealth(healthService, httpClient, fakeBootLog{}).(*healthUseCase)
+			target := newTestHealthTarget("http://localhost:8080", tt.healthCheck)
+
+			var got []bool
+			for range tt.statusCodes {
+				useCase.check(context.Background(), target)
+				got = append(got, healthService.IsDown(target.host))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("IsDown() after every check = %v, want %v", got, tt.want)
+			}
+			if path := httpClient.requests[0].Path().String(); path != "/health" {
+				t.Errorf("probe path = %s, want /health", path)
+			}
+		})
+	}
+}
+
+func TestHealthStart(t *testing.T) {
+	healthService := service.NewHealth()
+	httpClient := &fakeHTTPClient{statusCodes: []int{200, 500, 500}}
+	useCase := NewHealth(healthService, httpClient, fakeBootLog{})
+
+	healthCheck := vo.NewHealthCheck("/health", vo.NewDuration(5*time.Millisecond), 0, nil, 2, 1)
+	backend := vo.NewBackend(enum.BackendTypeNormal, []string{"http://localhost:8080"}, "/users", "GET", nil,
+		healthCheck, nil, nil, nil, nil, nil, nil, vo.Transport{}, nil, nil)
+	endpoint := vo.NewEndpoint("/users", "GET", 0, vo.Limiter{}, nil, nil, nil, nil, nil, nil, []vo.Backend{backend})
+
+	useCase.Start(vo.NewGopen(nil, nil, nil, nil, []vo.Endpoint{endpoint}))
+	defer useCase.Stop()
+
+	deadline := time.Now().Add(time.Second)
+	for !healthService.IsDown("http://localhost:8080") {
+		if time.Now().After(deadline) {
+			t.Fatal("host not marked down by the watcher")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	useCase.Stop()
+	if got := len(httpClient.requests); got < 3 {
+		t.Errorf("probes = %d, want at least 3", got)
+	}
+	report := useCase.Report()
+	if len(report) != 1 || report[0].Failures() < 2 {
+		t.Errorf("Report() = %+v, want the host down after 2 failures", report)
+	}
+}
+
+func newTestHealthCheck(expectedStatusCodes []int) *vo.HealthCheck {
+	return vo.NewHealthCheck("/health", 0, 0, expectedStatusCodes, 1, 1)
+}
+
+func newTestHealthTarget(host string, healthCheck *vo.HealthCheck) healthTarget {
+	return healthTarget{host: host, healthCheck: healthCheck}
+}
@@ -0,0 +1,117 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package usecase
+
+import (
+	"context"
+	"github.com/tech4works/checker"
+	"github.com/tech4works/gopen-gateway/internal/domain"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/enum"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/vo"
+	"sync/atomic"
+	"time"
+)
+
+type readinessUseCase struct {
+	store         domain.Store
+	limiterStore  domain.LimiterStore
+	healthUseCase Health
+	reloading     atomic.Bool
+}
+
+type Readiness interface {
+	Check(ctx context.Context, gopen *vo.Gopen) vo.ReadinessReport
+	MarkReloading(reloading bool)
+}
+
+func NewReadiness(store domain.Store, limiterStore domain.LimiterStore, healthUseCase Health) Readiness {
+	return &readinessUseCase{
+		store:         store,
+		limiterStore:  limiterStore,
+		healthUseCase: healthUseCase,
+	}
+}
+
+func (r *readinessUseCase) Check(ctx context.Context, gopen *vo.Gopen) vo.ReadinessReport {
+	ctx, cancel := context.WithTimeout(ctx, gopen.Readiness().Timeout())
+	defer cancel()
+
+	checks := []vo.DependencyCheck{r.checkPing(ctx, enum.DependencyKindStore, "cache", r.store.Ping)}
+	if checker.NonNil(r.limiterStore) {
+		checks = append(checks, r.checkPing(ctx, enum.DependencyKindLimiterStore, "limiter", r.limiterStore.Ping))
+	}
+
+	ready := !r.reloading.Load()
+	for _, check := range checks {
+		if check.IsDown() {
+			ready = false
+		}
+	}
+
+	hostChecks, hostsReady := r.checkHosts(ctx, gopen)
+	checks = append(checks, hostChecks...)
+
+	return vo.NewReadinessReport(r.reloading.Load(), checks, ready && hostsReady)
+}
+
+func (r *readinessUseCase) MarkReloading(reloading bool) {
+	r.reloading.Store(reloading)
+}
+
+func (r *readinessUseCase) checkPing(ctx context.Context, kind enum.DependencyKind, name string,
+	ping func(ctx context.Context) error) vo.DependencyCheck {
+	startTime := time.Now()
+	err := ping(ctx)
+	return vo.NewDependencyCheck(kind, name, err, time.Since(startTime))
+}
+
+func (r *readinessUseCase) checkHosts(ctx context.Context, gopen *vo.Gopen) ([]vo.DependencyCheck, bool) {
+	var hostHealths []vo.HostHealth
+	if gopen.Readiness().ProbeHosts() {
+		hostHealths = r.healthUseCase.Probe(ctx, gopen)
+	} else {
+		hostHealths = r.healthUseCase.Report()
+	}
+
+	var checks []vo.DependencyCheck
+	downHosts := map[string]bool{}
+	for _, hostHealth := range hostHealths {
+		checks = append(checks, vo.NewDependencyCheckByHostHealth(hostHealth))
+		if hostHealth.IsDown() {
+			downHosts[hostHealth.Host()] = true
+		}
+	}
+
+	// a single host down is handled by the balancer, we are only not ready when a backend has no host left
+	for _, endpoint := range gopen.Endpoints() {
+		for _, backend := range endpoint.Backends() {
+			if r.allDown(backend.Hosts(), downHosts) {
+				return checks, false
+			}
+		}
+	}
+	return checks, true
+}
+
+func (r *readinessUseCase) allDown(hosts []string, downHosts map[string]bool) bool {
+	for _, host := range hosts {
+		if !downHosts[host] {
+			return false
+		}
+	}
+	return checker.IsNotEmpty(hosts)
+}
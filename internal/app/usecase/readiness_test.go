@@ -0,0 +1,158 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package usecase
+
+import (
+	"context"
+	"github.com/tech4works/errors"
+	"github.com/tech4works/gopen-gateway/internal/domain"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/enum"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/vo"
+	"testing"
+)
+
+type fakeStore struct {
+	domain.Store
+	err error
+}
+
+type fakeLimiterStore struct {
+	domain.LimiterStore
+	err error
+}
+
+type fakeHealth struct {
+	Health
+	report []vo.HostHealth
+	probe  []vo.HostHealth
+}
+
+func (f fakeStore) Ping(context.Context) error {
+	return f.err
+}
+
+func (f fakeLimiterStore) Ping(context.Context) error {
+	return f.err
+}
+
+func (f fakeHealth) Report() []vo.HostHealth {
+	return f.report
+}
+
+func (f fakeHealth) Probe(context.Context, *vo.Gopen) []vo.HostHealth {
+	return f.probe
+}
+
+func TestReadinessCheck(t *testing.T) {
+	tests := []struct {
+		name         string
+		storeErr     error
+		limiterStore domain.LimiterStore
+		health       fakeHealth
+		probeHosts   bool
+		reloading    bool
+		hosts        [][]string
+		want         bool
+		wantChecks   int
+	}{
+		{
+			name:       "all up",
+			hosts:      [][]string{{"http://a"}},
+			want:       true,
+			wantChecks: 1,
+		},
+		{
+			name:       "store down",
+			storeErr:   errors.New("connection refused"),
+			hosts:      [][]string{{"http://a"}},
+			want:       false,
+			wantChecks: 1,
+		},
+		{
+			name:         "limiter store down",
+			limiterStore: fakeLimiterStore{err: errors.New("connection refused")},
+			hosts:        [][]string{{"http://a"}},
+			want:         false,
+			wantChecks:   2,
+		},
+		{
+			name:       "reloading",
+			reloading:  true,
+			hosts:      [][]string{{"http://a"}},
+			want:       false,
+			wantChecks: 1,
+		},
+		{
+			name:       "one host of the backend down",
+			health:     fakeHealth{report: []vo.HostHealth{vo.NewHostHealthDown("http://a", 3, "timeout")}},
+			hosts:      [][]string{{"http://a", "http://b"}},
+			want:       true,
+			wantChecks: 2,
+		},
+		{
+			name: "every host of a backend down",
+			health: fakeHealth{report: []vo.HostHealth{
+				vo.NewHostHealthDown("http://a", 3, "timeout"),
+				vo.NewHostHealthDown("http://b", 3, "timeout"),
+			}},
+			hosts:      [][]string{{"http://a", "http://b"}, {"http://c"}},
+			want:       false,
+			wantChecks: 3,
+		},
+		{
+			name: "probe hosts",
+			health: fakeHealth{
+				report: []vo.HostHealth{vo.NewHostHealthUp("http://a")},
+				probe:  []vo.HostHealth{vo.NewHostHealthDown("http://a", 1, "connection refused")},
+			},
+			probeHosts: true,
+			hosts:      [][]string{{"http://a"}},
+			want:       false,
+			wantChecks: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useCase := NewReadiness(fakeStore{err: tt.storeErr}, tt.limiterStore, tt.health)
+			useCase.MarkReloading(tt.reloading)
+
+			report := useCase.Check(context.Background(), newTestReadinessGopen(tt.probeHosts, tt.hosts))
+			if report.IsReady() != tt.want {
+				t.Errorf("IsReady() = %v, want %v", report.IsReady(), tt.want)
+			}
+			if report.IsReloading() != tt.reloading {
+				t.Errorf("IsReloading() = %v, want %v", report.IsReloading(), tt.reloading)
+			}
+			if len(report.Checks()) != tt.wantChecks {
+				t.Errorf("len(Checks()) = %d, want %d", len(report.Checks()), tt.wantChecks)
+			}
+			if kind := report.Checks()[0].Kind(); kind != enum.DependencyKindStore {
+				t.Errorf("Checks()[0].Kind() = %s, want %s", kind, enum.DependencyKindStore)
+			}
+		})
+	}
+}
+
+func newTestReadinessGopen(probeHosts bool, hosts [][]string) *vo.Gopen {
+	var backends []vo.Backend
+	for _, backendHosts := range hosts {
+		backends = append(backends, vo.NewBackend(enum.BackendTypeNormal, backendHosts, "/users", "GET", nil, nil, nil,
+			nil, nil, nil, nil, nil, vo.Transport{}, nil, nil))
+	}
+	endpoint := vo.NewEndpoint("/users", "GET", 0, vo.Limiter{}, nil, nil, nil, nil, nil, nil, backends)
+	return vo.NewGopen(nil, nil, vo.NewReadiness(probeHosts, 0), nil, []vo.Endpoint{endpoint})
+}
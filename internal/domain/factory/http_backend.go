@@ -27,7 +27,6 @@ import (
 	"github.com/tech4works/gopen-gateway/internal/domain/model/vo"
 	"github.com/tech4works/gopen-gateway/internal/domain/service"
 	"io"
-	"math/rand"
 	"net/http"
 	"time"
 )
@@ -41,18 +40,22 @@ type httpBackendFactory struct {
 	nomenclatureService service.Nomenclature
 	contentService      service.Content
 	aggregatorService   service.Aggregator
+	balancerService     service.Balancer
 }
 
 type HTTPBackend interface {
 	BuildRequest(backend *vo.Backend, request *vo.HTTPRequest, history *vo.History) (*vo.HTTPBackendRequest, []error)
 	BuildTemporaryResponse(httpResponse *http.Response) *vo.HTTPBackendResponse
 	BuildTemporaryResponseByErr(endpoint *vo.Endpoint, err error) *vo.HTTPBackendResponse
+	BuildFallbackResponse(fallback *vo.FallbackResponse) *vo.HTTPBackendResponse
+	BuildForeachResponse(responses []*vo.HTTPBackendResponse) (*vo.HTTPBackendResponse, []error)
 	BuildResponse(backend *vo.Backend, temporaryResponse *vo.HTTPBackendResponse, request *vo.HTTPRequest, history *vo.History) (*vo.HTTPBackendResponse, []error)
 }
 
 func NewHTTPBackend(mapperService service.Mapper, projectorService service.Projector,
 	dynamicValueService service.DynamicValue, modifierService service.Modifier, omitterService service.Omitter,
 	nomenclatureService service.Nomenclature, contentService service.Content, aggregatorService service.Aggregator,
+	balancerService service.Balancer,
 ) HTTPBackend {
 	return httpBackendFactory{
 		mapperService:       mapperService,
@@ -63,12 +66,13 @@ func NewHTTPBackend(mapperService service.Mapper, projectorService service.Proje
 		nomenclatureService: nomenclatureService,
 		contentService:      contentService,
 		aggregatorService:   aggregatorService,
+		balancerService:     balancerService,
 	}
 }
 
 func (f httpBackendFactory) BuildRequest(backend *vo.Backend, request *vo.HTTPRequest, history *vo.History) (
 	*vo.HTTPBackendRequest, []error) {
-	host := f.buildRequestBalancedHost(backend)
+	host := f.balancerService.SelectHost(backend, request)
 	body, bodyErrs := f.buildRequestBody(backend, request, history)
 	urlPath, urlPathErrs := f.buildRequestURLPath(backend, request, history)
 	header, headerErrs := f.buildRequestHeader(backend, body, request, history)
@@ -113,6 +117,11 @@ func (f httpBackendFactory) BuildTemporaryResponseByErr(endpoint *vo.Endpoint, e
 		code = http.StatusGatewayTimeout
 	} else if errors.Is(err, mapper.ErrBadGateway) {
 		code = http.StatusBadGateway
+	} else if errors.Is(err, mapper.ErrServiceUnavailable) || errors.Is(err, mapper.ErrCircuitOpen) {
+		code = http.StatusServiceUnavailable
+	} else if errors.Is(err, mapper.ErrValueNotFound) || errors.Is(err, mapper.ErrIncompatibleForeachValue) ||
+		errors.Is(err, mapper.ErrForeachMaxItems) {
+		code = http.StatusUnprocessableEntity
 	}
 	statusCode := vo.NewStatusCode(code)
 
@@ -130,6 +139,37 @@ func (f httpBackendFactory) BuildTemporaryResponseByErr(endpoint *vo.Endpoint, e
 	return vo.NewHTTPBackendResponse(statusCode, header, body)
 }
 
+func (f httpBackendFactory) BuildFallbackResponse(fallback *vo.FallbackResponse) *vo.HTTPBackendResponse {
+	var body *vo.Body
+	if bodyStr, ok := fallback.Body().(string); ok {
+		body = vo.NewBodyWithContentType(vo.NewContentTypeTextPlain(), bytes.NewBufferString(bodyStr))
+	} else if fallback.HasBody() {
+		body = vo.NewBodyJson(converter.ToBuffer(fallback.Body()))
+	}
+
+	values := vo.NewHeaderByBody(body).Copy()
+	for key, value := range fallback.Header() {
+		values[http.CanonicalHeaderKey(key)] = []string{value}
+	}
+
+	return vo.NewHTTPBackendResponse(fallback.StatusCode(), vo.NewHeader(values), body)
+}
+
+func (f httpBackendFactory) BuildForeachResponse(responses []*vo.HTTPBackendResponse) (*vo.HTTPBackendResponse,
+	[]error) {
+	// the first failed item defines the status code, so the endpoint can abort like with a single response
+	statusCode := vo.NewStatusCode(http.StatusOK)
+	for _, httpBackendResponse := range responses {
+		if httpBackendResponse.StatusCode().Failed() {
+			statusCode = httpBackendResponse.StatusCode()
+			break
+		}
+	}
+
+	body, errs := f.aggregatorService.AggregateResponseBodiesIntoArray(responses)
+	return vo.NewHTTPBackendResponse(statusCode, vo.NewHeaderByBody(body), body), errs
+}
+
 func (f httpBackendFactory) BuildResponse(backend *vo.Backend, temporaryResponse *vo.HTTPBackendResponse,
 	request *vo.HTTPRequest, history *vo.History) (*vo.HTTPBackendResponse, []error) {
 	if !backend.HasResponse() {
@@ -148,15 +188,6 @@ func (f httpBackendFactory) BuildResponse(backend *vo.Backend, temporaryResponse
 	return vo.NewHTTPBackendResponse(temporaryResponse.StatusCode(), header, body), allErrs
 }
 
-func (f httpBackendFactory) buildRequestBalancedHost(backend *vo.Backend) string {
-	// todo: aqui obtemos o host (correto é criar um domínio chamado balancer aonde ele vai retornar o host
-	//  disponível pegando como base, se ele esta de pé ou não, e sua config de porcentagem)
-	if checker.IsLengthEquals(backend.Hosts(), 1) {
-		return backend.Hosts()[0]
-	}
-	return backend.Hosts()[rand.Intn(len(backend.Hosts())-1)]
-}
-
 func (f httpBackendFactory) buildRequestBody(backend *vo.Backend, request *vo.HTTPRequest, history *vo.History) (
 	*vo.Body, []error) {
 	if !backend.HasRequest() {
@@ -198,7 +229,11 @@ func (f httpBackendFactory) buildRequestURLPath(backend *vo.Backend, request *vo
 
 func (f httpBackendFactory) buildRequestHeader(backend *vo.Backend, body *vo.Body, request *vo.HTTPRequest,
 	history *vo.History) (vo.Header, []error) {
-	header := vo.NewHeaderByBody(body)
+	// the request id always reaches the backend, even when the header is omitted, so the calls can be correlated
+	values := vo.NewHeaderByBody(body).Copy()
+	values[mapper.XRequestId] = []string{request.RequestID()}
+
+	header := vo.NewHeader(values)
 	if backend.HasRequest() && backend.Request().OmitHeader() {
 		return header, nil
 	}
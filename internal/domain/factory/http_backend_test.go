@@ -0,0 +1,192 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package factory
+
+import (
+	"bytes"
+	"github.com/tech4works/gopen-gateway/internal/domain/mapper"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/vo"
+	"github.com/tech4works/gopen-gateway/internal/domain/service"
+	"github.com/tech4works/gopen-gateway/internal/infra/jsonpath"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestHTTPBackendBuildFallbackResponse(t *testing.T) {
+	tests := []struct {
+		name            string
+		fallback        *vo.FallbackResponse
+		wantStatus      int
+		wantContentType string
+		wantBody        string
+	}{
+		{
+			name:       "without body",
+			fallback:   vo.NewFallbackResponse(0, nil, nil),
+			wantStatus: 503,
+		},
+		{
+			name:            "text body",
+			fallback:        vo.NewFallbackResponse(503, nil, "unavailable"),
+			wantStatus:      503,
+			wantContentType: "text/plain",
+			wantBody:        "unavailable",
+		},
+		{
+			name:            "json body",
+			fallback:        vo.NewFallbackResponse(200, nil, map[string]any{"items": []any{}}),
+			wantStatus:      200,
+			wantContentType: "application/json",
+			wantBody:        `{"items":[]}`,
+		},
+		{
+			name:            "custom header",
+			fallback:        vo.NewFallbackResponse(200, map[string]string{"x-fallback": "true"}, "ok"),
+			wantStatus:      200,
+			wantContentType: "text/plain",
+			wantBody:        "ok",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			factory := httpBackendFactory{}
+
+			got := factory.BuildFallbackResponse(tt.fallback)
+			if got.StatusCode().Code() != tt.wantStatus {
+				t.Errorf("BuildFallbackResponse() status code = %d, want %d", got.StatusCode().Code(), tt.wantStatus)
+			}
+			if contentType := got.Header().Get("Content-Type"); !strings.HasPrefix(contentType, tt.wantContentType) {
+				t.Errorf("BuildFallbackResponse() content type = %s, want %s", contentType, tt.wantContentType)
+			}
+			for key, value := range tt.fallback.Header() {
+				if got.Header().Get(http.CanonicalHeaderKey(key)) != value {
+					t.Errorf("BuildFallbackResponse() header %s = %s, want %s", key,
+						got.Header().Get(http.CanonicalHeaderKey(key)), value)
+				}
+			}
+			if !got.HasBody() {
+				if tt.wantBody != "" {
+					t.Errorf("BuildFallbackResponse() body = nil, want %s", tt.wantBody)
+				}
+			} else if body, _ := got.Body().String(); body != tt.wantBody {
+				t.Errorf("BuildFallbackResponse() body = %s, want %s", body, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestHTTPBackendBuildForeachResponse(t *testing.T) {
+	tests := []struct {
+		name       string
+		responses  []*vo.HTTPBackendResponse
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "without items",
+			wantStatus: 200,
+			wantBody:   `[]`,
+		},
+		{
+			name: "json items",
+			responses: []*vo.HTTPBackendResponse{
+				newTestBackendResponse(200, `{"id":1}`),
+				newTestBackendResponse(201, `{"id":2}`),
+			},
+			wantStatus: 200,
+			wantBody:   `[{"id":1},{"id":2}]`,
+		},
+		{
+			name: "items without body",
+			responses: []*vo.HTTPBackendResponse{
+				newTestBackendResponse(204, ""),
+				newTestBackendResponse(200, `{"id":2}`),
+			},
+			wantStatus: 200,
+			wantBody:   `[null,{"id":2}]`,
+		},
+		{
+			name: "first failed item",
+			responses: []*vo.HTTPBackendResponse{
+				newTestBackendResponse(200, `{"id":1}`),
+				newTestBackendResponse(404, `{"error":"not found"}`),
+				newTestBackendResponse(500, `{"error":"internal"}`),
+			},
+			wantStatus: 404,
+			wantBody:   `[{"id":1},{"error":"not found"},{"error":"internal"}]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			factory := httpBackendFactory{aggregatorService: service.NewAggregator(jsonpath.New())}
+
+			got, errs := factory.BuildForeachResponse(tt.responses)
+			if len(errs) > 0 {
+				t.Fatalf("BuildForeachResponse() errs = %v", errs)
+			} else if got.StatusCode().Code() != tt.wantStatus {
+				t.Errorf("BuildForeachResponse() status code = %d, want %d", got.StatusCode().Code(), tt.wantStatus)
+			}
+			if body, _ := got.Body().Raw(); body != tt.wantBody {
+				t.Errorf("BuildForeachResponse() body = %s, want %s", body, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestHTTPBackendBuildTemporaryResponseByErr(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantNil    bool
+	}{
+		{"concurrent canceled", mapper.NewErrConcurrentCanceled(), 0, true},
+		{"circuit open", mapper.NewErrCircuitOpen("http://localhost:8080"), 503, false},
+		{"foreach value not found", mapper.NewErrValueNotFound("#request.body.ids"), 422, false},
+		{"incompatible foreach value", mapper.NewErrIncompatibleForeachValue("#request.body.user"), 422, false},
+		{"foreach max items", mapper.NewErrForeachMaxItems("#request.body.ids", 7, 5), 422, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			factory := httpBackendFactory{}
+			endpoint := vo.NewEndpoint("/users", "POST", 0, vo.Limiter{}, nil, nil, nil, nil, nil, nil, nil)
+
+			got := factory.BuildTemporaryResponseByErr(&endpoint, tt.err)
+			if tt.wantNil {
+				if got != nil {
+					t.Errorf("BuildTemporaryResponseByErr() = %v, want nil", got)
+				}
+				return
+			} else if got == nil {
+				t.Fatalf("BuildTemporaryResponseByErr() = nil, want status %d", tt.wantStatus)
+			}
+			if got.StatusCode().Code() != tt.wantStatus {
+				t.Errorf("BuildTemporaryResponseByErr() status code = %d, want %d", got.StatusCode().Code(),
+					tt.wantStatus)
+			}
+		})
+	}
+}
+
+func newTestBackendResponse(statusCode int, body string) *vo.HTTPBackendResponse {
+	var httpBody *vo.Body
+	if body != "" {
+		httpBody = vo.NewBodyJson(bytes.NewBufferString(body))
+	}
+	return vo.NewHTTPBackendResponse(vo.NewStatusCode(statusCode), vo.NewHeader(nil), httpBody)
+}
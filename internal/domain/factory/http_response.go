@@ -59,7 +59,7 @@ func (h httpResponseFactory) BuildAbortedResponse(endpoint *vo.Endpoint, history
 	header := vo.NewHeader(map[string][]string{
 		mapper.XGopenCache:    {"false"},
 		mapper.XGopenSuccess:  {converter.ToString(lastStatusCode.OK())},
-		mapper.XGopenComplete: {converter.ToString(checker.Equals(history.Size(), endpoint.CountBackendsNonOmit()))},
+		mapper.XGopenComplete: {converter.ToString(h.isComplete(endpoint, history))},
 	})
 	header = h.aggregatorService.AggregateHeaders(header, lastHeader)
 
@@ -114,11 +114,16 @@ func (h httpResponseFactory) buildBodyByHistory(endpoint *vo.Endpoint, history *
 	return body, errs
 }
 
+func (h httpResponseFactory) isComplete(endpoint *vo.Endpoint, history *vo.History) bool {
+	// skipped backends were never expected to answer
+	return checker.Equals(history.Size(), endpoint.CountBackendsNonOmit()-history.CountSkippedNonOmit())
+}
+
 func (h httpResponseFactory) buildHeaderByHistory(endpoint *vo.Endpoint, body *vo.Body, history *vo.History) vo.Header {
 	mapHeader := map[string][]string{
 		mapper.XGopenCache:    {"false"},
 		mapper.XGopenSuccess:  {converter.ToString(history.AllOK())},
-		mapper.XGopenComplete: {converter.ToString(checker.Equals(history.Size(), endpoint.CountBackendsNonOmit()))},
+		mapper.XGopenComplete: {converter.ToString(h.isComplete(endpoint, history))},
 	}
 	if checker.NonNil(body) {
 		mapHeader[mapper.ContentType] = []string{body.ContentType().String()}
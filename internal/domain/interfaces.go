@@ -52,6 +52,22 @@ type JSONValue interface {
 	Interface() any
 }
 
+type JWT interface {
+	Verify(token string, jwt *vo.JWT) (string, error)
+}
+
+type LimiterStore interface {
+	Allow(ctx context.Context, key string, rate vo.Rate) (*vo.RateState, error)
+	Reset(ctx context.Context) (int, error)
+	Ping(ctx context.Context) error
+	Close() error
+}
+
+type LocalLimiterStore interface {
+	LimiterStore
+	Stats() vo.LimiterStats
+}
+
 type Nomenclature interface {
 	Parse(nomenclature enum.Nomenclature, key string) string
 }
@@ -59,6 +75,8 @@ type Nomenclature interface {
 type Store interface {
 	Set(ctx context.Context, key string, value *vo.CacheResponse) error
 	Del(ctx context.Context, key string) error
+	DelByPattern(ctx context.Context, pattern string) (int, error)
 	Get(ctx context.Context, key string) (*vo.CacheResponse, error)
+	Ping(ctx context.Context) error
 	Close() error
 }
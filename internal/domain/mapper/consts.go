@@ -18,22 +18,42 @@ package mapper
 
 import (
 	"github.com/tech4works/checker"
+	"strings"
 )
 
 const (
-	ContentType     = "Content-Type"
-	ContentEncoding = "Content-Encoding"
-	ContentLength   = "Content-Length"
-	XForwardedFor   = "X-Forwarded-For"
-	XGopenCache     = "X-Gopen-Cache"
-	XGopenCacheTTL  = "X-Gopen-Cache-Ttl"
-	XGopenComplete  = "X-Gopen-Complete"
-	XGopenSuccess   = "X-Gopen-Success"
+	ContentType                   = "Content-Type"
+	ContentEncoding               = "Content-Encoding"
+	ContentLength                 = "Content-Length"
+	XForwardedFor                 = "X-Forwarded-For"
+	XRequestId                    = "X-Request-Id"
+	Traceparent                   = "Traceparent"
+	XGopenCache                   = "X-Gopen-Cache"
+	XGopenCacheTTL                = "X-Gopen-Cache-Ttl"
+	XGopenComplete                = "X-Gopen-Complete"
+	XGopenSuccess                 = "X-Gopen-Success"
+	Origin                        = "Origin"
+	Authorization                 = "Authorization"
+	WWWAuthenticate               = "WWW-Authenticate"
+	XApiKey                       = "X-Api-Key"
+	XRateLimitLimit               = "X-RateLimit-Limit"
+	XRateLimitRemaining           = "X-RateLimit-Remaining"
+	XRateLimitReset               = "X-RateLimit-Reset"
+	RetryAfter                    = "Retry-After"
+	Vary                          = "Vary"
+	AccessControlRequestMethod    = "Access-Control-Request-Method"
+	AccessControlRequestHeaders   = "Access-Control-Request-Headers"
+	AccessControlAllowOrigin      = "Access-Control-Allow-Origin"
+	AccessControlAllowMethods     = "Access-Control-Allow-Methods"
+	AccessControlAllowHeaders     = "Access-Control-Allow-Headers"
+	AccessControlAllowCredentials = "Access-Control-Allow-Credentials"
+	AccessControlExposeHeaders    = "Access-Control-Expose-Headers"
+	AccessControlMaxAge           = "Access-Control-Max-Age"
 )
 
 func mandatoryHeaderKeys() []string {
-	return []string{ContentType, ContentEncoding, ContentLength, XForwardedFor, XGopenCache, XGopenCacheTTL,
-		XGopenComplete, XGopenSuccess}
+	return []string{ContentType, ContentEncoding, ContentLength, XForwardedFor, XRequestId, XGopenCache,
+		XGopenCacheTTL, XGopenComplete, XGopenSuccess}
 }
 
 func IsHeaderMandatoryKey(key string) bool {
@@ -43,3 +63,19 @@ func IsHeaderMandatoryKey(key string) bool {
 func IsNotHeaderMandatoryKey(key string) bool {
 	return !IsHeaderMandatoryKey(key)
 }
+
+// browserHeaderKeys are the headers set by the browser itself, they can't be controlled by the caller script.
+func browserHeaderKeys() []string {
+	return []string{"Accept", "Accept-Language", "Content-Language", ContentType, "Accept-Charset", "Accept-Encoding",
+		"Connection", ContentLength, "Cookie", "Date", "Dnt", "Expect", "Host", "Keep-Alive", Origin, "Referer", "Te",
+		"Trailer", "Transfer-Encoding", "Upgrade", "User-Agent", "Via", XForwardedFor}
+}
+
+func IsBrowserHeaderKey(key string) bool {
+	return checker.Contains(browserHeaderKeys(), key) || strings.HasPrefix(key, "Sec-") ||
+		strings.HasPrefix(key, "Proxy-") || strings.HasPrefix(key, "Access-Control-Request-")
+}
+
+func IsNotBrowserHeaderKey(key string) bool {
+	return !IsBrowserHeaderKey(key)
+}
@@ -19,6 +19,7 @@ package mapper
 import (
 	"github.com/tech4works/errors"
 	"github.com/tech4works/gopen-gateway/internal/domain/model/enum"
+	"strings"
 	"time"
 )
 
@@ -27,26 +28,44 @@ const msgErrInvalidAction = "Invalid action modifier %s! action: %s"
 const msgErrEmptyKey = "Modifier empty key!"
 const msgErrEmptyValue = "Modifier empty value!"
 const msgErrIncompatibleBodyType = "Incompatible body type %s to modify!"
+const msgErrIncompatibleForeachValue = "Incompatible foreach value by syntax %s, expected an array!"
+const msgErrForeachMaxItems = "Foreach value by syntax %s has %d items, the limit is %d!"
 const msgErrBadGateway = "bad gateway error:"
 const msgErrGatewayTimeout = "gateway timeout error:"
+const msgErrServiceUnavailable = "service unavailable error:"
+const msgErrCircuitOpen = "circuit breaker open error:"
 const msgErrPayloadTooLarge = "payload too large error:"
 const msgErrHeaderTooLarge = "header too large error:"
 const msgErrTooManyRequests = "too many requests error:"
+const msgErrUnauthorized = "unauthorized error:"
+const msgErrForbidden = "forbidden error:"
 const msgErrCacheNotFound = "cache not found"
+const msgErrLimiterStore = "limiter store error:"
+const msgErrMaxConcurrent = "max concurrent error:"
 const msgErrConcurrentCanceled = "concurrent context canceled"
+const msgErrEndpointDisabled = "endpoint disabled error:"
 
 var ErrBadGateway = errors.New(msgErrBadGateway)
 var ErrGatewayTimeout = errors.New(msgErrGatewayTimeout)
+var ErrServiceUnavailable = errors.New(msgErrServiceUnavailable)
+var ErrCircuitOpen = errors.New(msgErrCircuitOpen)
 var ErrPayloadTooLarge = errors.New(msgErrPayloadTooLarge)
 var ErrHeaderTooLarge = errors.New(msgErrHeaderTooLarge)
 var ErrTooManyRequests = errors.New(msgErrTooManyRequests)
+var ErrUnauthorized = errors.New(msgErrUnauthorized)
+var ErrForbidden = errors.New(msgErrForbidden)
 var ErrCacheNotFound = errors.New(msgErrCacheNotFound)
+var ErrLimiterStore = errors.New(msgErrLimiterStore)
+var ErrMaxConcurrent = errors.New(msgErrMaxConcurrent)
 var ErrValueNotFound = errors.New(msgErrValueNotFound)
 var ErrInvalidAction = errors.New(msgErrInvalidAction)
 var ErrEmptyKey = errors.New(msgErrEmptyKey)
 var ErrEmptyValue = errors.New(msgErrEmptyValue)
 var ErrIncompatibleBodyType = errors.New(msgErrIncompatibleBodyType)
+var ErrIncompatibleForeachValue = errors.New(msgErrIncompatibleForeachValue)
+var ErrForeachMaxItems = errors.New(msgErrForeachMaxItems)
 var ErrConcurrentCanceled = errors.New(msgErrConcurrentCanceled)
+var ErrEndpointDisabled = errors.New(msgErrEndpointDisabled)
 
 func NewErrBadGateway(err error) error {
 	ErrBadGateway = errors.NewSkipCaller(2, msgErrBadGateway, err)
@@ -58,11 +77,28 @@ func NewErrGatewayTimeoutByErr(err error) error {
 	return ErrGatewayTimeout
 }
 
+func NewErrServiceUnavailable(hosts []string) error {
+	ErrServiceUnavailable = errors.NewSkipCaller(2, msgErrServiceUnavailable, "all hosts are down:",
+		strings.Join(hosts, ", "))
+	return ErrServiceUnavailable
+}
+
+func NewErrCircuitOpen(path string) error {
+	ErrCircuitOpen = errors.NewSkipCaller(2, msgErrCircuitOpen, "backend", path, "is temporarily unavailable")
+	return ErrCircuitOpen
+}
+
 func NewErrConcurrentCanceled() error {
 	ErrConcurrentCanceled = errors.NewSkipCaller(2, msgErrConcurrentCanceled)
 	return ErrConcurrentCanceled
 }
 
+func NewErrEndpointDisabled(method, path string) error {
+	ErrEndpointDisabled = errors.NewSkipCaller(2, msgErrEndpointDisabled, "endpoint", method, path,
+		"is temporarily disabled")
+	return ErrEndpointDisabled
+}
+
 func NewErrPayloadTooLarge(limit string) error {
 	ErrPayloadTooLarge = errors.NewSkipCaller(2, msgErrPayloadTooLarge, "permitted limit is", limit)
 	return ErrPayloadTooLarge
@@ -79,6 +115,17 @@ func NewErrTooManyRequests(capacity int, every time.Duration) error {
 	return ErrTooManyRequests
 }
 
+func NewErrLimiterStore(err error) error {
+	ErrLimiterStore = errors.NewSkipCaller(2, msgErrLimiterStore, err)
+	return ErrLimiterStore
+}
+
+func NewErrMaxConcurrent(limit int) error {
+	ErrMaxConcurrent = errors.NewSkipCaller(2, msgErrMaxConcurrent, "permitted limit of", limit,
+		"in-flight requests reached")
+	return ErrMaxConcurrent
+}
+
 func NewErrCacheNotFound() error {
 	ErrCacheNotFound = errors.NewSkipCaller(2, msgErrCacheNotFound)
 	return ErrCacheNotFound
@@ -108,3 +155,23 @@ func NewErrIncompatibleBodyType(contentType string) error {
 	ErrIncompatibleBodyType = errors.NewSkipCallerf(2, msgErrIncompatibleBodyType, contentType)
 	return ErrIncompatibleBodyType
 }
+
+func NewErrIncompatibleForeachValue(syntax string) error {
+	ErrIncompatibleForeachValue = errors.NewSkipCallerf(2, msgErrIncompatibleForeachValue, syntax)
+	return ErrIncompatibleForeachValue
+}
+
+func NewErrForeachMaxItems(syntax string, count, maxItems int) error {
+	ErrForeachMaxItems = errors.NewSkipCallerf(2, msgErrForeachMaxItems, syntax, count, maxItems)
+	return ErrForeachMaxItems
+}
+
+func NewErrUnauthorized(cause string) error {
+	ErrUnauthorized = errors.NewSkipCaller(2, msgErrUnauthorized, cause)
+	return ErrUnauthorized
+}
+
+func NewErrForbidden(cause string) error {
+	ErrForbidden = errors.NewSkipCaller(2, msgErrForbidden, cause)
+	return ErrForbidden
+}
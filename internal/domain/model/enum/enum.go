@@ -36,6 +36,26 @@ type ContentEncoding string
 
 type CacheControl string
 
+type BalancerAlgorithm string
+
+type BalancerHashKey string
+
+type CircuitBreakerState string
+
+type ConditionOperator string
+
+type RateScope string
+
+type RateKeyStrategy string
+
+type TracerProvider string
+
+type LogLevel string
+
+type LogFormat string
+
+type DependencyKind string
+
 const (
 	ModifierScopeRequest  ModifierScope = "REQUEST"
 	ModifierScopeResponse ModifierScope = "RESPONSE"
@@ -87,6 +107,61 @@ const (
 	CacheControlNoCache CacheControl = "no-cache"
 	CacheControlNoStore CacheControl = "no-store"
 )
+const (
+	BalancerAlgorithmRoundRobin     BalancerAlgorithm = "ROUND_ROBIN"
+	BalancerAlgorithmWeighted       BalancerAlgorithm = "WEIGHTED"
+	BalancerAlgorithmLeastInFlight  BalancerAlgorithm = "LEAST_IN_FLIGHT"
+	BalancerAlgorithmConsistentHash BalancerAlgorithm = "CONSISTENT_HASH"
+)
+const (
+	BalancerHashKeyClientIP BalancerHashKey = "CLIENT_IP"
+	BalancerHashKeyHeader   BalancerHashKey = "HEADER"
+)
+const (
+	CircuitBreakerStateClosed   CircuitBreakerState = "CLOSED"
+	CircuitBreakerStateOpen     CircuitBreakerState = "OPEN"
+	CircuitBreakerStateHalfOpen CircuitBreakerState = "HALF_OPEN"
+)
+const (
+	ConditionOperatorExists    ConditionOperator = "EXISTS"
+	ConditionOperatorNotExists ConditionOperator = "NOT_EXISTS"
+	ConditionOperatorEquals    ConditionOperator = "EQUALS"
+	ConditionOperatorNotEquals ConditionOperator = "NOT_EQUALS"
+	ConditionOperatorIn        ConditionOperator = "IN"
+	ConditionOperatorNotIn     ConditionOperator = "NOT_IN"
+	ConditionOperatorTruthy    ConditionOperator = "TRUTHY"
+	ConditionOperatorFalsy     ConditionOperator = "FALSY"
+)
+const (
+	RateScopeGlobal   RateScope = "GLOBAL"
+	RateScopeEndpoint RateScope = "ENDPOINT"
+)
+const (
+	RateKeyStrategyClientIP RateKeyStrategy = "CLIENT_IP"
+	RateKeyStrategyHeader   RateKeyStrategy = "HEADER"
+	RateKeyStrategyConsumer RateKeyStrategy = "CONSUMER"
+	RateKeyStrategyJWTClaim RateKeyStrategy = "JWT_CLAIM"
+)
+const (
+	TracerProviderAPM  TracerProvider = "APM"
+	TracerProviderOTel TracerProvider = "OTEL"
+	TracerProviderNone TracerProvider = "NONE"
+)
+const (
+	LogLevelDebug LogLevel = "DEBUG"
+	LogLevelInfo  LogLevel = "INFO"
+	LogLevelWarn  LogLevel = "WARN"
+	LogLevelError LogLevel = "ERROR"
+)
+const (
+	LogFormatText LogFormat = "TEXT"
+	LogFormatJSON LogFormat = "JSON"
+)
+const (
+	DependencyKindStore        DependencyKind = "STORE"
+	DependencyKindLimiterStore DependencyKind = "LIMITER_STORE"
+	DependencyKindHost         DependencyKind = "HOST"
+)
 
 func (c ContentType) IsEnumValid() bool {
 	switch c {
@@ -112,6 +187,23 @@ func (c CacheControl) IsEnumValid() bool {
 	return false
 }
 
+func (b BalancerAlgorithm) IsEnumValid() bool {
+	switch b {
+	case BalancerAlgorithmRoundRobin, BalancerAlgorithmWeighted, BalancerAlgorithmLeastInFlight,
+		BalancerAlgorithmConsistentHash:
+		return true
+	}
+	return false
+}
+
+func (b BalancerHashKey) IsEnumValid() bool {
+	switch b {
+	case BalancerHashKeyClientIP, BalancerHashKeyHeader:
+		return true
+	}
+	return false
+}
+
 func (n Nomenclature) IsEnumValid() bool {
 	switch n {
 	case NomenclatureCamel, NomenclatureLowerCamel, NomenclatureSnake, NomenclatureKebab, NomenclatureScreamingSnake,
@@ -160,3 +252,68 @@ func (b BackendType) Abbreviation() string {
 	}
 	return ""
 }
+
+func (c CircuitBreakerState) IsEnumValid() bool {
+	switch c {
+	case CircuitBreakerStateClosed, CircuitBreakerStateOpen, CircuitBreakerStateHalfOpen:
+		return true
+	}
+	return false
+}
+
+func (c ConditionOperator) IsEnumValid() bool {
+	switch c {
+	case ConditionOperatorExists, ConditionOperatorNotExists, ConditionOperatorEquals, ConditionOperatorNotEquals,
+		ConditionOperatorIn, ConditionOperatorNotIn, ConditionOperatorTruthy, ConditionOperatorFalsy:
+		return true
+	}
+	return false
+}
+
+func (r RateScope) IsEnumValid() bool {
+	switch r {
+	case RateScopeGlobal, RateScopeEndpoint:
+		return true
+	}
+	return false
+}
+
+func (r RateKeyStrategy) IsEnumValid() bool {
+	switch r {
+	case RateKeyStrategyClientIP, RateKeyStrategyHeader, RateKeyStrategyConsumer, RateKeyStrategyJWTClaim:
+		return true
+	}
+	return false
+}
+
+func (t TracerProvider) IsEnumValid() bool {
+	switch t {
+	case TracerProviderAPM, TracerProviderOTel, TracerProviderNone:
+		return true
+	}
+	return false
+}
+
+func (l LogLevel) IsEnumValid() bool {
+	switch l {
+	case LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelError:
+		return true
+	}
+	return false
+}
+
+func (l LogFormat) IsEnumValid() bool {
+	switch l {
+	case LogFormatText, LogFormatJSON:
+		return true
+	}
+	return false
+}
+
+func (d DependencyKind) IsEnumValid() bool {
+	switch d {
+	case DependencyKindStore, DependencyKindLimiterStore, DependencyKindHost:
+		return true
+	}
+	return false
+}
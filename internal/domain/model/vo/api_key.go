@@ -0,0 +1,49 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vo
+
+import (
+	"github.com/tech4works/checker"
+	"github.com/tech4works/gopen-gateway/internal/domain/mapper"
+)
+
+type APIKey struct {
+	header string
+	query  string
+}
+
+func NewAPIKey(header, query string) *APIKey {
+	return &APIKey{
+		header: header,
+		query:  query,
+	}
+}
+
+func (a APIKey) Header() string {
+	if checker.IsNotEmpty(a.header) {
+		return a.header
+	}
+	return mapper.XApiKey
+}
+
+func (a APIKey) HasQuery() bool {
+	return checker.IsNotEmpty(a.query)
+}
+
+func (a APIKey) Query() string {
+	return a.query
+}
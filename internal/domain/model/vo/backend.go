@@ -22,12 +22,21 @@ import (
 )
 
 type Backend struct {
-	kind     enum.BackendType
-	hosts    []string
-	path     string
-	method   string
-	request  *BackendRequest
-	response *BackendResponse
+	kind           enum.BackendType
+	hosts          []string
+	path           string
+	method         string
+	balancer       *Balancer
+	healthCheck    *HealthCheck
+	circuitBreaker *CircuitBreaker
+	retry          *Retry
+	onlyIf         []Condition
+	ignoreIf       []Condition
+	foreach        *Foreach
+	fallback       *Fallback
+	transport      Transport
+	request        *BackendRequest
+	response       *BackendResponse
 }
 
 type BackendRequest struct {
@@ -69,16 +78,34 @@ func NewBackend(
 	hosts []string,
 	path,
 	method string,
+	balancer *Balancer,
+	healthCheck *HealthCheck,
+	circuitBreaker *CircuitBreaker,
+	retry *Retry,
+	onlyIf,
+	ignoreIf []Condition,
+	foreach *Foreach,
+	fallback *Fallback,
+	transport Transport,
 	request *BackendRequest,
 	response *BackendResponse,
 ) Backend {
 	return Backend{
-		kind:     kind,
-		hosts:    hosts,
-		path:     path,
-		method:   method,
-		request:  request,
-		response: response,
+		kind:           kind,
+		hosts:          hosts,
+		path:           path,
+		method:         method,
+		balancer:       balancer,
+		healthCheck:    healthCheck,
+		circuitBreaker: circuitBreaker,
+		retry:          retry,
+		onlyIf:         onlyIf,
+		ignoreIf:       ignoreIf,
+		foreach:        foreach,
+		fallback:       fallback,
+		transport:      transport,
+		request:        request,
+		response:       response,
 	}
 }
 
@@ -182,6 +209,73 @@ func (b *Backend) Method() string {
 	return b.method
 }
 
+func (b *Backend) Balancer() *Balancer {
+	if checker.NonNil(b.balancer) {
+		return b.balancer
+	}
+	return NewBalancerDefault()
+}
+
+func (b *Backend) HasHealthCheck() bool {
+	return checker.NonNil(b.healthCheck)
+}
+
+func (b *Backend) HealthCheck() *HealthCheck {
+	return b.healthCheck
+}
+
+func (b *Backend) HasCircuitBreaker() bool {
+	return checker.NonNil(b.circuitBreaker)
+}
+
+func (b *Backend) CircuitBreaker() *CircuitBreaker {
+	return b.circuitBreaker
+}
+
+func (b *Backend) HasRetry() bool {
+	return checker.NonNil(b.retry)
+}
+
+func (b *Backend) Retry() *Retry {
+	return b.retry
+}
+
+func (b *Backend) HasOnlyIf() bool {
+	return checker.IsNotEmpty(b.onlyIf)
+}
+
+func (b *Backend) OnlyIf() []Condition {
+	return b.onlyIf
+}
+
+func (b *Backend) HasIgnoreIf() bool {
+	return checker.IsNotEmpty(b.ignoreIf)
+}
+
+func (b *Backend) IgnoreIf() []Condition {
+	return b.ignoreIf
+}
+
+func (b *Backend) HasForeach() bool {
+	return checker.NonNil(b.foreach)
+}
+
+func (b *Backend) Foreach() *Foreach {
+	return b.foreach
+}
+
+func (b *Backend) HasFallback() bool {
+	return checker.NonNil(b.fallback)
+}
+
+func (b *Backend) Fallback() *Fallback {
+	return b.fallback
+}
+
+func (b *Backend) Transport() Transport {
+	return b.transport
+}
+
 func (b *Backend) HasRequest() bool {
 	return checker.NonNil(b.request)
 }
@@ -198,6 +292,23 @@ func (b *Backend) Response() *BackendResponse {
 	return b.response
 }
 
+func (b *Backend) DynamicValues() []string {
+	var values []string
+	for _, condition := range append(b.onlyIf, b.ignoreIf...) {
+		values = append(values, condition.Value())
+	}
+	if b.HasForeach() {
+		values = append(values, b.Foreach().Path())
+	}
+	if b.HasFallback() && b.Fallback().HasBackend() {
+		values = append(values, b.Fallback().Backend().DynamicValues()...)
+	}
+	if checker.NonNil(b.Request()) {
+		values = append(values, b.Request().DynamicValues()...)
+	}
+	return values
+}
+
 func (b *Backend) CountAllDataTransforms() (count int) {
 	if checker.NonNil(b.Request()) {
 		count += b.Request().CountAllDataTransforms()
@@ -327,6 +438,16 @@ func (b BackendRequest) BodyModifiers() []Modifier {
 	return b.bodyModifiers
 }
 
+func (b BackendRequest) DynamicValues() []string {
+	var values []string
+	for _, modifiers := range [][]Modifier{b.headerModifiers, b.paramModifiers, b.queryModifiers, b.bodyModifiers} {
+		for _, modifier := range modifiers {
+			values = append(values, modifier.Value())
+		}
+	}
+	return values
+}
+
 func (b BackendRequest) CountAllDataTransforms() (count int) {
 	count += b.CountParamDataTransforms()
 	count += b.CountHeaderDataTransforms()
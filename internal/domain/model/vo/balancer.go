@@ -0,0 +1,82 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vo
+
+import (
+	"github.com/tech4works/checker"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/enum"
+)
+
+type Balancer struct {
+	algorithm  enum.BalancerAlgorithm
+	weights    map[string]int
+	hashKey    enum.BalancerHashKey
+	hashHeader string
+}
+
+func NewBalancer(
+	algorithm enum.BalancerAlgorithm,
+	hosts []string,
+	weights []int,
+	hashKey enum.BalancerHashKey,
+	hashHeader string,
+) *Balancer {
+	weightsByHost := map[string]int{}
+	for i, weight := range weights {
+		if checker.IsGreaterThan(len(hosts), i) {
+			weightsByHost[hosts[i]] = weight
+		}
+	}
+	return &Balancer{
+		algorithm:  algorithm,
+		weights:    weightsByHost,
+		hashKey:    hashKey,
+		hashHeader: hashHeader,
+	}
+}
+
+func NewBalancerDefault() *Balancer {
+	return &Balancer{
+		algorithm: enum.BalancerAlgorithmRoundRobin,
+	}
+}
+
+func (b Balancer) Algorithm() enum.BalancerAlgorithm {
+	if b.algorithm.IsEnumValid() {
+		return b.algorithm
+	}
+	return enum.BalancerAlgorithmRoundRobin
+}
+
+func (b Balancer) Weight(host string) int {
+	weight, ok := b.weights[host]
+	if ok && checker.IsGreaterThan(weight, 0) {
+		return weight
+	}
+	return 1
+}
+
+func (b Balancer) HashKey() enum.BalancerHashKey {
+	if b.hashKey.IsEnumValid() {
+		return b.hashKey
+	}
+	return enum.BalancerHashKeyClientIP
+}
+
+func (b Balancer) HashHeader() string {
+	return b.hashHeader
+}
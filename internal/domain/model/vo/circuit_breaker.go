@@ -0,0 +1,104 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vo
+
+import (
+	"github.com/tech4works/checker"
+	"time"
+)
+
+type CircuitBreaker struct {
+	window              Duration
+	minRequests         int
+	errorRatio          float64
+	consecutiveFailures int
+	cooldown            Duration
+	halfOpenRequests    int
+	fallback            *FallbackResponse
+}
+
+func NewCircuitBreaker(
+	window Duration,
+	minRequests int,
+	errorRatio float64,
+	consecutiveFailures int,
+	cooldown Duration,
+	halfOpenRequests int,
+	fallback *FallbackResponse,
+) *CircuitBreaker {
+	return &CircuitBreaker{
+		window:              window,
+		minRequests:         minRequests,
+		errorRatio:          errorRatio,
+		consecutiveFailures: consecutiveFailures,
+		cooldown:            cooldown,
+		halfOpenRequests:    halfOpenRequests,
+		fallback:            fallback,
+	}
+}
+
+func (c CircuitBreaker) Window() time.Duration {
+	if checker.IsGreaterThan(c.window, 0) {
+		return c.window.Time()
+	}
+	return 10 * time.Second
+}
+
+func (c CircuitBreaker) MinRequests() int {
+	if checker.IsGreaterThan(c.minRequests, 0) {
+		return c.minRequests
+	}
+	return 10
+}
+
+func (c CircuitBreaker) HasErrorRatio() bool {
+	return checker.IsGreaterThan(c.errorRatio, 0)
+}
+
+func (c CircuitBreaker) ErrorRatio() float64 {
+	return c.errorRatio
+}
+
+func (c CircuitBreaker) HasConsecutiveFailures() bool {
+	return checker.IsGreaterThan(c.consecutiveFailures, 0)
+}
+
+func (c CircuitBreaker) ConsecutiveFailures() int {
+	return c.consecutiveFailures
+}
+
+func (c CircuitBreaker) Cooldown() time.Duration {
+	if checker.IsGreaterThan(c.cooldown, 0) {
+		return c.cooldown.Time()
+	}
+	return 30 * time.Second
+}
+
+func (c CircuitBreaker) HalfOpenRequests() int {
+	if checker.IsGreaterThan(c.halfOpenRequests, 0) {
+		return c.halfOpenRequests
+	}
+	return 1
+}
+
+func (c CircuitBreaker) HasFallback() bool {
+	return checker.NonNil(c.fallback)
+}
+
+func (c CircuitBreaker) Fallback() *FallbackResponse {
+	return c.fallback
+}
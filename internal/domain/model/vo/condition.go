@@ -0,0 +1,50 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vo
+
+import (
+	"github.com/tech4works/gopen-gateway/internal/domain/model/enum"
+)
+
+type Condition struct {
+	value    string
+	operator enum.ConditionOperator
+	expected []string
+}
+
+func NewCondition(value string, operator enum.ConditionOperator, expected []string) Condition {
+	return Condition{
+		value:    value,
+		operator: operator,
+		expected: expected,
+	}
+}
+
+func (c Condition) Value() string {
+	return c.value
+}
+
+func (c Condition) Operator() enum.ConditionOperator {
+	if c.operator.IsEnumValid() {
+		return c.operator
+	}
+	return enum.ConditionOperatorTruthy
+}
+
+func (c Condition) Expected() []string {
+	return c.expected
+}
@@ -0,0 +1,75 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vo
+
+import (
+	"github.com/tech4works/checker"
+	"github.com/tech4works/converter"
+	"strings"
+)
+
+type Consumer struct {
+	name             string
+	apiKeys          []string
+	allowedEndpoints []string
+	metadata         map[string]string
+}
+
+func NewConsumer(name string, apiKeys, allowedEndpoints []string, metadata map[string]string) Consumer {
+	return Consumer{
+		name:             name,
+		apiKeys:          apiKeys,
+		allowedEndpoints: allowedEndpoints,
+		metadata:         metadata,
+	}
+}
+
+func (c Consumer) Name() string {
+	return c.name
+}
+
+func (c Consumer) APIKeys() []string {
+	return c.apiKeys
+}
+
+func (c Consumer) Metadata() map[string]string {
+	return c.metadata
+}
+
+func (c Consumer) Allows(endpoint *Endpoint) bool {
+	if checker.IsEmpty(c.allowedEndpoints) {
+		return true
+	}
+
+	// an allowed endpoint can be only the path, or the method followed by the path, like "GET /users/:id"
+	for _, allowedEndpoint := range c.allowedEndpoints {
+		method, path, found := strings.Cut(allowedEndpoint, " ")
+		if !found && checker.Equals(allowedEndpoint, endpoint.Path()) {
+			return true
+		} else if found && strings.EqualFold(method, endpoint.Method()) && checker.Equals(path, endpoint.Path()) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c Consumer) Map() string {
+	return converter.ToString(map[string]any{
+		"name":     c.name,
+		"metadata": c.metadata,
+	})
+}
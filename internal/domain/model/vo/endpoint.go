@@ -29,6 +29,9 @@ type Endpoint struct {
 	timeout            Duration
 	limiter            Limiter
 	cache              *Cache
+	securityCors       *SecurityCors
+	jwt                *JWT
+	apiKey             *APIKey
 	abortIfStatusCodes *[]int
 	response           *EndpointResponse
 	backends           []Backend
@@ -48,6 +51,9 @@ func NewEndpoint(
 	timeout Duration,
 	limiter Limiter,
 	cache *Cache,
+	securityCors *SecurityCors,
+	jwt *JWT,
+	apiKey *APIKey,
 	abortIfStatusCodes *[]int,
 	response *EndpointResponse,
 	backends []Backend,
@@ -58,6 +64,9 @@ func NewEndpoint(
 		timeout:            timeout,
 		limiter:            limiter,
 		cache:              cache,
+		securityCors:       securityCors,
+		jwt:                jwt,
+		apiKey:             apiKey,
 		abortIfStatusCodes: abortIfStatusCodes,
 		response:           response,
 		backends:           backends,
@@ -112,6 +121,30 @@ func (e *Endpoint) Cache() *Cache {
 	return e.cache
 }
 
+func (e *Endpoint) HasSecurityCors() bool {
+	return checker.NonNil(e.securityCors)
+}
+
+func (e *Endpoint) SecurityCors() *SecurityCors {
+	return e.securityCors
+}
+
+func (e *Endpoint) HasJWT() bool {
+	return checker.NonNil(e.jwt)
+}
+
+func (e *Endpoint) JWT() *JWT {
+	return e.jwt
+}
+
+func (e *Endpoint) HasAPIKey() bool {
+	return checker.NonNil(e.apiKey)
+}
+
+func (e *Endpoint) APIKey() *APIKey {
+	return e.apiKey
+}
+
 func (e *Endpoint) Backends() []Backend {
 	return e.backends
 }
@@ -0,0 +1,45 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vo
+
+import (
+	"github.com/tech4works/checker"
+)
+
+type Fallback struct {
+	response *FallbackResponse
+	backend  *Backend
+}
+
+func NewFallback(response *FallbackResponse, backend *Backend) *Fallback {
+	return &Fallback{
+		response: response,
+		backend:  backend,
+	}
+}
+
+func (f Fallback) HasBackend() bool {
+	return checker.NonNil(f.backend)
+}
+
+func (f Fallback) Backend() *Backend {
+	return f.backend
+}
+
+func (f Fallback) Response() *FallbackResponse {
+	return f.response
+}
@@ -0,0 +1,55 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vo
+
+import (
+	"github.com/tech4works/checker"
+	"net/http"
+)
+
+type FallbackResponse struct {
+	statusCode int
+	header     map[string]string
+	body       any
+}
+
+func NewFallbackResponse(statusCode int, header map[string]string, body any) *FallbackResponse {
+	return &FallbackResponse{
+		statusCode: statusCode,
+		header:     header,
+		body:       body,
+	}
+}
+
+func (f FallbackResponse) StatusCode() StatusCode {
+	if checker.IsGreaterThan(f.statusCode, 0) {
+		return NewStatusCode(f.statusCode)
+	}
+	return NewStatusCode(http.StatusServiceUnavailable)
+}
+
+func (f FallbackResponse) Header() map[string]string {
+	return f.header
+}
+
+func (f FallbackResponse) HasBody() bool {
+	return checker.NonNil(f.body)
+}
+
+func (f FallbackResponse) Body() any {
+	return f.body
+}
@@ -0,0 +1,53 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vo
+
+import (
+	"github.com/tech4works/checker"
+)
+
+type Foreach struct {
+	path        string
+	concurrency int
+	maxItems    int
+}
+
+func NewForeach(path string, concurrency, maxItems int) *Foreach {
+	return &Foreach{
+		path:        path,
+		concurrency: concurrency,
+		maxItems:    maxItems,
+	}
+}
+
+func (f Foreach) Path() string {
+	return f.path
+}
+
+func (f Foreach) Concurrency() int {
+	if checker.IsGreaterThan(f.concurrency, 0) {
+		return f.concurrency
+	}
+	return 5
+}
+
+func (f Foreach) MaxItems() int {
+	if checker.IsGreaterThan(f.maxItems, 0) {
+		return f.maxItems
+	}
+	return 100
+}
@@ -21,14 +21,27 @@ import (
 )
 
 type Gopen struct {
-	securityCors *SecurityCors
-	endpoints    []Endpoint
+	securityCors      *SecurityCors
+	maxConcurrent     *MaxConcurrent
+	readiness         *Readiness
+	consumersByAPIKey map[string]*Consumer
+	endpoints         []Endpoint
 }
 
-func NewGopen(securityCors *SecurityCors, endpoints []Endpoint) *Gopen {
+func NewGopen(securityCors *SecurityCors, maxConcurrent *MaxConcurrent, readiness *Readiness, consumers []Consumer,
+	endpoints []Endpoint) *Gopen {
+	consumersByAPIKey := map[string]*Consumer{}
+	for i := range consumers {
+		for _, apiKey := range consumers[i].APIKeys() {
+			consumersByAPIKey[apiKey] = &consumers[i]
+		}
+	}
 	return &Gopen{
-		securityCors: securityCors,
-		endpoints:    endpoints,
+		securityCors:      securityCors,
+		maxConcurrent:     maxConcurrent,
+		readiness:         readiness,
+		consumersByAPIKey: consumersByAPIKey,
+		endpoints:         endpoints,
 	}
 }
 
@@ -40,6 +53,36 @@ func (g Gopen) HasSecurityCors() bool {
 	return checker.NonNil(g.securityCors)
 }
 
+func (g Gopen) HasMaxConcurrent() bool {
+	return checker.NonNil(g.maxConcurrent)
+}
+
+func (g Gopen) MaxConcurrent() *MaxConcurrent {
+	return g.maxConcurrent
+}
+
+func (g Gopen) Readiness() *Readiness {
+	if checker.NonNil(g.readiness) {
+		return g.readiness
+	}
+	return NewReadiness(false, 0)
+}
+
+func (g Gopen) FindConsumer(apiKeyHash string) (*Consumer, bool) {
+	consumer, ok := g.consumersByAPIKey[apiKeyHash]
+	return consumer, ok
+}
+
 func (g Gopen) Endpoints() []Endpoint {
 	return g.endpoints
 }
+
+func (g Gopen) FindEndpoint(path, method string) (*Endpoint, bool) {
+	for i := range g.endpoints {
+		endpoint := &g.endpoints[i]
+		if checker.Equals(endpoint.Path(), path) && checker.Equals(endpoint.Method(), method) {
+			return endpoint, true
+		}
+	}
+	return nil, false
+}
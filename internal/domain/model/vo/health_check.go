@@ -0,0 +1,90 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vo
+
+import (
+	"github.com/tech4works/checker"
+	"time"
+)
+
+type HealthCheck struct {
+	path                string
+	interval            Duration
+	timeout             Duration
+	expectedStatusCodes []int
+	unhealthyThreshold  int
+	healthyThreshold    int
+}
+
+func NewHealthCheck(path string, interval, timeout Duration, expectedStatusCodes []int, unhealthyThreshold,
+	healthyThreshold int) *HealthCheck {
+	return &HealthCheck{
+		path:                path,
+		interval:            interval,
+		timeout:             timeout,
+		expectedStatusCodes: expectedStatusCodes,
+		unhealthyThreshold:  unhealthyThreshold,
+		healthyThreshold:    healthyThreshold,
+	}
+}
+
+func (h HealthCheck) Path() string {
+	return h.path
+}
+
+func (h HealthCheck) Interval() time.Duration {
+	if checker.IsGreaterThan(h.interval, 0) {
+		return h.interval.Time()
+	}
+	return 10 * time.Second
+}
+
+func (h HealthCheck) Timeout() time.Duration {
+	timeout := 5 * time.Second
+	if checker.IsGreaterThan(h.timeout, 0) {
+		timeout = h.timeout.Time()
+	}
+	if checker.IsGreaterThan(timeout, h.Interval()) {
+		return h.Interval()
+	}
+	return timeout
+}
+
+func (h HealthCheck) UnhealthyThreshold() int {
+	if checker.IsGreaterThan(h.unhealthyThreshold, 0) {
+		return h.unhealthyThreshold
+	}
+	return 3
+}
+
+func (h HealthCheck) HealthyThreshold() int {
+	if checker.IsGreaterThan(h.healthyThreshold, 0) {
+		return h.healthyThreshold
+	}
+	return 2
+}
+
+func (h HealthCheck) ExpectedStatusCodes() []int {
+	return h.expectedStatusCodes
+}
+
+func (h HealthCheck) IsExpectedStatusCode(statusCode StatusCode) bool {
+	if checker.IsNotEmpty(h.expectedStatusCodes) {
+		return checker.Contains(h.expectedStatusCodes, statusCode.Code())
+	}
+	return checker.IsGreaterThanOrEqual(statusCode.Code(), 200) && checker.IsLessThanOrEqual(statusCode.Code(), 299)
+}
@@ -25,17 +25,21 @@ type History struct {
 	backends  []*Backend
 	requests  []*HTTPBackendRequest
 	responses []*HTTPBackendResponse
+	skipped   []*Backend
+	item      string
 }
 
 func NewEmptyHistory() *History {
 	return &History{}
 }
 
-func NewHistory(backends []*Backend, requests []*HTTPBackendRequest, responses []*HTTPBackendResponse) *History {
+func NewHistory(backends []*Backend, requests []*HTTPBackendRequest, responses []*HTTPBackendResponse,
+	skipped []*Backend) *History {
 	return &History{
 		backends:  backends,
 		requests:  requests,
 		responses: responses,
+		skipped:   skipped,
 	}
 }
 
@@ -44,9 +48,64 @@ func (h *History) Add(backend *Backend, request *HTTPBackendRequest, response *H
 		backends:  append(h.backends, backend),
 		requests:  append(h.requests, request),
 		responses: append(h.responses, response),
+		skipped:   h.skipped,
 	}
 }
 
+func (h *History) Skip(backend *Backend) *History {
+	return &History{
+		backends:  append(h.backends, backend),
+		requests:  append(h.requests, nil),
+		responses: append(h.responses, nil),
+		skipped:   append(h.skipped, backend),
+	}
+}
+
+func (h *History) WithItem(item string) *History {
+	return &History{
+		backends:  h.backends,
+		requests:  h.requests,
+		responses: h.responses,
+		skipped:   h.skipped,
+		item:      item,
+	}
+}
+
+func (h *History) HasItem() bool {
+	return checker.IsNotEmpty(h.item)
+}
+
+func (h *History) Item() string {
+	return h.item
+}
+
+func (h *History) Skipped() []*Backend {
+	return h.skipped
+}
+
+func (h *History) CountSkippedNonOmit() (count int) {
+	for _, backend := range h.skipped {
+		if checker.IsNil(backend.Response()) || !backend.Response().Omit() {
+			count++
+		}
+	}
+	return count
+}
+
+func (h *History) Compact() *History {
+	var backends []*Backend
+	var requests []*HTTPBackendRequest
+	var responses []*HTTPBackendResponse
+	for i, response := range h.responses {
+		if checker.NonNil(response) {
+			backends = append(backends, h.backends[i])
+			requests = append(requests, h.requests[i])
+			responses = append(responses, response)
+		}
+	}
+	return NewHistory(backends, requests, responses, h.skipped)
+}
+
 func (h *History) Get(i int) (*Backend, *HTTPBackendRequest, *HTTPBackendResponse) {
 	return h.backends[i], h.requests[i], h.responses[i]
 }
@@ -79,6 +138,11 @@ func (h *History) AllOK() bool {
 func (h *History) Map() (string, error) {
 	var sliceOfMap []any
 	for _, response := range h.responses {
+		// backends not executed keep their position so that #responses indexes stay aligned
+		if checker.IsNil(response) {
+			sliceOfMap = append(sliceOfMap, nil)
+			continue
+		}
 		responseMap, err := response.Map()
 		if checker.NonNil(err) {
 			return "", err
@@ -0,0 +1,76 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vo
+
+import (
+	"github.com/tech4works/checker"
+	"time"
+)
+
+type HostHealth struct {
+	host      string
+	up        bool
+	failures  int
+	message   string
+	checkedAt time.Time
+}
+
+func NewHostHealthUp(host string) HostHealth {
+	return HostHealth{
+		host:      host,
+		up:        true,
+		checkedAt: time.Now(),
+	}
+}
+
+func NewHostHealthDown(host string, failures int, message string) HostHealth {
+	return HostHealth{
+		host:      host,
+		up:        false,
+		failures:  failures,
+		message:   message,
+		checkedAt: time.Now(),
+	}
+}
+
+func (h HostHealth) Host() string {
+	return h.host
+}
+
+func (h HostHealth) IsUp() bool {
+	return h.up
+}
+
+func (h HostHealth) IsDown() bool {
+	return !h.up
+}
+
+func (h HostHealth) Failures() int {
+	return h.failures
+}
+
+func (h HostHealth) Message() string {
+	return h.message
+}
+
+func (h HostHealth) HasMessage() bool {
+	return checker.IsNotEmpty(h.message)
+}
+
+func (h HostHealth) CheckedAt() time.Time {
+	return h.checkedAt
+}
@@ -48,6 +48,10 @@ func NewHTTPBackendRequest(
 	}
 }
 
+func (b *HTTPBackendRequest) Host() string {
+	return b.host
+}
+
 func (b *HTTPBackendRequest) Path() URLPath {
 	return b.path
 }
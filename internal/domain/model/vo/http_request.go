@@ -23,12 +23,14 @@ import (
 )
 
 type HTTPRequest struct {
-	url    string
-	path   URLPath
-	method string
-	header Header
-	query  Query
-	body   *Body
+	url       string
+	path      URLPath
+	method    string
+	header    Header
+	query     Query
+	body      *Body
+	jwtClaims string
+	consumer  *Consumer
 }
 
 func NewHTTPRequest(path URLPath, url, method string, header Header, query Query, body *Body) *HTTPRequest {
@@ -70,6 +72,48 @@ func (h *HTTPRequest) Body() *Body {
 	return h.body
 }
 
+func (h *HTTPRequest) WithJWTClaims(claims string) *HTTPRequest {
+	return &HTTPRequest{
+		path:      h.path,
+		url:       h.url,
+		method:    h.method,
+		header:    h.header,
+		query:     h.query,
+		body:      h.body,
+		jwtClaims: claims,
+		consumer:  h.consumer,
+	}
+}
+
+func (h *HTTPRequest) HasJWTClaims() bool {
+	return checker.IsNotEmpty(h.jwtClaims)
+}
+
+func (h *HTTPRequest) JWTClaims() string {
+	return h.jwtClaims
+}
+
+func (h *HTTPRequest) WithConsumer(consumer *Consumer) *HTTPRequest {
+	return &HTTPRequest{
+		path:      h.path,
+		url:       h.url,
+		method:    h.method,
+		header:    h.header,
+		query:     h.query,
+		body:      h.body,
+		jwtClaims: h.jwtClaims,
+		consumer:  consumer,
+	}
+}
+
+func (h *HTTPRequest) HasConsumer() bool {
+	return checker.NonNil(h.consumer)
+}
+
+func (h *HTTPRequest) Consumer() *Consumer {
+	return h.consumer
+}
+
 func (h *HTTPRequest) Map() (string, error) {
 	var body any
 	if checker.NonNil(h.Body()) {
@@ -91,6 +135,10 @@ func (h *HTTPRequest) ClientIP() string {
 	return h.Header().GetFirst(mapper.XForwardedFor)
 }
 
+func (h *HTTPRequest) RequestID() string {
+	return h.Header().Get(mapper.XRequestId)
+}
+
 func (h *HTTPRequest) HasBody() bool {
 	return checker.NonNil(h.body)
 }
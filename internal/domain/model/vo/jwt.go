@@ -0,0 +1,142 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vo
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"github.com/tech4works/checker"
+	"slices"
+	"time"
+)
+
+type JWT struct {
+	algorithms     []string
+	keys           []JWTKey
+	issuer         string
+	audience       []string
+	requiredClaims []string
+	leeway         Duration
+}
+
+type JWTKey struct {
+	id    string
+	value any
+}
+
+func NewJWT(
+	algorithms []string,
+	keys []JWTKey,
+	issuer string,
+	audience,
+	requiredClaims []string,
+	leeway Duration,
+) *JWT {
+	return &JWT{
+		algorithms:     algorithms,
+		keys:           keys,
+		issuer:         issuer,
+		audience:       audience,
+		requiredClaims: requiredClaims,
+		leeway:         leeway,
+	}
+}
+
+func NewJWTKey(id string, value any) JWTKey {
+	return JWTKey{
+		id:    id,
+		value: value,
+	}
+}
+
+func (j JWT) Algorithms() []string {
+	if checker.IsNotEmpty(j.algorithms) {
+		return j.algorithms
+	}
+
+	// without configured algorithms we accept the ones compatible with the configured keys
+	var algorithms []string
+	for _, key := range j.keys {
+		algorithm := key.DefaultAlgorithm()
+		if checker.IsNotEmpty(algorithm) && !slices.Contains(algorithms, algorithm) {
+			algorithms = append(algorithms, algorithm)
+		}
+	}
+	return algorithms
+}
+
+func (j JWT) Keys() []JWTKey {
+	return j.keys
+}
+
+func (j JWT) HasIssuer() bool {
+	return checker.IsNotEmpty(j.issuer)
+}
+
+func (j JWT) Issuer() string {
+	return j.issuer
+}
+
+func (j JWT) HasAudience() bool {
+	return checker.IsNotEmpty(j.audience)
+}
+
+func (j JWT) Audience() []string {
+	return j.audience
+}
+
+func (j JWT) RequiredClaims() []string {
+	return j.requiredClaims
+}
+
+func (j JWT) Leeway() time.Duration {
+	return j.leeway.Time()
+}
+
+func (k JWTKey) ID() string {
+	return k.id
+}
+
+func (k JWTKey) Value() any {
+	return k.value
+}
+
+func (k JWTKey) DefaultAlgorithm() string {
+	switch k.value.(type) {
+	case []byte:
+		return "HS256"
+	case *rsa.PublicKey:
+		return "RS256"
+	case *ecdsa.PublicKey:
+		return "ES256"
+	default:
+		return ""
+	}
+}
+
+func (k JWTKey) IsCompatible(algorithm string) bool {
+	switch k.value.(type) {
+	case []byte:
+		return slices.Contains([]string{"HS256", "HS384", "HS512"}, algorithm)
+	case *rsa.PublicKey:
+		return slices.Contains([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512"}, algorithm)
+	case *ecdsa.PublicKey:
+		return slices.Contains([]string{"ES256", "ES384", "ES512"}, algorithm)
+	default:
+		return false
+	}
+}
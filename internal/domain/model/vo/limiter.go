@@ -18,6 +18,8 @@ package vo
 
 import (
 	"github.com/tech4works/checker"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/enum"
+	"math"
 	"time"
 )
 
@@ -26,19 +28,43 @@ type Limiter struct {
 	maxBodySize            Bytes
 	maxMultipartMemorySize Bytes
 	rate                   Rate
+	maxConcurrent          *MaxConcurrent
 }
 
 type Rate struct {
 	capacity int
 	every    Duration
+	scope    enum.RateScope
+	key      RateKey
+	headers  bool
 }
 
-func NewLimiter(maxHeaderSize, maxBodySize, maxMultipartForm Bytes, rate Rate) Limiter {
+type RateKey struct {
+	strategies []enum.RateKeyStrategy
+	header     string
+	claim      string
+}
+
+type LimiterStats struct {
+	buckets   int
+	evictions uint64
+}
+
+type RateState struct {
+	allowed  bool
+	capacity int
+	every    time.Duration
+	tokens   float64
+}
+
+func NewLimiter(maxHeaderSize, maxBodySize, maxMultipartForm Bytes, rate Rate, maxConcurrent *MaxConcurrent,
+) Limiter {
 	return Limiter{
 		maxHeaderSize:          maxHeaderSize,
 		maxBodySize:            maxBodySize,
 		maxMultipartMemorySize: maxMultipartForm,
 		rate:                   rate,
+		maxConcurrent:          maxConcurrent,
 	}
 }
 
@@ -46,10 +72,37 @@ func NewLimiterDefault() Limiter {
 	return Limiter{}
 }
 
-func NewRate(every Duration, capacity int) Rate {
+func NewRate(every Duration, capacity int, scope enum.RateScope, key RateKey, headers bool) Rate {
 	return Rate{
 		capacity: capacity,
 		every:    every,
+		scope:    scope,
+		key:      key,
+		headers:  headers,
+	}
+}
+
+func NewRateKey(strategies []enum.RateKeyStrategy, header, claim string) RateKey {
+	return RateKey{
+		strategies: strategies,
+		header:     header,
+		claim:      claim,
+	}
+}
+
+func NewRateState(rate Rate, allowed bool, tokens float64) *RateState {
+	return &RateState{
+		allowed:  allowed,
+		capacity: rate.Capacity(),
+		every:    rate.EveryTime(),
+		tokens:   tokens,
+	}
+}
+
+func NewLimiterStats(buckets int, evictions uint64) LimiterStats {
+	return LimiterStats{
+		buckets:   buckets,
+		evictions: evictions,
 	}
 }
 
@@ -74,10 +127,25 @@ func (l Limiter) MaxMultipartMemorySize() Bytes {
 	return NewBytes("5MB")
 }
 
+func (l Limiter) MaxBodySizeBy(contentType string) Bytes {
+	if checker.ContainsIgnoreCase(contentType, "multipart/form-data") {
+		return l.MaxMultipartMemorySize()
+	}
+	return l.MaxBodySize()
+}
+
 func (l Limiter) Rate() Rate {
 	return l.rate
 }
 
+func (l Limiter) HasMaxConcurrent() bool {
+	return checker.NonNil(l.maxConcurrent)
+}
+
+func (l Limiter) MaxConcurrent() *MaxConcurrent {
+	return l.maxConcurrent
+}
+
 func (r Rate) IsEmpty() bool {
 	return checker.IsLessThanOrEqual(r.Capacity(), 0) && checker.IsLessThanOrEqual(r.Every(), 0)
 }
@@ -93,3 +161,64 @@ func (r Rate) Every() Duration {
 func (r Rate) EveryTime() time.Duration {
 	return r.every.Time()
 }
+
+func (r Rate) Scope() enum.RateScope {
+	if r.scope.IsEnumValid() {
+		return r.scope
+	}
+	return enum.RateScopeGlobal
+}
+
+func (r Rate) Key() RateKey {
+	return r.key
+}
+
+func (r Rate) Headers() bool {
+	return r.headers
+}
+
+func (r RateKey) Strategies() []enum.RateKeyStrategy {
+	if checker.IsNotEmpty(r.strategies) {
+		return r.strategies
+	}
+	return []enum.RateKeyStrategy{enum.RateKeyStrategyClientIP}
+}
+
+func (r RateKey) Header() string {
+	return r.header
+}
+
+func (r RateKey) Claim() string {
+	return r.claim
+}
+
+func (r RateState) Allowed() bool {
+	return r.allowed
+}
+
+func (r RateState) Limit() int {
+	return r.capacity
+}
+
+func (r RateState) Remaining() int {
+	return int(math.Max(0, math.Floor(r.tokens)))
+}
+
+func (r RateState) Reset() time.Duration {
+	return time.Duration(math.Max(0, float64(r.capacity)-r.tokens) * float64(r.every))
+}
+
+func (r RateState) RetryAfter() time.Duration {
+	if r.allowed {
+		return 0
+	}
+	return time.Duration(math.Max(0, 1-r.tokens) * float64(r.every))
+}
+
+func (l LimiterStats) Buckets() int {
+	return l.buckets
+}
+
+func (l LimiterStats) Evictions() uint64 {
+	return l.evictions
+}
@@ -0,0 +1,54 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vo
+
+import (
+	"github.com/tech4works/checker"
+	"time"
+)
+
+type MaxConcurrent struct {
+	limit        int
+	queueSize    int
+	queueTimeout Duration
+}
+
+func NewMaxConcurrent(limit, queueSize int, queueTimeout Duration) *MaxConcurrent {
+	return &MaxConcurrent{
+		limit:        limit,
+		queueSize:    queueSize,
+		queueTimeout: queueTimeout,
+	}
+}
+
+func (m MaxConcurrent) Limit() int {
+	return m.limit
+}
+
+func (m MaxConcurrent) QueueSize() int {
+	if checker.IsGreaterThan(m.queueSize, 0) {
+		return m.queueSize
+	}
+	return 0
+}
+
+func (m MaxConcurrent) QueueTimeout() time.Duration {
+	if checker.IsGreaterThan(m.queueTimeout, 0) {
+		return m.queueTimeout.Time()
+	}
+	return time.Second
+}
@@ -37,6 +37,10 @@ func NewEmptyQuery() Query {
 	return Query{}
 }
 
+func (q Query) Get(key string) string {
+	return url.Values(q.values).Get(key)
+}
+
 func (q Query) GetAll(key string) []string {
 	return q.values[key]
 }
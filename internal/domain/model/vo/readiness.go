@@ -0,0 +1,127 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vo
+
+import (
+	"github.com/tech4works/checker"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/enum"
+	"time"
+)
+
+type Readiness struct {
+	probeHosts bool
+	timeout    Duration
+}
+
+type ReadinessReport struct {
+	reloading bool
+	checks    []DependencyCheck
+	ready     bool
+}
+
+type DependencyCheck struct {
+	kind     enum.DependencyKind
+	name     string
+	up       bool
+	message  string
+	duration time.Duration
+}
+
+func NewReadiness(probeHosts bool, timeout Duration) *Readiness {
+	return &Readiness{
+		probeHosts: probeHosts,
+		timeout:    timeout,
+	}
+}
+
+func NewReadinessReport(reloading bool, checks []DependencyCheck, ready bool) ReadinessReport {
+	return ReadinessReport{
+		reloading: reloading,
+		checks:    checks,
+		ready:     ready,
+	}
+}
+
+func NewDependencyCheck(kind enum.DependencyKind, name string, err error, duration time.Duration) DependencyCheck {
+	var message string
+	if checker.NonNil(err) {
+		message = err.Error()
+	}
+	return DependencyCheck{
+		kind:     kind,
+		name:     name,
+		up:       checker.IsNil(err),
+		message:  message,
+		duration: duration,
+	}
+}
+
+func NewDependencyCheckByHostHealth(hostHealth HostHealth) DependencyCheck {
+	return DependencyCheck{
+		kind:    enum.DependencyKindHost,
+		name:    hostHealth.Host(),
+		up:      hostHealth.IsUp(),
+		message: hostHealth.Message(),
+	}
+}
+
+func (r Readiness) ProbeHosts() bool {
+	return r.probeHosts
+}
+
+func (r Readiness) Timeout() time.Duration {
+	if checker.IsGreaterThan(r.timeout, 0) {
+		return r.timeout.Time()
+	}
+	return 2 * time.Second
+}
+
+func (r ReadinessReport) IsReloading() bool {
+	return r.reloading
+}
+
+func (r ReadinessReport) Checks() []DependencyCheck {
+	return r.checks
+}
+
+func (r ReadinessReport) IsReady() bool {
+	return r.ready
+}
+
+func (d DependencyCheck) Kind() enum.DependencyKind {
+	return d.kind
+}
+
+func (d DependencyCheck) Name() string {
+	return d.name
+}
+
+func (d DependencyCheck) IsUp() bool {
+	return d.up
+}
+
+func (d DependencyCheck) IsDown() bool {
+	return !d.up
+}
+
+func (d DependencyCheck) Message() string {
+	return d.message
+}
+
+func (d DependencyCheck) Duration() time.Duration {
+	return d.duration
+}
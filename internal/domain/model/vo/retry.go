@@ -0,0 +1,133 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vo
+
+import (
+	"github.com/tech4works/checker"
+	"math"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+type Retry struct {
+	maxAttempts        int
+	statusCodes        []int
+	onConnectionError  bool
+	onTimeout          bool
+	initialInterval    Duration
+	maxInterval        Duration
+	multiplier         float64
+	budget             Duration
+	allowNonIdempotent bool
+}
+
+func NewRetry(
+	maxAttempts int,
+	statusCodes []int,
+	onConnectionError,
+	onTimeout bool,
+	initialInterval,
+	maxInterval Duration,
+	multiplier float64,
+	budget Duration,
+	allowNonIdempotent bool,
+) *Retry {
+	return &Retry{
+		maxAttempts:        maxAttempts,
+		statusCodes:        statusCodes,
+		onConnectionError:  onConnectionError,
+		onTimeout:          onTimeout,
+		initialInterval:    initialInterval,
+		maxInterval:        maxInterval,
+		multiplier:         multiplier,
+		budget:             budget,
+		allowNonIdempotent: allowNonIdempotent,
+	}
+}
+
+func (r Retry) MaxAttempts() int {
+	if checker.IsGreaterThan(r.maxAttempts, 0) {
+		return r.maxAttempts
+	}
+	return 1
+}
+
+func (r Retry) StatusCodes() []int {
+	if checker.IsNotEmpty(r.statusCodes) {
+		return r.statusCodes
+	}
+	return []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout}
+}
+
+func (r Retry) IsRetryableStatusCode(statusCode StatusCode) bool {
+	return checker.Contains(r.StatusCodes(), statusCode.Code())
+}
+
+func (r Retry) OnConnectionError() bool {
+	return r.onConnectionError
+}
+
+func (r Retry) OnTimeout() bool {
+	return r.onTimeout
+}
+
+func (r Retry) InitialInterval() time.Duration {
+	if checker.IsGreaterThan(r.initialInterval, 0) {
+		return r.initialInterval.Time()
+	}
+	return 100 * time.Millisecond
+}
+
+func (r Retry) MaxInterval() time.Duration {
+	if checker.IsGreaterThan(r.maxInterval, 0) {
+		return r.maxInterval.Time()
+	}
+	return 2 * time.Second
+}
+
+func (r Retry) Multiplier() float64 {
+	if checker.IsGreaterThanOrEqual(r.multiplier, 1.0) {
+		return r.multiplier
+	}
+	return 2
+}
+
+func (r Retry) HasBudget() bool {
+	return checker.IsGreaterThan(r.budget, 0)
+}
+
+func (r Retry) Budget() time.Duration {
+	return r.budget.Time()
+}
+
+func (r Retry) IsRetryableMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return r.allowNonIdempotent
+}
+
+func (r Retry) Backoff(attempt int) time.Duration {
+	interval := float64(r.InitialInterval()) * math.Pow(r.Multiplier(), float64(attempt-1))
+	interval = math.Min(interval, float64(r.MaxInterval()))
+
+	// equal jitter, half of the interval is kept and the other half is random to spread the retries
+	half := interval / 2
+	return time.Duration(half + rand.Float64()*half)
+}
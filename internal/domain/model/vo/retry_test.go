@@ -0,0 +1,54 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetryBackoff(t *testing.T) {
+	tests := []struct {
+		name     string
+		retry    *Retry
+		attempt  int
+		interval time.Duration
+	}{
+		{"default first attempt", NewRetry(3, nil, false, false, 0, 0, 0, 0, false), 1, 100 * time.Millisecond},
+		{"default third attempt", NewRetry(3, nil, false, false, 0, 0, 0, 0, false), 3, 400 * time.Millisecond},
+		{"default capped attempt", NewRetry(3, nil, false, false, 0, 0, 0, 0, false), 10, 2 * time.Second},
+		{"custom second attempt", NewRetry(3, nil, false, false, NewDuration(time.Second), NewDuration(5*time.Second), 3,
+			0, false), 2, 3 * time.Second},
+		{"custom capped attempt", NewRetry(3, nil, false, false, NewDuration(time.Second), NewDuration(5*time.Second), 3,
+			0, false), 3, 5 * time.Second},
+		{"constant multiplier", NewRetry(3, nil, false, false, NewDuration(time.Second), NewDuration(5*time.Second), 1,
+			0, false), 3, time.Second},
+		{"multiplier below one", NewRetry(3, nil, false, false, NewDuration(time.Second), NewDuration(5*time.Second), 0.5,
+			0, false), 2, 2 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// half of the interval is random, so every backoff must stay between the half and the whole interval
+			for i := 0; i < 100; i++ {
+				got := tt.retry.Backoff(tt.attempt)
+				if got < tt.interval/2 || got > tt.interval {
+					t.Fatalf("Backoff(%d) = %s, want between %s and %s", tt.attempt, got, tt.interval/2, tt.interval)
+				}
+			}
+		})
+	}
+}
@@ -18,30 +18,110 @@ package vo
 
 import (
 	"github.com/tech4works/checker"
+	"slices"
+	"strings"
 )
 
 type SecurityCors struct {
-	allowOrigins []string
-	allowMethods []string
-	allowHeaders []string
+	allowOrigins     []string
+	allowMethods     []string
+	allowHeaders     []string
+	exposeHeaders    []string
+	allowCredentials bool
+	maxAge           Duration
 }
 
-func NewSecurityCors(allowsOrigins, allowMethods, allowHeaders []string) *SecurityCors {
+func NewSecurityCors(
+	allowOrigins,
+	allowMethods,
+	allowHeaders,
+	exposeHeaders []string,
+	allowCredentials bool,
+	maxAge Duration,
+) *SecurityCors {
 	return &SecurityCors{
-		allowOrigins: allowsOrigins,
-		allowMethods: allowMethods,
-		allowHeaders: allowHeaders,
+		allowOrigins:     allowOrigins,
+		allowMethods:     allowMethods,
+		allowHeaders:     allowHeaders,
+		exposeHeaders:    exposeHeaders,
+		allowCredentials: allowCredentials,
+		maxAge:           maxAge,
 	}
 }
 
 func (s SecurityCors) DisallowOrigin(origin string) bool {
-	return checker.NonNil(s.allowOrigins) && checker.NotContains(s.allowOrigins, origin)
+	if checker.IsNil(s.allowOrigins) {
+		return false
+	}
+	for _, allowOrigin := range s.allowOrigins {
+		if matchOrigin(strings.ToLower(allowOrigin), strings.ToLower(origin)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s SecurityCors) AllowAnyOrigin() bool {
+	return checker.IsNil(s.allowOrigins) || slices.Contains(s.allowOrigins, "*")
 }
 
 func (s SecurityCors) DisallowMethod(method string) bool {
-	return checker.NonNil(s.allowOrigins) && checker.NotContains(s.allowMethods, method)
+	return checker.NonNil(s.allowMethods) && !containsFold(s.allowMethods, method)
 }
 
 func (s SecurityCors) DisallowHeader(headerKey string) bool {
-	return checker.NonNil(s.allowOrigins) && checker.NotContains(s.allowHeaders, headerKey)
+	return checker.NonNil(s.allowHeaders) && !containsFold(s.allowHeaders, headerKey)
+}
+
+func (s SecurityCors) AllowMethods() []string {
+	return s.allowMethods
+}
+
+func (s SecurityCors) AllowHeaders() []string {
+	return s.allowHeaders
+}
+
+func (s SecurityCors) ExposeHeaders() []string {
+	return s.exposeHeaders
+}
+
+func (s SecurityCors) AllowCredentials() bool {
+	return s.allowCredentials
+}
+
+func (s SecurityCors) MaxAge() Duration {
+	return s.maxAge
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if checker.Equals(v, "*") || strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchOrigin(pattern, origin string) bool {
+	if checker.Equals(pattern, "*") {
+		return true
+	}
+
+	// every * matches any sequence, like https://*.example.com
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(origin, parts[0]) {
+		return false
+	}
+	origin = origin[len(parts[0]):]
+	for i, part := range parts[1:] {
+		if checker.Equals(i, len(parts)-2) {
+			return strings.HasSuffix(origin, part)
+		}
+		index := strings.Index(origin, part)
+		if checker.IsGreaterThan(0, index) {
+			return false
+		}
+		origin = origin[index+len(part):]
+	}
+	return checker.IsEmpty(origin)
 }
@@ -0,0 +1,49 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vo
+
+import "testing"
+
+func TestMatchOrigin(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		origin  string
+		want    bool
+	}{
+		{"any", "*", "https://example.com", true},
+		{"exact", "https://example.com", "https://example.com", true},
+		{"exact with another host", "https://example.com", "https://example.org", false},
+		{"exact with a longer origin", "https://example.com", "https://example.com.evil.com", false},
+		{"subdomain", "https://*.example.com", "https://api.example.com", true},
+		{"nested subdomain", "https://*.example.com", "https://v1.api.example.com", true},
+		{"subdomain without the subdomain", "https://*.example.com", "https://example.com", false},
+		{"subdomain with another suffix", "https://*.example.com", "https://api.example.com.evil.com", false},
+		{"subdomain with another scheme", "https://*.example.com", "http://api.example.com", false},
+		{"any port", "http://localhost:*", "http://localhost:3000", true},
+		{"many wildcards", "https://*.example.*", "https://api.example.org", true},
+		{"many wildcards without a part", "https://*.example.*", "https://api.sample.org", false},
+		{"consecutive wildcards", "https://**.example.com", "https://api.example.com", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchOrigin(tt.pattern, tt.origin); got != tt.want {
+				t.Errorf("matchOrigin(%q, %q) = %v, want %v", tt.pattern, tt.origin, got, tt.want)
+			}
+		})
+	}
+}
@@ -43,6 +43,10 @@ func (s StatusCode) Failed() bool {
 	return checker.IsGreaterThanOrEqual(s.Code(), 400)
 }
 
+func (s StatusCode) ServerError() bool {
+	return checker.IsGreaterThanOrEqual(s.Code(), 500)
+}
+
 func (s StatusCode) Code() int {
 	return s.code
 }
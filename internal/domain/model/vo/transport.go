@@ -0,0 +1,92 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vo
+
+import (
+	"github.com/tech4works/checker"
+	"time"
+)
+
+type Transport struct {
+	maxIdleConnsPerHost   int
+	idleConnTimeout       Duration
+	dialTimeout           Duration
+	tlsHandshakeTimeout   Duration
+	responseHeaderTimeout Duration
+	keepAlive             bool
+	http2                 bool
+}
+
+func NewTransport(
+	maxIdleConnsPerHost int,
+	idleConnTimeout,
+	dialTimeout,
+	tlsHandshakeTimeout,
+	responseHeaderTimeout Duration,
+	keepAlive,
+	http2 bool,
+) Transport {
+	return Transport{
+		maxIdleConnsPerHost:   maxIdleConnsPerHost,
+		idleConnTimeout:       idleConnTimeout,
+		dialTimeout:           dialTimeout,
+		tlsHandshakeTimeout:   tlsHandshakeTimeout,
+		responseHeaderTimeout: responseHeaderTimeout,
+		keepAlive:             keepAlive,
+		http2:                 http2,
+	}
+}
+
+func (t Transport) MaxIdleConnsPerHost() int {
+	if checker.IsGreaterThan(t.maxIdleConnsPerHost, 0) {
+		return t.maxIdleConnsPerHost
+	}
+	return 100
+}
+
+func (t Transport) IdleConnTimeout() time.Duration {
+	if checker.IsGreaterThan(t.idleConnTimeout, 0) {
+		return t.idleConnTimeout.Time()
+	}
+	return 90 * time.Second
+}
+
+func (t Transport) DialTimeout() time.Duration {
+	if checker.IsGreaterThan(t.dialTimeout, 0) {
+		return t.dialTimeout.Time()
+	}
+	return 30 * time.Second
+}
+
+func (t Transport) TLSHandshakeTimeout() time.Duration {
+	if checker.IsGreaterThan(t.tlsHandshakeTimeout, 0) {
+		return t.tlsHandshakeTimeout.Time()
+	}
+	return 10 * time.Second
+}
+
+func (t Transport) ResponseHeaderTimeout() time.Duration {
+	return t.responseHeaderTimeout.Time()
+}
+
+func (t Transport) KeepAlive() bool {
+	return t.keepAlive
+}
+
+func (t Transport) HTTP2() bool {
+	return t.http2
+}
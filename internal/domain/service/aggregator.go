@@ -23,6 +23,7 @@ import (
 	"github.com/tech4works/gopen-gateway/internal/domain"
 	"github.com/tech4works/gopen-gateway/internal/domain/mapper"
 	"github.com/tech4works/gopen-gateway/internal/domain/model/vo"
+	"strconv"
 )
 
 type aggregatorService struct {
@@ -34,6 +35,7 @@ type Aggregator interface {
 	AggregateBodyToKey(key string, value *vo.Body) (*vo.Body, error)
 	AggregateBodiesIntoSlice(history *vo.History) (*vo.Body, []error)
 	AggregateBodies(history *vo.History) (*vo.Body, []error)
+	AggregateResponseBodiesIntoArray(responses []*vo.HTTPBackendResponse) (*vo.Body, []error)
 }
 
 func NewAggregator(jsonPath domain.JSONPath) Aggregator {
@@ -135,6 +137,29 @@ func (a aggregatorService) AggregateBodies(history *vo.History) (*vo.Body, []err
 	return a.buildBodyJson(result, errs)
 }
 
+func (a aggregatorService) AggregateResponseBodiesIntoArray(responses []*vo.HTTPBackendResponse) (*vo.Body, []error) {
+	result := "[]"
+
+	var errs []error
+	for _, httpBackendResponse := range responses {
+		raw := "null"
+		if httpBackendResponse.HasBody() {
+			bodyRaw, err := httpBackendResponse.Body().Raw()
+			if checker.NonNil(err) {
+				errs = append(errs, err)
+			} else if checker.IsJSON(bodyRaw) {
+				raw = bodyRaw
+			} else {
+				raw = strconv.Quote(bodyRaw)
+			}
+		}
+
+		result, _ = a.jsonPath.AppendOnArray(result, raw)
+	}
+
+	return a.buildBodyJson(result, errs)
+}
+
 func (a aggregatorService) buildBodyDefaultForSlice(httpBackendResponse *vo.HTTPBackendResponse) string {
 	code := httpBackendResponse.StatusCode()
 
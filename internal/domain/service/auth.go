@@ -0,0 +1,162 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"github.com/tech4works/checker"
+	"github.com/tech4works/gopen-gateway/internal/domain"
+	"github.com/tech4works/gopen-gateway/internal/domain/mapper"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/vo"
+	"slices"
+	"strings"
+	"time"
+)
+
+type authService struct {
+	jwt      domain.JWT
+	jsonPath domain.JSONPath
+}
+
+type Auth interface {
+	AuthenticateJWT(jwt *vo.JWT, request *vo.HTTPRequest) (string, error)
+	AuthenticateAPIKey(gopen *vo.Gopen, endpoint *vo.Endpoint, request *vo.HTTPRequest) (*vo.Consumer, error)
+	AuthenticateAdmin(token string, request *vo.HTTPRequest) error
+}
+
+func NewAuth(jwt domain.JWT, jsonPath domain.JSONPath) Auth {
+	return authService{
+		jwt:      jwt,
+		jsonPath: jsonPath,
+	}
+}
+
+func (a authService) AuthenticateJWT(jwt *vo.JWT, request *vo.HTTPRequest) (string, error) {
+	token, err := a.findBearerToken(request)
+	if checker.NonNil(err) {
+		return "", err
+	}
+
+	claims, err := a.jwt.Verify(token, jwt)
+	if checker.NonNil(err) {
+		return "", mapper.NewErrUnauthorized(err.Error())
+	}
+
+	if err = a.validateTime(jwt, claims); checker.NonNil(err) {
+		return "", err
+	} else if jwt.HasIssuer() && checker.NotEquals(a.jsonPath.Get(claims, "iss").String(), jwt.Issuer()) {
+		return "", mapper.NewErrUnauthorized("token issuer is not accepted")
+	} else if jwt.HasAudience() && !a.matchAudience(jwt, claims) {
+		return "", mapper.NewErrUnauthorized("token audience is not accepted")
+	}
+
+	// the token is valid at this point, so a missing claim means the caller is not allowed
+	for _, claim := range jwt.RequiredClaims() {
+		if a.jsonPath.Get(claims, claim).NotExists() {
+			return "", mapper.NewErrForbidden(fmt.Sprintf("token required claim %s is missing", claim))
+		}
+	}
+
+	return claims, nil
+}
+
+func (a authService) AuthenticateAPIKey(gopen *vo.Gopen, endpoint *vo.Endpoint, request *vo.HTTPRequest) (
+	*vo.Consumer, error) {
+	apiKey := a.findAPIKey(endpoint.APIKey(), request)
+	if checker.IsEmpty(apiKey) {
+		return nil, mapper.NewErrUnauthorized("api key not informed")
+	}
+
+	// only the hashes are configured, so the informed key is hashed before the lookup
+	hash := sha256.Sum256([]byte(apiKey))
+	consumer, ok := gopen.FindConsumer(hex.EncodeToString(hash[:]))
+	if !ok {
+		return nil, mapper.NewErrUnauthorized("api key is not valid")
+	} else if !consumer.Allows(endpoint) {
+		return nil, mapper.NewErrForbidden(fmt.Sprintf("consumer %s is not allowed on endpoint %s %s", consumer.Name(),
+			endpoint.Method(), endpoint.Path()))
+	}
+
+	return consumer, nil
+}
+
+func (a authService) AuthenticateAdmin(token string, request *vo.HTTPRequest) error {
+	bearerToken, err := a.findBearerToken(request)
+	if checker.NonNil(err) {
+		return err
+	}
+
+	// constant time comparison, so the token can't be guessed by the response time
+	if !checker.Equals(subtle.ConstantTimeCompare([]byte(bearerToken), []byte(token)), 1) {
+		return mapper.NewErrUnauthorized("admin token is not valid")
+	}
+	return nil
+}
+
+func (a authService) findAPIKey(apiKey *vo.APIKey, request *vo.HTTPRequest) string {
+	value := request.Header().Get(apiKey.Header())
+	if checker.IsEmpty(value) && apiKey.HasQuery() {
+		value = request.Query().Get(apiKey.Query())
+	}
+	return strings.TrimSpace(value)
+}
+
+func (a authService) findBearerToken(request *vo.HTTPRequest) (string, error) {
+	scheme, token, found := strings.Cut(request.Header().Get(mapper.Authorization), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || checker.IsEmpty(strings.TrimSpace(token)) {
+		return "", mapper.NewErrUnauthorized("bearer token not informed")
+	}
+	return strings.TrimSpace(token), nil
+}
+
+func (a authService) validateTime(jwt *vo.JWT, claims string) error {
+	now := time.Now()
+
+	exp := a.jsonPath.Get(claims, "exp")
+	if exp.Exists() && now.After(a.parseNumericDate(exp).Add(jwt.Leeway())) {
+		return mapper.NewErrUnauthorized("token is expired")
+	}
+
+	nbf := a.jsonPath.Get(claims, "nbf")
+	if nbf.Exists() && now.Add(jwt.Leeway()).Before(a.parseNumericDate(nbf)) {
+		return mapper.NewErrUnauthorized("token is not valid yet")
+	}
+
+	return nil
+}
+
+func (a authService) parseNumericDate(value domain.JSONValue) time.Time {
+	seconds, _ := value.Interface().(float64)
+	return time.Unix(0, int64(seconds*float64(time.Second)))
+}
+
+func (a authService) matchAudience(jwt *vo.JWT, claims string) bool {
+	aud := a.jsonPath.Get(claims, "aud")
+	if !aud.IsArray() {
+		return slices.Contains(jwt.Audience(), aud.String())
+	}
+
+	match := false
+	aud.ForEach(func(_ string, value domain.JSONValue) bool {
+		match = slices.Contains(jwt.Audience(), value.String())
+		return !match
+	})
+	return match
+}
@@ -0,0 +1,294 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/tech4works/errors"
+	"github.com/tech4works/gopen-gateway/internal/domain/mapper"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/vo"
+	"github.com/tech4works/gopen-gateway/internal/infra/jsonpath"
+	"testing"
+	"time"
+)
+
+type fakeJWT struct {
+	claims string
+	err    error
+}
+
+func (f fakeJWT) Verify(string, *vo.JWT) (string, error) {
+	return f.claims, f.err
+}
+
+func TestAuthAuthenticateJWT(t *testing.T) {
+	now := time.Now().Unix()
+	leeway := vo.NewDuration(time.Minute)
+
+	tests := []struct {
+		name          string
+		jwt           *vo.JWT
+		authorization string
+		claims        string
+		verifyErr     error
+		wantErr       *error
+	}{
+		{
+			name:          "valid token",
+			jwt:           newTestJWT(nil, 0),
+			authorization: "Bearer token",
+			claims:        fmt.Sprintf(`{"sub":"user-1","iss":"https://auth.example.com","aud":"api","exp":%d}`, now+60),
+		},
+		{
+			name:    "without authorization",
+			jwt:     newTestJWT(nil, 0),
+			claims:  `{"sub":"user-1"}`,
+			wantErr: &mapper.ErrUnauthorized,
+		},
+		{
+			name:          "another scheme",
+			jwt:           newTestJWT(nil, 0),
+			authorization: "Basic dXNlcjpwYXNz",
+			claims:        `{"sub":"user-1"}`,
+			wantErr:       &mapper.ErrUnauthorized,
+		},
+		{
+			name:          "invalid signature",
+			jwt:           newTestJWT(nil, 0),
+			authorization: "Bearer token",
+			verifyErr:     errors.New("token signature is invalid"),
+			wantErr:       &mapper.ErrUnauthorized,
+		},
+		{
+			name:          "expired token",
+			jwt:           newTestJWT(nil, 0),
+			authorization: "Bearer token",
+			claims:        fmt.Sprintf(`{"sub":"user-1","iss":"https://auth.example.com","aud":"api","exp":%d}`, now-30),
+			wantErr:       &mapper.ErrUnauthorized,
+		},
+		{
+			name:          "expired token within leeway",
+			jwt:           newTestJWT(nil, leeway),
+			authorization: "Bearer token",
+			claims:        fmt.Sprintf(`{"sub":"user-1","iss":"https://auth.example.com","aud":"api","exp":%d}`, now-30),
+		},
+		{
+			name:          "token not valid yet",
+			jwt:           newTestJWT(nil, 0),
+			authorization: "Bearer token",
+			claims:        fmt.Sprintf(`{"sub":"user-1","iss":"https://auth.example.com","aud":"api","nbf":%d}`, now+30),
+			wantErr:       &mapper.ErrUnauthorized,
+		},
+		{
+			name:          "token not valid yet within leeway",
+			jwt:           newTestJWT(nil, leeway),
+			authorization: "Bearer token",
+			claims:        fmt.Sprintf(`{"sub":"user-1","iss":"https://auth.example.com","aud":"api","nbf":%d}`, now+30),
+		},
+		{
+			name:          "another issuer",
+			jwt:           newTestJWT(nil, 0),
+			authorization: "Bearer token",
+			claims:        `{"sub":"user-1","iss":"https://evil.com","aud":"api"}`,
+			wantErr:       &mapper.ErrUnauthorized,
+		},
+		{
+			name:          "without issuer",
+			jwt:           newTestJWT(nil, 0),
+			authorization: "Bearer token",
+			claims:        `{"sub":"user-1","aud":"api"}`,
+			wantErr:       &mapper.ErrUnauthorized,
+		},
+		{
+			name:          "another audience",
+			jwt:           newTestJWT(nil, 0),
+			authorization: "Bearer token",
+			claims:        `{"sub":"user-1","iss":"https://auth.example.com","aud":"web"}`,
+			wantErr:       &mapper.ErrUnauthorized,
+		},
+		{
+			name:          "audience array",
+			jwt:           newTestJWT(nil, 0),
+			authorization: "Bearer token",
+			claims:        `{"sub":"user-1","iss":"https://auth.example.com","aud":["web","api"]}`,
+		},
+		{
+			name:          "audience array without accepted audience",
+			jwt:           newTestJWT(nil, 0),
+			authorization: "Bearer token",
+			claims:        `{"sub":"user-1","iss":"https://auth.example.com","aud":["web","mobile"]}`,
+			wantErr:       &mapper.ErrUnauthorized,
+		},
+		{
+			name:          "required claims",
+			jwt:           newTestJWT([]string{"sub", "scope"}, 0),
+			authorization: "Bearer token",
+			claims:        `{"sub":"user-1","iss":"https://auth.example.com","aud":"api","scope":"read"}`,
+		},
+		{
+			name:          "missing required claim",
+			jwt:           newTestJWT([]string{"sub", "scope"}, 0),
+			authorization: "Bearer token",
+			claims:        `{"sub":"user-1","iss":"https://auth.example.com","aud":"api"}`,
+			wantErr:       &mapper.ErrForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewAuth(fakeJWT{claims: tt.claims, err: tt.verifyErr}, jsonpath.New())
+			header := map[string][]string{}
+			if tt.authorization != "" {
+				header["Authorization"] = []string{tt.authorization}
+			}
+			request := vo.NewHTTPRequest(vo.NewURLPath("/users", nil), "/users", "GET", vo.NewHeader(header),
+				vo.NewEmptyQuery(), nil)
+
+			got, err := service.AuthenticateJWT(tt.jwt, request)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("AuthenticateJWT() err = %v, want nil", err)
+			} else if tt.wantErr != nil && !errors.Is(err, *tt.wantErr) {
+				t.Fatalf("AuthenticateJWT() err = %v, want %v", err, *tt.wantErr)
+			} else if tt.wantErr == nil && got != tt.claims {
+				t.Errorf("AuthenticateJWT() = %s, want %s", got, tt.claims)
+			}
+		})
+	}
+}
+
+func TestAuthAuthenticateAPIKey(t *testing.T) {
+	tests := []struct {
+		name         string
+		header       map[string][]string
+		query        map[string][]string
+		endpoint     vo.Endpoint
+		wantConsumer string
+		wantErr      *error
+	}{
+		{
+			name:         "header key",
+			header:       map[string][]string{"X-Api-Key": {"key-mobile"}},
+			endpoint:     newTestAPIKeyEndpoint("GET", "/users"),
+			wantConsumer: "mobile",
+		},
+		{
+			name:         "query key",
+			query:        map[string][]string{"api_key": {"key-web"}},
+			endpoint:     newTestAPIKeyEndpoint("GET", "/users"),
+			wantConsumer: "web",
+		},
+		{
+			name:     "without key",
+			endpoint: newTestAPIKeyEndpoint("GET", "/users"),
+			wantErr:  &mapper.ErrUnauthorized,
+		},
+		{
+			name:     "unknown key",
+			header:   map[string][]string{"X-Api-Key": {"key-unknown"}},
+			endpoint: newTestAPIKeyEndpoint("GET", "/users"),
+			wantErr:  &mapper.ErrUnauthorized,
+		},
+		{
+			name:     "hashed key",
+			header:   map[string][]string{"X-Api-Key": {newTestAPIKeyHash("key-mobile")}},
+			endpoint: newTestAPIKeyEndpoint("GET", "/users"),
+			wantErr:  &mapper.ErrUnauthorized,
+		},
+		{
+			name:         "allowed endpoint by method and path",
+			header:       map[string][]string{"X-Api-Key": {"key-mobile"}},
+			endpoint:     newTestAPIKeyEndpoint("GET", "/orders"),
+			wantConsumer: "mobile",
+		},
+		{
+			name:     "endpoint not allowed",
+			header:   map[string][]string{"X-Api-Key": {"key-mobile"}},
+			endpoint: newTestAPIKeyEndpoint("POST", "/orders"),
+			wantErr:  &mapper.ErrForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewAuth(fakeJWT{}, jsonpath.New())
+			gopen := vo.NewGopen(nil, nil, nil, []vo.Consumer{
+				vo.NewConsumer("mobile", []string{newTestAPIKeyHash("key-mobile")}, []string{"/users", "GET /orders"},
+					nil),
+				vo.NewConsumer("web", []string{newTestAPIKeyHash("key-web")}, nil, nil),
+			}, nil)
+			request := vo.NewHTTPRequest(vo.NewURLPath(tt.endpoint.Path(), nil), tt.endpoint.Path(),
+				tt.endpoint.Method(), vo.NewHeader(tt.header), vo.NewQuery(tt.query), nil)
+
+			got, err := service.AuthenticateAPIKey(gopen, &tt.endpoint, request)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("AuthenticateAPIKey() err = %v, want nil", err)
+			} else if tt.wantErr != nil && !errors.Is(err, *tt.wantErr) {
+				t.Fatalf("AuthenticateAPIKey() err = %v, want %v", err, *tt.wantErr)
+			} else if tt.wantErr == nil && got.Name() != tt.wantConsumer {
+				t.Errorf("AuthenticateAPIKey() consumer = %s, want %s", got.Name(), tt.wantConsumer)
+			}
+		})
+	}
+}
+
+func TestAuthAuthenticateAdmin(t *testing.T) {
+	tests := []struct {
+		name          string
+		authorization string
+		wantErr       *error
+	}{
+		{"valid token", "Bearer s3cr3t", nil},
+		{"lowercase scheme", "bearer s3cr3t", nil},
+		{"wrong token", "Bearer other", &mapper.ErrUnauthorized},
+		{"token prefix", "Bearer s3cr3", &mapper.ErrUnauthorized},
+		{"basic scheme", "Basic s3cr3t", &mapper.ErrUnauthorized},
+		{"empty bearer", "Bearer ", &mapper.ErrUnauthorized},
+		{"missing header", "", &mapper.ErrUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewAuth(fakeJWT{}, jsonpath.New())
+			header := map[string][]string{}
+			if tt.authorization != "" {
+				header["Authorization"] = []string{tt.authorization}
+			}
+			request := vo.NewHTTPRequest(vo.NewURLPath("/admin/cache", nil), "/admin/cache", "DELETE",
+				vo.NewHeader(header), vo.NewEmptyQuery(), nil)
+
+			err := service.AuthenticateAdmin("s3cr3t", request)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("AuthenticateAdmin() err = %v, want nil", err)
+			} else if tt.wantErr != nil && !errors.Is(err, *tt.wantErr) {
+				t.Fatalf("AuthenticateAdmin() err = %v, want %v", err, *tt.wantErr)
+			}
+		})
+	}
+}
+
+func newTestJWT(requiredClaims []string, leeway vo.Duration) *vo.JWT {
+	return vo.NewJWT(nil, nil, "https://auth.example.com", []string{"api"}, requiredClaims, leeway)
+}
+
+func newTestAPIKeyEndpoint(method, path string) vo.Endpoint {
+	return vo.NewEndpoint(path, method, 0, vo.NewLimiterDefault(), nil, nil, nil, vo.NewAPIKey("", "api_key"), nil,
+		nil, nil)
+}
+
+func newTestAPIKeyHash(apiKey string) string {
+	hash := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(hash[:])
+}
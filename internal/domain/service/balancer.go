@@ -0,0 +1,180 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"github.com/tech4works/checker"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/enum"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/vo"
+	"hash/fnv"
+	"strings"
+	"sync"
+)
+
+type balancerService struct {
+	healthService  Health
+	mutex          *sync.Mutex
+	counters       map[string]int
+	currentWeights map[string]map[string]int
+	inFlight       map[string]int
+}
+
+type Balancer interface {
+	SelectHost(backend *vo.Backend, request *vo.HTTPRequest) string
+	Acquire(host string)
+	Release(host string)
+}
+
+func NewBalancer(healthService Health) Balancer {
+	return &balancerService{
+		healthService:  healthService,
+		mutex:          &sync.Mutex{},
+		counters:       map[string]int{},
+		currentWeights: map[string]map[string]int{},
+		inFlight:       map[string]int{},
+	}
+}
+
+func (b *balancerService) SelectHost(backend *vo.Backend, request *vo.HTTPRequest) string {
+	hosts := backend.Hosts()
+	// down hosts are skipped, if none is available we keep all of them so the request can still be attempted
+	if availableHosts := b.healthService.AvailableHosts(hosts); checker.IsNotEmpty(availableHosts) {
+		hosts = availableHosts
+	}
+	if checker.IsLengthEquals(hosts, 1) {
+		return hosts[0]
+	}
+
+	balancer := backend.Balancer()
+	switch balancer.Algorithm() {
+	case enum.BalancerAlgorithmWeighted:
+		return b.selectWeighted(hosts, balancer)
+	case enum.BalancerAlgorithmLeastInFlight:
+		return b.selectLeastInFlight(hosts)
+	case enum.BalancerAlgorithmConsistentHash:
+		return b.selectConsistentHash(hosts, b.buildHashKey(balancer, request))
+	default:
+		return b.selectRoundRobin(hosts)
+	}
+}
+
+func (b *balancerService) Acquire(host string) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.inFlight[host]++
+}
+
+func (b *balancerService) Release(host string) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.inFlight[host]--
+	if checker.IsLessThanOrEqual(b.inFlight[host], 0) {
+		delete(b.inFlight, host)
+	}
+}
+
+func (b *balancerService) selectRoundRobin(hosts []string) string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	key := b.buildGroupKey(hosts)
+	counter := b.counters[key]
+	b.counters[key] = (counter + 1) % len(hosts)
+
+	return hosts[counter%len(hosts)]
+}
+
+func (b *balancerService) selectWeighted(hosts []string, balancer *vo.Balancer) string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	key := b.buildGroupKey(hosts)
+	currentWeights, ok := b.currentWeights[key]
+	if !ok {
+		currentWeights = map[string]int{}
+		b.currentWeights[key] = currentWeights
+	}
+
+	// smooth weighted round-robin, every host gains its weight and the chosen one loses the total
+	var selected string
+	total := 0
+	for _, host := range hosts {
+		weight := balancer.Weight(host)
+		total += weight
+		currentWeights[host] += weight
+		if checker.IsEmpty(selected) || checker.IsGreaterThan(currentWeights[host], currentWeights[selected]) {
+			selected = host
+		}
+	}
+	currentWeights[selected] -= total
+
+	return selected
+}
+
+func (b *balancerService) selectLeastInFlight(hosts []string) string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	key := b.buildGroupKey(hosts)
+	offset := b.counters[key]
+	b.counters[key] = (offset + 1) % len(hosts)
+
+	// we start from a rotating offset so that hosts with the same amount of requests are chosen evenly
+	selected := hosts[offset%len(hosts)]
+	for i := 1; i < len(hosts); i++ {
+		host := hosts[(offset+i)%len(hosts)]
+		if checker.IsGreaterThan(b.inFlight[selected], b.inFlight[host]) {
+			selected = host
+		}
+	}
+
+	return selected
+}
+
+func (b *balancerService) selectConsistentHash(hosts []string, hashKey string) string {
+	// rendezvous hashing, the same key always reaches the same host while it is available
+	var selected string
+	var maxScore uint64
+	for _, host := range hosts {
+		hash := fnv.New64a()
+		hash.Write([]byte(hashKey))
+		hash.Write([]byte(host))
+
+		score := hash.Sum64()
+		if checker.IsEmpty(selected) || score > maxScore {
+			selected = host
+			maxScore = score
+		}
+	}
+	return selected
+}
+
+func (b *balancerService) buildHashKey(balancer *vo.Balancer, request *vo.HTTPRequest) string {
+	// without the header value all the traffic would go to the same host, so the client ip is used instead
+	if checker.Equals(balancer.HashKey(), enum.BalancerHashKeyHeader) {
+		if value := request.Header().Get(balancer.HashHeader()); checker.IsNotEmpty(value) {
+			return value
+		}
+	}
+	return request.ClientIP()
+}
+
+func (b *balancerService) buildGroupKey(hosts []string) string {
+	return strings.Join(hosts, ",")
+}
@@ -0,0 +1,164 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"github.com/tech4works/gopen-gateway/internal/domain/mapper"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/enum"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/vo"
+	"slices"
+	"testing"
+)
+
+func TestBalancerSelectRoundRobin(t *testing.T) {
+	tests := []struct {
+		name  string
+		hosts []string
+		want  []string
+	}{
+		{"two hosts", []string{"a", "b"}, []string{"a", "b", "a", "b"}},
+		{"three hosts", []string{"a", "b", "c"}, []string{"a", "b", "c", "a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			balancer := newTestBalancer()
+
+			var got []string
+			for range tt.want {
+				got = append(got, balancer.selectRoundRobin(tt.hosts))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("selectRoundRobin() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBalancerSelectWeighted(t *testing.T) {
+	tests := []struct {
+		name    string
+		hosts   []string
+		weights []int
+		want    []string
+	}{
+		{"without weights", []string{"a", "b"}, nil, []string{"a", "b", "a", "b"}},
+		{"two to one", []string{"a", "b"}, []int{2, 1}, []string{"a", "b", "a", "a", "b", "a"}},
+		{"smooth", []string{"a", "b", "c"}, []int{5, 1, 1}, []string{"a", "a", "b", "a", "c", "a", "a"}},
+		{"more weights than hosts", []string{"a", "b"}, []int{2, 1, 3}, []string{"a", "b", "a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			balancer := newTestBalancer()
+			config := vo.NewBalancer(enum.BalancerAlgorithmWeighted, tt.hosts, tt.weights, "", "")
+
+			var got []string
+			for range tt.want {
+				got = append(got, balancer.selectWeighted(tt.hosts, config))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("selectWeighted() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBalancerSelectLeastInFlight(t *testing.T) {
+	tests := []struct {
+		name     string
+		hosts    []string
+		inFlight []string
+		want     []string
+	}{
+		{"one busy host", []string{"a", "b"}, []string{"a"}, []string{"b", "b"}},
+		{"least busy host", []string{"a", "b", "c"}, []string{"a", "a", "b", "c", "c"}, []string{"b", "b", "b"}},
+		{"without requests in flight", []string{"a", "b", "c"}, nil, []string{"a", "b", "c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			balancer := newTestBalancer()
+			for _, host := range tt.inFlight {
+				balancer.Acquire(host)
+			}
+
+			var got []string
+			for range tt.want {
+				got = append(got, balancer.selectLeastInFlight(tt.hosts))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("selectLeastInFlight() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBalancerSelectConsistentHash(t *testing.T) {
+	hosts := []string{"a", "b", "c", "d"}
+
+	tests := []string{"10.0.0.1", "10.0.0.2", "user-1", "user-2"}
+	for _, hashKey := range tests {
+		t.Run(hashKey, func(t *testing.T) {
+			balancer := newTestBalancer()
+
+			selected := balancer.selectConsistentHash(hosts, hashKey)
+			if got := balancer.selectConsistentHash(hosts, hashKey); got != selected {
+				t.Errorf("selectConsistentHash() = %s, want the same host %s", got, selected)
+			}
+
+			// the key only moves when its own host is removed
+			for _, removed := range hosts {
+				if removed == selected {
+					continue
+				}
+				remaining := slices.DeleteFunc(slices.Clone(hosts), func(host string) bool { return host == removed })
+				if got := balancer.selectConsistentHash(remaining, hashKey); got != selected {
+					t.Errorf("selectConsistentHash() without %s = %s, want %s", removed, got, selected)
+				}
+			}
+		})
+	}
+}
+
+func TestBalancerBuildHashKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		hashKey enum.BalancerHashKey
+		header  map[string][]string
+		want    string
+	}{
+		{"client ip", enum.BalancerHashKeyClientIP, map[string][]string{"X-User": {"user-1"}}, "10.0.0.1"},
+		{"header", enum.BalancerHashKeyHeader, map[string][]string{"X-User": {"user-1"}}, "user-1"},
+		{"empty header", enum.BalancerHashKeyHeader, map[string][]string{"X-User": {""}}, "10.0.0.1"},
+		{"missing header", enum.BalancerHashKeyHeader, map[string][]string{}, "10.0.0.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			balancer := newTestBalancer()
+			config := vo.NewBalancer(enum.BalancerAlgorithmConsistentHash, nil, nil, tt.hashKey, "X-User")
+
+			tt.header[mapper.XForwardedFor] = []string{"10.0.0.1"}
+			request := vo.NewHTTPRequest(vo.NewURLPath("/", nil), "/", "GET", vo.NewHeader(tt.header),
+				vo.NewEmptyQuery(), nil)
+			if got := balancer.buildHashKey(config, request); got != tt.want {
+				t.Errorf("buildHashKey() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func newTestBalancer() *balancerService {
+	return NewBalancer(NewHealth()).(*balancerService)
+}
@@ -36,6 +36,9 @@ type cacheService struct {
 type Cache interface {
 	Read(ctx context.Context, cache *vo.Cache, request *vo.HTTPRequest) (*vo.CacheResponse, error)
 	Write(ctx context.Context, cache *vo.Cache, request *vo.HTTPRequest, response *vo.HTTPResponse) error
+	CanRead(cache *vo.Cache, request *vo.HTTPRequest) bool
+	CanWrite(cache *vo.Cache, request *vo.HTTPRequest, response *vo.HTTPResponse) bool
+	Purge(ctx context.Context, pattern string) (int, error)
 }
 
 func NewCache(store domain.Store) Cache {
@@ -45,7 +48,7 @@ func NewCache(store domain.Store) Cache {
 }
 
 func (c cacheService) Read(ctx context.Context, cache *vo.Cache, request *vo.HTTPRequest) (*vo.CacheResponse, error) {
-	if !c.canRead(cache, request) {
+	if !c.CanRead(cache, request) {
 		return nil, nil
 	}
 
@@ -60,14 +63,14 @@ func (c cacheService) Read(ctx context.Context, cache *vo.Cache, request *vo.HTT
 }
 
 func (c cacheService) Write(ctx context.Context, cache *vo.Cache, request *vo.HTTPRequest, response *vo.HTTPResponse) error {
-	if !c.canWrite(cache, request, response) {
+	if !c.CanWrite(cache, request, response) {
 		return nil
 	}
 
 	return c.store.Set(ctx, c.buildKey(cache, request), vo.NewCacheResponse(cache, response))
 }
 
-func (c cacheService) canRead(cache *vo.Cache, request *vo.HTTPRequest) bool {
+func (c cacheService) CanRead(cache *vo.Cache, request *vo.HTTPRequest) bool {
 	if cache.Disabled() {
 		return false
 	}
@@ -76,7 +79,7 @@ func (c cacheService) canRead(cache *vo.Cache, request *vo.HTTPRequest) bool {
 		c.allowMethod(cache, request)
 }
 
-func (c cacheService) canWrite(cache *vo.Cache, request *vo.HTTPRequest, response *vo.HTTPResponse) bool {
+func (c cacheService) CanWrite(cache *vo.Cache, request *vo.HTTPRequest, response *vo.HTTPResponse) bool {
 	if cache.Disabled() {
 		return false
 	}
@@ -85,6 +88,10 @@ func (c cacheService) canWrite(cache *vo.Cache, request *vo.HTTPRequest, respons
 		c.allowMethod(cache, request) && c.allowStatusCode(cache, response)
 }
 
+func (c cacheService) Purge(ctx context.Context, pattern string) (int, error) {
+	return c.store.DelByPattern(ctx, pattern)
+}
+
 func (c cacheService) buildKey(cache *vo.Cache, request *vo.HTTPRequest) string {
 	url := request.Url()
 	if cache.IgnoreQuery() {
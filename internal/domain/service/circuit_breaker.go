@@ -0,0 +1,169 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"fmt"
+	"github.com/tech4works/checker"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/enum"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/vo"
+	"strings"
+	"sync"
+	"time"
+)
+
+type circuitBreakerService struct {
+	mutex    *sync.Mutex
+	circuits map[string]*circuit
+}
+
+type circuit struct {
+	state               enum.CircuitBreakerState
+	windowStart         time.Time
+	requests            int
+	failures            int
+	consecutiveFailures int
+	openedAt            time.Time
+	halfOpenInFlight    int
+}
+
+type CircuitBreaker interface {
+	Allow(backend *vo.Backend) bool
+	Record(backend *vo.Backend, response *vo.HTTPBackendResponse)
+}
+
+func NewCircuitBreaker() CircuitBreaker {
+	return &circuitBreakerService{
+		mutex:    &sync.Mutex{},
+		circuits: map[string]*circuit{},
+	}
+}
+
+func (c *circuitBreakerService) Allow(backend *vo.Backend) bool {
+	if !backend.HasCircuitBreaker() {
+		return true
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	circuitBreaker := backend.CircuitBreaker()
+	current := c.getCircuit(backend)
+
+	switch current.state {
+	case enum.CircuitBreakerStateOpen:
+		if checker.IsGreaterThan(circuitBreaker.Cooldown(), time.Since(current.openedAt)) {
+			return false
+		}
+		current.state = enum.CircuitBreakerStateHalfOpen
+		current.halfOpenInFlight = 0
+		fallthrough
+	case enum.CircuitBreakerStateHalfOpen:
+		// only a few requests are let through to test if the backend has recovered
+		if checker.IsGreaterThanOrEqual(current.halfOpenInFlight, circuitBreaker.HalfOpenRequests()) {
+			return false
+		}
+		current.halfOpenInFlight++
+	}
+	return true
+}
+
+func (c *circuitBreakerService) Record(backend *vo.Backend, response *vo.HTTPBackendResponse) {
+	if !backend.HasCircuitBreaker() {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	current := c.getCircuit(backend)
+	if checker.Equals(current.state, enum.CircuitBreakerStateHalfOpen) &&
+		checker.IsGreaterThan(current.halfOpenInFlight, 0) {
+		current.halfOpenInFlight--
+	}
+
+	// canceled requests say nothing about the backend health
+	if checker.IsNil(response) {
+		return
+	}
+	failed := checker.IsGreaterThanOrEqual(response.StatusCode().Code(), 500)
+
+	switch current.state {
+	case enum.CircuitBreakerStateHalfOpen:
+		if failed {
+			c.open(current)
+		} else {
+			c.close(current)
+		}
+	case enum.CircuitBreakerStateClosed:
+		c.count(backend.CircuitBreaker(), current, failed)
+	}
+}
+
+func (c *circuitBreakerService) count(circuitBreaker *vo.CircuitBreaker, current *circuit, failed bool) {
+	if checker.IsGreaterThan(time.Since(current.windowStart), circuitBreaker.Window()) {
+		current.windowStart = time.Now()
+		current.requests = 0
+		current.failures = 0
+	}
+
+	current.requests++
+	if failed {
+		current.failures++
+		current.consecutiveFailures++
+	} else {
+		current.consecutiveFailures = 0
+	}
+
+	if circuitBreaker.HasConsecutiveFailures() &&
+		checker.IsGreaterThanOrEqual(current.consecutiveFailures, circuitBreaker.ConsecutiveFailures()) {
+		c.open(current)
+	} else if circuitBreaker.HasErrorRatio() &&
+		checker.IsGreaterThanOrEqual(current.requests, circuitBreaker.MinRequests()) &&
+		checker.IsGreaterThanOrEqual(float64(current.failures)/float64(current.requests), circuitBreaker.ErrorRatio()) {
+		c.open(current)
+	}
+}
+
+func (c *circuitBreakerService) open(current *circuit) {
+	current.state = enum.CircuitBreakerStateOpen
+	current.openedAt = time.Now()
+	current.halfOpenInFlight = 0
+}
+
+func (c *circuitBreakerService) close(current *circuit) {
+	current.state = enum.CircuitBreakerStateClosed
+	current.windowStart = time.Now()
+	current.requests = 0
+	current.failures = 0
+	current.consecutiveFailures = 0
+	current.halfOpenInFlight = 0
+}
+
+func (c *circuitBreakerService) getCircuit(backend *vo.Backend) *circuit {
+	key := fmt.Sprint(backend.Method(), " ", strings.Join(backend.Hosts(), ","), backend.Path())
+
+	current, exists := c.circuits[key]
+	if !exists {
+		current = &circuit{
+			state:       enum.CircuitBreakerStateClosed,
+			windowStart: time.Now(),
+		}
+		c.circuits[key] = current
+	}
+	return current
+}
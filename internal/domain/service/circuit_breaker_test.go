@@ -0,0 +1,153 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"github.com/tech4works/gopen-gateway/internal/domain/model/enum"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/vo"
+	"testing"
+	"time"
+)
+
+type circuitBreakerStep struct {
+	action string
+	status int
+	want   bool
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	tests := []struct {
+		name           string
+		circuitBreaker *vo.CircuitBreaker
+		steps          []circuitBreakerStep
+	}{
+		{
+			name:           "opens after the consecutive failures",
+			circuitBreaker: vo.NewCircuitBreaker(0, 0, 0, 2, 0, 1, nil),
+			steps: []circuitBreakerStep{
+				{action: "record", status: 500},
+				{action: "allow", want: true},
+				{action: "record", status: 502},
+				{action: "allow", want: false},
+			},
+		},
+		{
+			name:           "success resets the consecutive failures",
+			circuitBreaker: vo.NewCircuitBreaker(0, 0, 0, 2, 0, 1, nil),
+			steps: []circuitBreakerStep{
+				{action: "record", status: 500},
+				{action: "record", status: 200},
+				{action: "record", status: 500},
+				{action: "allow", want: true},
+			},
+		},
+		{
+			name:           "client errors are not failures",
+			circuitBreaker: vo.NewCircuitBreaker(0, 0, 0, 1, 0, 1, nil),
+			steps: []circuitBreakerStep{
+				{action: "record", status: 404},
+				{action: "allow", want: true},
+			},
+		},
+		{
+			name:           "opens at the error ratio after the min requests",
+			circuitBreaker: vo.NewCircuitBreaker(0, 4, 0.5, 0, 0, 1, nil),
+			steps: []circuitBreakerStep{
+				{action: "record", status: 500},
+				{action: "record", status: 500},
+				{action: "record", status: 200},
+				{action: "allow", want: true},
+				{action: "record", status: 200},
+				{action: "allow", want: false},
+			},
+		},
+		{
+			name:           "half-open lets a limited number of requests through after the cooldown",
+			circuitBreaker: vo.NewCircuitBreaker(0, 0, 0, 1, 0, 2, nil),
+			steps: []circuitBreakerStep{
+				{action: "record", status: 500},
+				{action: "allow", want: false},
+				{action: "cooldown"},
+				{action: "allow", want: true},
+				{action: "allow", want: true},
+				{action: "allow", want: false},
+			},
+		},
+		{
+			name:           "half-open closes on success",
+			circuitBreaker: vo.NewCircuitBreaker(0, 0, 0, 1, 0, 1, nil),
+			steps: []circuitBreakerStep{
+				{action: "record", status: 500},
+				{action: "cooldown"},
+				{action: "allow", want: true},
+				{action: "record", status: 200},
+				{action: "allow", want: true},
+				{action: "allow", want: true},
+			},
+		},
+		{
+			name:           "half-open opens again on failure",
+			circuitBreaker: vo.NewCircuitBreaker(0, 0, 0, 1, 0, 1, nil),
+			steps: []circuitBreakerStep{
+				{action: "record", status: 500},
+				{action: "cooldown"},
+				{action: "allow", want: true},
+				{action: "record", status: 503},
+				{action: "allow", want: false},
+			},
+		},
+		{
+			name:           "half-open releases the slot of a canceled request",
+			circuitBreaker: vo.NewCircuitBreaker(0, 0, 0, 1, 0, 1, nil),
+			steps: []circuitBreakerStep{
+				{action: "record", status: 500},
+				{action: "cooldown"},
+				{action: "allow", want: true},
+				{action: "cancel"},
+				{action: "allow", want: true},
+				{action: "allow", want: false},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewCircuitBreaker().(*circuitBreakerService)
+			backend := newTestCircuitBreakerBackend(tt.circuitBreaker)
+
+			for i, step := range tt.steps {
+				switch step.action {
+				case "allow":
+					if got := service.Allow(&backend); got != step.want {
+						t.Fatalf("step #%d Allow() = %v, want %v", i, got, step.want)
+					}
+				case "record":
+					service.Record(&backend, vo.NewHTTPBackendResponse(vo.NewStatusCode(step.status), vo.NewHeader(nil),
+						nil))
+				case "cancel":
+					service.Record(&backend, nil)
+				case "cooldown":
+					service.getCircuit(&backend).openedAt = time.Now().Add(-tt.circuitBreaker.Cooldown())
+				}
+			}
+		})
+	}
+}
+
+func newTestCircuitBreakerBackend(circuitBreaker *vo.CircuitBreaker) vo.Backend {
+	return vo.NewBackend(enum.BackendTypeNormal, []string{"http://localhost:8080"}, "/users", "GET", nil, nil,
+		circuitBreaker, nil, nil, nil, nil, nil, vo.Transport{}, nil, nil)
+}
@@ -0,0 +1,102 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"github.com/tech4works/checker"
+	"github.com/tech4works/errors"
+	"github.com/tech4works/gopen-gateway/internal/domain"
+	"github.com/tech4works/gopen-gateway/internal/domain/mapper"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/enum"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/vo"
+	"slices"
+)
+
+type conditionService struct {
+	dynamicValueService DynamicValue
+}
+
+type Condition interface {
+	Evaluate(conditions []vo.Condition, request *vo.HTTPRequest, history *vo.History) (bool, []error)
+}
+
+func NewCondition(dynamicValueService DynamicValue) Condition {
+	return conditionService{
+		dynamicValueService: dynamicValueService,
+	}
+}
+
+func (c conditionService) Evaluate(conditions []vo.Condition, request *vo.HTTPRequest, history *vo.History) (
+	bool, []error) {
+	var errs []error
+	for _, condition := range conditions {
+		result, err := c.evaluate(condition, request, history)
+		if checker.NonNil(err) {
+			errs = append(errs, err)
+		}
+		if !result {
+			return false, errs
+		}
+	}
+	return true, errs
+}
+
+func (c conditionService) evaluate(condition vo.Condition, request *vo.HTTPRequest, history *vo.History) (bool,
+	error) {
+	value, err := c.dynamicValueService.GetJSONValue(condition.Value(), request, history)
+	if checker.NonNil(err) && !errors.Is(err, mapper.ErrValueNotFound) {
+		return false, err
+	}
+	exists := checker.IsNil(err)
+
+	switch condition.Operator() {
+	case enum.ConditionOperatorExists:
+		return exists, nil
+	case enum.ConditionOperatorNotExists:
+		return !exists, nil
+	case enum.ConditionOperatorEquals, enum.ConditionOperatorIn:
+		return exists && c.matchAny(value, condition.Expected()), nil
+	case enum.ConditionOperatorNotEquals, enum.ConditionOperatorNotIn:
+		return !exists || !c.matchAny(value, condition.Expected()), nil
+	case enum.ConditionOperatorFalsy:
+		return !exists || !c.isTruthy(value), nil
+	default:
+		return exists && c.isTruthy(value), nil
+	}
+}
+
+func (c conditionService) matchAny(value domain.JSONValue, expected []string) bool {
+	// header and query values are arrays, so any of their items can match
+	if !value.IsArray() {
+		return slices.Contains(expected, value.String())
+	}
+
+	matched := false
+	value.ForEach(func(key string, item domain.JSONValue) bool {
+		matched = slices.Contains(expected, item.String())
+		return !matched
+	})
+	return matched
+}
+
+func (c conditionService) isTruthy(value domain.JSONValue) bool {
+	switch value.Raw() {
+	case "", "false", "0", "null", `""`, "[]", "{}":
+		return false
+	}
+	return true
+}
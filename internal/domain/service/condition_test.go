@@ -0,0 +1,116 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"bytes"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/enum"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/vo"
+	"github.com/tech4works/gopen-gateway/internal/infra/jsonpath"
+	"testing"
+)
+
+func TestConditionEvaluate(t *testing.T) {
+	tests := []struct {
+		name       string
+		conditions []vo.Condition
+		want       bool
+	}{
+		{
+			name: "without conditions",
+			want: true,
+		},
+		{
+			name:       "exists",
+			conditions: []vo.Condition{vo.NewCondition("#request.header.X-Role", enum.ConditionOperatorExists, nil)},
+			want:       true,
+		},
+		{
+			name:       "missing value exists",
+			conditions: []vo.Condition{vo.NewCondition("#request.header.X-Missing", enum.ConditionOperatorExists, nil)},
+			want:       false,
+		},
+		{
+			name: "missing value not exists",
+			conditions: []vo.Condition{
+				vo.NewCondition("#request.header.X-Missing", enum.ConditionOperatorNotExists, nil),
+			},
+			want: true,
+		},
+		{
+			name: "header item equals",
+			conditions: []vo.Condition{
+				vo.NewCondition("#request.header.X-Role", enum.ConditionOperatorEquals, []string{"admin"}),
+			},
+			want: true,
+		},
+		{
+			name: "body value in",
+			conditions: []vo.Condition{
+				vo.NewCondition("#request.body.plan", enum.ConditionOperatorIn, []string{"pro", "enterprise"}),
+			},
+			want: true,
+		},
+		{
+			name: "body value not in",
+			conditions: []vo.Condition{
+				vo.NewCondition("#request.body.plan", enum.ConditionOperatorNotIn, []string{"pro"}),
+			},
+			want: false,
+		},
+		{
+			name: "missing value not equals",
+			conditions: []vo.Condition{
+				vo.NewCondition("#request.body.missing", enum.ConditionOperatorNotEquals, []string{"pro"}),
+			},
+			want: true,
+		},
+		{
+			name:       "truthy",
+			conditions: []vo.Condition{vo.NewCondition("#request.body.active", enum.ConditionOperatorTruthy, nil)},
+			want:       true,
+		},
+		{
+			name:       "falsy",
+			conditions: []vo.Condition{vo.NewCondition("#request.body.count", enum.ConditionOperatorFalsy, nil)},
+			want:       true,
+		},
+		{
+			name: "every condition must match",
+			conditions: []vo.Condition{
+				vo.NewCondition("#request.body.active", enum.ConditionOperatorTruthy, nil),
+				vo.NewCondition("#request.body.count", enum.ConditionOperatorTruthy, nil),
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewCondition(NewDynamicValue(jsonpath.New()))
+			request := vo.NewHTTPRequest(vo.NewURLPath("/users", nil), "/users", "POST",
+				vo.NewHeader(map[string][]string{"X-Role": {"admin"}}), vo.NewEmptyQuery(),
+				vo.NewBodyJson(bytes.NewBufferString(`{"plan":"pro","active":true,"count":0}`)))
+
+			got, errs := service.Evaluate(tt.conditions, request, vo.NewEmptyHistory())
+			if len(errs) > 0 {
+				t.Fatalf("Evaluate() errs = %v", errs)
+			} else if got != tt.want {
+				t.Errorf("Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
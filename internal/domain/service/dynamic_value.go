@@ -24,6 +24,7 @@ import (
 	"github.com/tech4works/gopen-gateway/internal/domain/mapper"
 	"github.com/tech4works/gopen-gateway/internal/domain/model/vo"
 	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -34,6 +35,8 @@ type dynamicValueService struct {
 type DynamicValue interface {
 	Get(value string, request *vo.HTTPRequest, history *vo.History) (string, []error)
 	GetAsSliceOfString(value string, request *vo.HTTPRequest, history *vo.History) ([]string, []error)
+	GetJSONValue(syntax string, request *vo.HTTPRequest, history *vo.History) (domain.JSONValue, error)
+	FindResponseIndexes(values []string) ([]int, bool)
 }
 
 func NewDynamicValue(jsonPath domain.JSONPath) DynamicValue {
@@ -71,16 +74,53 @@ func (d dynamicValueService) GetAsSliceOfString(value string, request *vo.HTTPRe
 	return []string{newValue}, errs
 }
 
+func (d dynamicValueService) GetJSONValue(syntax string, request *vo.HTTPRequest, history *vo.History) (
+	domain.JSONValue, error) {
+	return d.getJSONValueBySyntax(syntax, request, history)
+}
+
+func (d dynamicValueService) FindResponseIndexes(values []string) ([]int, bool) {
+	var indexes []int
+	for _, value := range values {
+		for _, word := range d.findAllBySyntax(value) {
+			dotSplit := strings.Split(strings.ReplaceAll(word, "#", ""), ".")
+			if !checker.Contains(dotSplit[0], "responses") {
+				continue
+			}
+
+			// without a numeric index, like #responses.#.body, the value depends on the whole history
+			if checker.IsGreaterThan(2, len(dotSplit)) {
+				return nil, true
+			}
+			index, err := strconv.Atoi(dotSplit[1])
+			if checker.NonNil(err) {
+				return nil, true
+			}
+			indexes = append(indexes, index)
+		}
+	}
+	return indexes, false
+}
+
 func (d dynamicValueService) findAllBySyntax(value string) []string {
 	regex := regexp.MustCompile(`\B#[a-zA-Z0-9_.\-\[\]]+`)
 	return regex.FindAllString(value, -1)
 }
 
 func (d dynamicValueService) getValueBySyntax(word string, request *vo.HTTPRequest, history *vo.History) (string, error) {
+	result, err := d.getJSONValueBySyntax(word, request, history)
+	if checker.NonNil(err) {
+		return "", err
+	}
+	return result.String(), nil
+}
+
+func (d dynamicValueService) getJSONValueBySyntax(word string, request *vo.HTTPRequest, history *vo.History) (
+	domain.JSONValue, error) {
 	cleanSintaxe := strings.ReplaceAll(word, "#", "")
 	dotSplit := strings.Split(cleanSintaxe, ".")
 	if checker.IsEmpty(dotSplit) {
-		return "", errors.Newf("Invalid dynamic value syntax! key: %s", word)
+		return nil, errors.Newf("Invalid dynamic value syntax! key: %s", word)
 	}
 
 	prefix := dotSplit[0]
@@ -88,39 +128,103 @@ func (d dynamicValueService) getValueBySyntax(word string, request *vo.HTTPReque
 		return d.getRequestValueByJsonPath(cleanSintaxe, request)
 	} else if checker.Contains(prefix, "responses") {
 		return d.getResponseValueByJsonPath(cleanSintaxe, history)
+	} else if checker.Equals(prefix, "item") {
+		return d.getItemValueByJsonPath(cleanSintaxe, history)
+	} else if checker.Equals(prefix, "jwt") {
+		return d.getJWTValueByJsonPath(cleanSintaxe, request)
+	} else if checker.Equals(prefix, "consumer") {
+		return d.getConsumerValueByJsonPath(cleanSintaxe, request)
 	} else {
-		return "", errors.Newf("Invalid prefix syntax %s!", prefix)
+		return nil, errors.Newf("Invalid prefix syntax %s!", prefix)
 	}
 }
 
-func (d dynamicValueService) getRequestValueByJsonPath(jsonPath string, request *vo.HTTPRequest) (string, error) {
+func (d dynamicValueService) getRequestValueByJsonPath(jsonPath string, request *vo.HTTPRequest) (domain.JSONValue,
+	error) {
 	jsonPath = strings.Replace(jsonPath, "request.", "", 1)
 
 	jsonRequest, err := request.Map()
 	if checker.NonNil(err) {
-		return "", err
+		return nil, err
 	}
 
 	result := d.jsonPath.Get(jsonRequest, jsonPath)
 	if result.Exists() {
-		return result.String(), nil
+		return result, nil
 	}
 
-	return "", mapper.NewErrValueNotFound(jsonPath)
+	return nil, mapper.NewErrValueNotFound(jsonPath)
 }
 
-func (d dynamicValueService) getResponseValueByJsonPath(jsonPath string, history *vo.History) (string, error) {
+func (d dynamicValueService) getResponseValueByJsonPath(jsonPath string, history *vo.History) (domain.JSONValue,
+	error) {
 	jsonPath = strings.Replace(jsonPath, "responses.", "", 1)
 
 	jsonResponse, err := history.Map()
 	if checker.NonNil(err) {
-		return "", err
+		return nil, err
 	}
 
 	result := d.jsonPath.Get(jsonResponse, jsonPath)
 	if result.Exists() {
-		return result.String(), nil
+		return result, nil
+	}
+
+	return nil, mapper.NewErrValueNotFound(jsonPath)
+}
+
+func (d dynamicValueService) getItemValueByJsonPath(jsonPath string, history *vo.History) (domain.JSONValue, error) {
+	if !history.HasItem() {
+		return nil, mapper.NewErrValueNotFound(jsonPath)
+	}
+
+	// #item alone is the whole item, #item.<path> is a value inside it
+	var result domain.JSONValue
+	if checker.Equals(jsonPath, "item") {
+		result = d.jsonPath.Parse(history.Item())
+	} else {
+		result = d.jsonPath.Get(history.Item(), strings.Replace(jsonPath, "item.", "", 1))
+	}
+	if result.Exists() {
+		return result, nil
+	}
+
+	return nil, mapper.NewErrValueNotFound(jsonPath)
+}
+
+func (d dynamicValueService) getJWTValueByJsonPath(jsonPath string, request *vo.HTTPRequest) (domain.JSONValue, error) {
+	if !request.HasJWTClaims() {
+		return nil, mapper.NewErrValueNotFound(jsonPath)
+	}
+
+	var result domain.JSONValue
+	if checker.Equals(jsonPath, "jwt.claims") {
+		result = d.jsonPath.Parse(request.JWTClaims())
+	} else {
+		result = d.jsonPath.Get(request.JWTClaims(), strings.Replace(jsonPath, "jwt.claims.", "", 1))
+	}
+	if result.Exists() {
+		return result, nil
+	}
+
+	return nil, mapper.NewErrValueNotFound(jsonPath)
+}
+
+func (d dynamicValueService) getConsumerValueByJsonPath(jsonPath string, request *vo.HTTPRequest) (domain.JSONValue,
+	error) {
+	if !request.HasConsumer() {
+		return nil, mapper.NewErrValueNotFound(jsonPath)
+	}
+
+	var result domain.JSONValue
+	if checker.Equals(jsonPath, "consumer") {
+		result = d.jsonPath.Parse(request.Consumer().Map())
+	} else {
+		result = d.jsonPath.Get(request.Consumer().Map(), strings.Replace(jsonPath, "consumer.", "", 1))
+	}
+	if result.Exists() {
+		return result, nil
 	}
 
-	return "", mapper.NewErrValueNotFound(jsonPath)
+	return nil, mapper.NewErrValueNotFound(jsonPath)
 }
@@ -0,0 +1,51 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"github.com/tech4works/gopen-gateway/internal/domain/model/vo"
+	"github.com/tech4works/gopen-gateway/internal/infra/jsonpath"
+	"testing"
+)
+
+func TestDynamicValueGetItem(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		history *vo.History
+		want    string
+	}{
+		{"whole item", "/users/#item", vo.NewEmptyHistory().WithItem("7"), "/users/7"},
+		{"item field", "/users/#item.id", vo.NewEmptyHistory().WithItem(`{"id":7}`), "/users/7"},
+		{"missing item field", "/users/#item.name", vo.NewEmptyHistory().WithItem(`{"id":7}`), "/users/#item.name"},
+		{"without item", "/users/#item", vo.NewEmptyHistory(), "/users/#item"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewDynamicValue(jsonpath.New())
+			request := vo.NewHTTPRequest(vo.NewURLPath("/users", nil), "/users", "GET", vo.NewHeader(nil),
+				vo.NewEmptyQuery(), nil)
+
+			got, errs := service.Get(tt.value, request, tt.history)
+			if len(errs) > 0 {
+				t.Fatalf("Get() errs = %v", errs)
+			} else if got != tt.want {
+				t.Errorf("Get() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
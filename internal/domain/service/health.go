@@ -0,0 +1,164 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"github.com/tech4works/checker"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/vo"
+	"slices"
+	"sort"
+	"sync"
+)
+
+type healthService struct {
+	mutex  *sync.RWMutex
+	hosts  map[string]vo.HostHealth
+	counts map[string]int
+}
+
+type Health interface {
+	MarkUp(host string, threshold int) bool
+	MarkDown(host string, message string, threshold int) bool
+	IsDown(host string) bool
+	AllDown(hosts []string) bool
+	AvailableHosts(hosts []string) []string
+	Report() []vo.HostHealth
+	Retain(hosts []string)
+}
+
+func NewHealth() Health {
+	return &healthService{
+		mutex:  &sync.RWMutex{},
+		hosts:  map[string]vo.HostHealth{},
+		counts: map[string]int{},
+	}
+}
+
+func (h *healthService) MarkUp(host string, threshold int) bool {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	previous, exists := h.hosts[host]
+	if !exists || previous.IsUp() {
+		delete(h.counts, host)
+		h.hosts[host] = vo.NewHostHealthUp(host)
+		return false
+	}
+
+	// a down host only comes back after consecutive successes, so a single lucky check doesn't make it flap
+	h.counts[host]++
+	if checker.IsGreaterThan(threshold, h.counts[host]) {
+		return false
+	}
+	delete(h.counts, host)
+	h.hosts[host] = vo.NewHostHealthUp(host)
+
+	return true
+}
+
+func (h *healthService) MarkDown(host string, message string, threshold int) bool {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	previous, exists := h.hosts[host]
+	if exists && previous.IsDown() {
+		delete(h.counts, host)
+		h.hosts[host] = vo.NewHostHealthDown(host, previous.Failures()+1, message)
+		return false
+	}
+
+	// the same for an available host, it is only removed from the balancer after consecutive failures
+	h.counts[host]++
+	if checker.IsGreaterThan(threshold, h.counts[host]) {
+		return false
+	}
+	h.hosts[host] = vo.NewHostHealthDown(host, h.counts[host], message)
+	delete(h.counts, host)
+
+	return true
+}
+
+func (h *healthService) IsDown(host string) bool {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	return h.isDown(host)
+}
+
+func (h *healthService) AllDown(hosts []string) bool {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	for _, host := range hosts {
+		if !h.isDown(host) {
+			return false
+		}
+	}
+	return checker.IsNotEmpty(hosts)
+}
+
+func (h *healthService) AvailableHosts(hosts []string) []string {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	var result []string
+	for _, host := range hosts {
+		if !h.isDown(host) {
+			result = append(result, host)
+		}
+	}
+	return result
+}
+
+func (h *healthService) Report() []vo.HostHealth {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	var result []vo.HostHealth
+	for _, hostHealth := range h.hosts {
+		result = append(result, hostHealth)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Host() < result[j].Host()
+	})
+
+	return result
+}
+
+func (h *healthService) Retain(hosts []string) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	// the service is kept across reloads, a host that is no longer checked must not stay out of the balancer
+	for host := range h.hosts {
+		if !slices.Contains(hosts, host) {
+			delete(h.hosts, host)
+			delete(h.counts, host)
+		}
+	}
+	for host := range h.counts {
+		if !slices.Contains(hosts, host) {
+			delete(h.counts, host)
+		}
+	}
+}
+
+func (h *healthService) isDown(host string) bool {
+	// hosts never checked are considered available
+	hostHealth, exists := h.hosts[host]
+	return exists && hostHealth.IsDown()
+}
@@ -0,0 +1,152 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestHealthMark(t *testing.T) {
+	tests := []struct {
+		name        string
+		results     []bool
+		wantChanged []bool
+		wantDown    bool
+	}{
+		{"first check up", []bool{true}, []bool{false}, false},
+		{"failures below the unhealthy threshold", []bool{false, false}, []bool{false, false}, false},
+		{"consecutive failures", []bool{false, false, false}, []bool{false, false, true}, true},
+		{
+			name:        "failures interrupted by a success",
+			results:     []bool{false, false, true, false, false},
+			wantChanged: []bool{false, false, false, false, false},
+		},
+		{"down again", []bool{false, false, false, false}, []bool{false, false, true, false}, true},
+		{
+			name:        "success below the healthy threshold",
+			results:     []bool{false, false, false, true},
+			wantChanged: []bool{false, false, true, false},
+			wantDown:    true,
+		},
+		{
+			name:        "consecutive successes",
+			results:     []bool{false, false, false, true, true},
+			wantChanged: []bool{false, false, true, false, true},
+		},
+		{
+			name:        "successes interrupted by a failure",
+			results:     []bool{false, false, false, true, false, true},
+			wantChanged: []bool{false, false, true, false, false, false},
+			wantDown:    true,
+		},
+		{"still up", []bool{true, true}, []bool{false, false}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewHealth()
+
+			var changed []bool
+			for _, up := range tt.results {
+				if up {
+					changed = append(changed, service.MarkUp("a", 2))
+				} else {
+					changed = append(changed, service.MarkDown("a", "connection refused", 3))
+				}
+			}
+			if !slices.Equal(changed, tt.wantChanged) {
+				t.Errorf("changed = %v, want %v", changed, tt.wantChanged)
+			}
+			if got := service.IsDown("a"); got != tt.wantDown {
+				t.Errorf("IsDown() = %v, want %v", got, tt.wantDown)
+			}
+		})
+	}
+}
+
+func TestHealthAvailableHosts(t *testing.T) {
+	tests := []struct {
+		name        string
+		down        []string
+		up          []string
+		want        []string
+		wantAllDown bool
+	}{
+		{"never checked", nil, nil, []string{"a", "b"}, false},
+		{"one down", []string{"a"}, nil, []string{"b"}, false},
+		{"all down", []string{"a", "b"}, nil, nil, true},
+		{"down and up again", []string{"a", "b"}, []string{"a"}, []string{"a"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewHealth()
+			for _, host := range tt.down {
+				service.MarkDown(host, "connection refused", 1)
+			}
+			for _, host := range tt.up {
+				service.MarkUp(host, 1)
+			}
+
+			hosts := []string{"a", "b"}
+			if got := service.AvailableHosts(hosts); !slices.Equal(got, tt.want) {
+				t.Errorf("AvailableHosts() = %v, want %v", got, tt.want)
+			}
+			if got := service.AllDown(hosts); got != tt.wantAllDown {
+				t.Errorf("AllDown() = %v, want %v", got, tt.wantAllDown)
+			}
+		})
+	}
+}
+
+func TestHealthRetain(t *testing.T) {
+	tests := []struct {
+		name       string
+		retain     []string
+		wantReport []string
+		wantDown   []string
+	}{
+		{"every host kept", []string{"a", "b"}, []string{"a", "b"}, []string{"a"}},
+		{"down host no longer checked", []string{"b"}, []string{"b"}, nil},
+		{"without checks", nil, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewHealth()
+			service.MarkDown("a", "connection refused", 1)
+			service.MarkUp("b", 1)
+
+			service.Retain(tt.retain)
+
+			var report []string
+			for _, hostHealth := range service.Report() {
+				report = append(report, hostHealth.Host())
+			}
+			if !slices.Equal(report, tt.wantReport) {
+				t.Errorf("Report() hosts = %v, want %v", report, tt.wantReport)
+			}
+			var down []string
+			for _, host := range []string{"a", "b"} {
+				if service.IsDown(host) {
+					down = append(down, host)
+				}
+			}
+			if !slices.Equal(down, tt.wantDown) {
+				t.Errorf("down hosts = %v, want %v", down, tt.wantDown)
+			}
+		})
+	}
+}
@@ -17,53 +17,139 @@
 package service
 
 import (
+	"context"
+	"fmt"
 	"github.com/tech4works/checker"
+	"github.com/tech4works/gopen-gateway/internal/domain"
 	"github.com/tech4works/gopen-gateway/internal/domain/mapper"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/enum"
 	"github.com/tech4works/gopen-gateway/internal/domain/model/vo"
-	timerate "golang.org/x/time/rate"
-	"io"
-	"net/http"
+	"math"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 type limiterService struct {
-	keys  map[string]*timerate.Limiter
-	mutex *sync.RWMutex
+	store      domain.LimiterStore
+	localStore domain.LocalLimiterStore
+	jsonPath   domain.JSONPath
+	mutex      *sync.Mutex
+	semaphores map[string]*concurrentSemaphore
+}
+
+type concurrentSemaphore struct {
+	slots   chan struct{}
+	waiting *atomic.Int64
 }
 
 type Limiter interface {
-	AllowRate(request *vo.HTTPRequest, rate vo.Rate) error
+	AllowRate(ctx context.Context, endpoint *vo.Endpoint, request *vo.HTTPRequest) (*vo.RateState, error)
+	AllowRateLocal(endpoint *vo.Endpoint, request *vo.HTTPRequest) (*vo.RateState, error)
+	BuildRateHeader(state *vo.RateState) vo.Header
+	AcquireConcurrent(ctx context.Context, gopen *vo.Gopen, endpoint *vo.Endpoint) (func(), error)
+	Stats() vo.LimiterStats
+	Reset(ctx context.Context) (int, error)
 	AllowSize(request *vo.HTTPRequest, limiter vo.Limiter) error
 }
 
-func NewLimiter() Limiter {
+func NewLimiter(store domain.LimiterStore, localStore domain.LocalLimiterStore, jsonPath domain.JSONPath) Limiter {
 	return &limiterService{
-		keys:  map[string]*timerate.Limiter{},
-		mutex: &sync.RWMutex{},
+		store:      store,
+		localStore: localStore,
+		jsonPath:   jsonPath,
+		mutex:      &sync.Mutex{},
+		semaphores: map[string]*concurrentSemaphore{},
 	}
 }
 
-func (s *limiterService) AllowRate(request *vo.HTTPRequest, rate vo.Rate) (err error) {
+func (s *limiterService) AllowRate(ctx context.Context, endpoint *vo.Endpoint, request *vo.HTTPRequest) (
+	*vo.RateState, error) {
+	rate := endpoint.Limiter().Rate()
 	if rate.IsEmpty() {
-		return nil
+		return nil, nil
+	} else if checker.IsNil(s.store) {
+		return s.AllowRateLocal(endpoint, request)
 	}
 
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
+	state, err := s.store.Allow(ctx, s.buildRateKey(endpoint, request), rate)
+	if checker.NonNil(err) {
+		return nil, mapper.NewErrLimiterStore(err)
+	} else if !state.Allowed() {
+		return state, mapper.NewErrTooManyRequests(rate.Capacity(), rate.EveryTime())
+	}
+	return state, nil
+}
+
+func (s *limiterService) AllowRateLocal(endpoint *vo.Endpoint, request *vo.HTTPRequest) (*vo.RateState, error) {
+	rate := endpoint.Limiter().Rate()
+	if rate.IsEmpty() {
+		return nil, nil
+	}
+
+	// the local store never fails, it is the fallback when the distributed one is unavailable
+	state, _ := s.localStore.Allow(context.Background(), s.buildRateKey(endpoint, request), rate)
+	if !state.Allowed() {
+		return state, mapper.NewErrTooManyRequests(rate.Capacity(), rate.EveryTime())
+	}
+	return state, nil
+}
 
-	clientIP := request.Header().GetFirst(mapper.XForwardedFor)
+func (s *limiterService) BuildRateHeader(state *vo.RateState) vo.Header {
+	// the reset and retry-after values are delta-seconds, rounded up so that the client never retries too early
+	values := map[string][]string{
+		mapper.XRateLimitLimit:     {strconv.Itoa(state.Limit())},
+		mapper.XRateLimitRemaining: {strconv.Itoa(state.Remaining())},
+		mapper.XRateLimitReset:     {s.formatSeconds(state.Reset())},
+	}
+	if !state.Allowed() {
+		values[mapper.RetryAfter] = []string{s.formatSeconds(state.RetryAfter())}
+	}
+	return vo.NewHeader(values)
+}
 
-	rateLimiter, exists := s.keys[clientIP]
-	if !exists {
-		rateLimiter = timerate.NewLimiter(timerate.Every(rate.EveryTime()), rate.Capacity())
-		s.keys[clientIP] = rateLimiter
+func (s *limiterService) AcquireConcurrent(ctx context.Context, gopen *vo.Gopen, endpoint *vo.Endpoint) (func(),
+	error) {
+	// the endpoint slot is taken first, so a request waiting for its endpoint doesn't hold a slot of the whole gateway
+	releaseEndpoint := func() {}
+	if endpoint.Limiter().HasMaxConcurrent() {
+		release, err := s.acquireConcurrent(ctx, fmt.Sprint(endpoint.Method(), " ", endpoint.Path()),
+			endpoint.Limiter().MaxConcurrent())
+		if checker.NonNil(err) {
+			return nil, err
+		}
+		releaseEndpoint = release
 	}
 
-	if !rateLimiter.Allow() {
-		err = mapper.NewErrTooManyRequests(rate.Capacity(), rate.EveryTime())
+	if gopen.HasMaxConcurrent() {
+		releaseGopen, err := s.acquireConcurrent(ctx, "*", gopen.MaxConcurrent())
+		if checker.NonNil(err) {
+			releaseEndpoint()
+			return nil, err
+		}
+		return func() {
+			releaseGopen()
+			releaseEndpoint()
+		}, nil
+	}
+
+	return releaseEndpoint, nil
+}
+
+func (s *limiterService) Stats() vo.LimiterStats {
+	return s.localStore.Stats()
+}
+
+func (s *limiterService) Reset(ctx context.Context) (int, error) {
+	count, err := s.localStore.Reset(ctx)
+	if checker.NonNil(err) || checker.IsNil(s.store) {
+		return count, err
 	}
 
-	return err
+	storeCount, err := s.store.Reset(ctx)
+	return count + storeCount, err
 }
 
 func (s *limiterService) AllowSize(request *vo.HTTPRequest, limiter vo.Limiter) error {
@@ -72,22 +158,118 @@ func (s *limiterService) AllowSize(request *vo.HTTPRequest, limiter vo.Limiter)
 		return mapper.NewErrHeaderTooLarge(maxHeaderSize.String())
 	}
 
-	maxBodySize := limiter.MaxBodySize()
-	if checker.ContainsIgnoreCase(request.Header().Get(mapper.ContentType), "multipart/form-data") {
-		maxBodySize = limiter.MaxMultipartMemorySize()
+	// the declared length is trusted first, so a request over the limit is rejected without its body being read
+	maxBodySize := limiter.MaxBodySizeBy(request.Header().Get(mapper.ContentType))
+	contentLength, err := strconv.ParseInt(request.Header().Get(mapper.ContentLength), 10, 64)
+	if checker.IsNil(err) && checker.IsGreaterThan(contentLength, int64(maxBodySize)) {
+		return mapper.NewErrPayloadTooLarge(maxBodySize.String())
+	} else if !request.HasBody() {
+		return nil
 	}
 
-	if !request.HasBody() {
-		return nil
+	// bodies without a declared length are read up to one byte over the limit, so exceeding it is enough
+	if checker.IsGreaterThan(int64(request.Body().Size()), int64(maxBodySize)) {
+		return mapper.NewErrPayloadTooLarge(maxBodySize.String())
+	}
+	return nil
+}
+
+func (s *limiterService) buildRateKey(endpoint *vo.Endpoint, request *vo.HTTPRequest) string {
+	rate := endpoint.Limiter().Rate()
+
+	var values []string
+	for _, strategy := range rate.Key().Strategies() {
+		value := s.findRateKeyValue(strategy, rate.Key(), request)
+		if checker.IsNotEmpty(value) {
+			values = append(values, fmt.Sprint(strategy, "=", value))
+		}
+	}
+	// a request without any of the configured values is limited by its ip, so it doesn't share the bucket with others
+	if checker.IsEmpty(values) {
+		values = append(values, fmt.Sprint(enum.RateKeyStrategyClientIP, "=", request.ClientIP()))
 	}
 
-	bodyBuffer := request.Body().Buffer()
-	readCloser := http.MaxBytesReader(nil, io.NopCloser(bodyBuffer), int64(maxBodySize))
+	key := strings.Join(values, "|")
+	if checker.Equals(rate.Scope(), enum.RateScopeEndpoint) {
+		key = fmt.Sprint(endpoint.Method(), " ", endpoint.Path(), "|", key)
+	} else {
+		// the global bucket is only shared by endpoints with the same rate, the token bucket state depends on it
+		key = fmt.Sprint(rate.Capacity(), "/", rate.Every(), "|", key)
+	}
+	return key
+}
 
-	_, err := io.ReadAll(readCloser)
-	if checker.NonNil(err) {
-		return mapper.NewErrPayloadTooLarge(maxBodySize.String())
+func (s *limiterService) findRateKeyValue(strategy enum.RateKeyStrategy, rateKey vo.RateKey,
+	request *vo.HTTPRequest) string {
+	switch strategy {
+	case enum.RateKeyStrategyHeader:
+		return request.Header().Get(rateKey.Header())
+	case enum.RateKeyStrategyConsumer:
+		if request.HasConsumer() {
+			return request.Consumer().Name()
+		}
+		return ""
+	case enum.RateKeyStrategyJWTClaim:
+		if request.HasJWTClaims() {
+			return s.jsonPath.Get(request.JWTClaims(), rateKey.Claim()).String()
+		}
+		return ""
+	default:
+		return request.ClientIP()
 	}
+}
 
-	return nil
+func (s *limiterService) formatSeconds(duration time.Duration) string {
+	return strconv.FormatInt(int64(math.Ceil(duration.Seconds())), 10)
+}
+
+func (s *limiterService) acquireConcurrent(ctx context.Context, key string, maxConcurrent *vo.MaxConcurrent) (
+	func(), error) {
+	semaphore := s.findSemaphore(key, maxConcurrent)
+	release := func() {
+		<-semaphore.slots
+	}
+
+	select {
+	case semaphore.slots <- struct{}{}:
+		return release, nil
+	default:
+	}
+
+	// without a free slot the request waits in the queue, when the queue is full it is shed right away
+	if checker.IsGreaterThan(semaphore.waiting.Add(1), int64(maxConcurrent.QueueSize())) {
+		semaphore.waiting.Add(-1)
+		return nil, mapper.NewErrMaxConcurrent(maxConcurrent.Limit())
+	}
+	defer semaphore.waiting.Add(-1)
+
+	timer := time.NewTimer(maxConcurrent.QueueTimeout())
+	defer timer.Stop()
+
+	select {
+	case semaphore.slots <- struct{}{}:
+		return release, nil
+	case <-timer.C:
+		return nil, mapper.NewErrMaxConcurrent(maxConcurrent.Limit())
+	case <-ctx.Done():
+		return nil, mapper.NewErrMaxConcurrent(maxConcurrent.Limit())
+	}
+}
+
+func (s *limiterService) findSemaphore(key string, maxConcurrent *vo.MaxConcurrent) *concurrentSemaphore {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// the service is kept across reloads, so a reloaded limit gets its own semaphore instead of the old capacity
+	key = fmt.Sprint(key, " ", maxConcurrent.Limit())
+
+	semaphore, ok := s.semaphores[key]
+	if !ok {
+		semaphore = &concurrentSemaphore{
+			slots:   make(chan struct{}, maxConcurrent.Limit()),
+			waiting: &atomic.Int64{},
+		}
+		s.semaphores[key] = semaphore
+	}
+	return semaphore
 }
@@ -0,0 +1,371 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"github.com/tech4works/errors"
+	"github.com/tech4works/gopen-gateway/internal/domain/mapper"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/enum"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/vo"
+	"github.com/tech4works/gopen-gateway/internal/infra/jsonpath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLimiterBuildRateKey(t *testing.T) {
+	consumer := vo.NewConsumer("mobile", nil, nil, nil)
+
+	tests := []struct {
+		name    string
+		rate    vo.Rate
+		header  map[string][]string
+		request func(request *vo.HTTPRequest) *vo.HTTPRequest
+		want    string
+	}{
+		{
+			name: "client ip by default",
+			rate: newTestRate(10, time.Second, enum.RateScopeGlobal, nil),
+			want: "10/1s|CLIENT_IP=10.0.0.1",
+		},
+		{
+			name: "global scope by default",
+			rate: newTestRate(20, time.Minute, "", nil),
+			want: "20/1m0s|CLIENT_IP=10.0.0.1",
+		},
+		{
+			name: "endpoint scope",
+			rate: newTestRate(10, time.Second, enum.RateScopeEndpoint, nil),
+			want: "GET /users|CLIENT_IP=10.0.0.1",
+		},
+		{
+			name:   "header",
+			rate:   newTestRate(10, time.Second, enum.RateScopeEndpoint, []enum.RateKeyStrategy{enum.RateKeyStrategyHeader}),
+			header: map[string][]string{"X-Tenant": {"acme"}},
+			want:   "GET /users|HEADER=acme",
+		},
+		{
+			name: "missing header",
+			rate: newTestRate(10, time.Second, enum.RateScopeEndpoint, []enum.RateKeyStrategy{enum.RateKeyStrategyHeader}),
+			want: "GET /users|CLIENT_IP=10.0.0.1",
+		},
+		{
+			name: "consumer",
+			rate: newTestRate(10, time.Second, enum.RateScopeEndpoint,
+				[]enum.RateKeyStrategy{enum.RateKeyStrategyConsumer}),
+			request: func(request *vo.HTTPRequest) *vo.HTTPRequest {
+				return request.WithConsumer(&consumer)
+			},
+			want: "GET /users|CONSUMER=mobile",
+		},
+		{
+			name: "jwt claim",
+			rate: newTestRate(10, time.Second, enum.RateScopeEndpoint,
+				[]enum.RateKeyStrategy{enum.RateKeyStrategyJWTClaim}),
+			request: func(request *vo.HTTPRequest) *vo.HTTPRequest {
+				return request.WithJWTClaims(`{"sub":"user-1"}`)
+			},
+			want: "GET /users|JWT_CLAIM=user-1",
+		},
+		{
+			name: "many strategies",
+			rate: newTestRate(10, time.Second, enum.RateScopeEndpoint,
+				[]enum.RateKeyStrategy{enum.RateKeyStrategyHeader, enum.RateKeyStrategyClientIP}),
+			header: map[string][]string{"X-Tenant": {"acme"}},
+			want:   "GET /users|HEADER=acme|CLIENT_IP=10.0.0.1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewLimiter(nil, nil, jsonpath.New()).(*limiterService)
+			endpoint := newTestLimiterEndpoint(vo.NewLimiter(0, 0, 0, tt.rate, nil))
+
+			request := newTestLimiterRequest(tt.header, nil)
+			if tt.request != nil {
+				request = tt.request(request)
+			}
+			if got := service.buildRateKey(&endpoint, request); got != tt.want {
+				t.Errorf("buildRateKey() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLimiterBuildRateHeader(t *testing.T) {
+	rate := newTestRate(10, time.Second, enum.RateScopeGlobal, nil)
+
+	tests := []struct {
+		name  string
+		state *vo.RateState
+		want  map[string][]string
+	}{
+		{
+			name:  "full bucket",
+			state: vo.NewRateState(rate, true, 9),
+			want: map[string][]string{
+				"X-RateLimit-Limit":     {"10"},
+				"X-RateLimit-Remaining": {"9"},
+				"X-RateLimit-Reset":     {"1"},
+			},
+		},
+		{
+			name:  "partial tokens",
+			state: vo.NewRateState(rate, true, 4.5),
+			want: map[string][]string{
+				"X-RateLimit-Limit":     {"10"},
+				"X-RateLimit-Remaining": {"4"},
+				"X-RateLimit-Reset":     {"6"},
+			},
+		},
+		{
+			name:  "denied",
+			state: vo.NewRateState(rate, false, 0.25),
+			want: map[string][]string{
+				"X-RateLimit-Limit":     {"10"},
+				"X-RateLimit-Remaining": {"0"},
+				"X-RateLimit-Reset":     {"10"},
+				"Retry-After":           {"1"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewLimiter(nil, nil, jsonpath.New())
+			if got := service.BuildRateHeader(tt.state); !reflect.DeepEqual(got.Copy(), tt.want) {
+				t.Errorf("BuildRateHeader() = %v, want %v", got.Copy(), tt.want)
+			}
+		})
+	}
+}
+
+func TestLimiterAllowSize(t *testing.T) {
+	limiter := vo.NewLimiter(vo.NewBytesByInt(256), vo.NewBytesByInt(10), vo.NewBytesByInt(20), vo.Rate{}, nil)
+
+	tests := []struct {
+		name   string
+		header map[string][]string
+		body   string
+		want   error
+	}{
+		{
+			name: "without body",
+		},
+		{
+			name: "body within the limit",
+			body: "0123456789",
+		},
+		{
+			name: "body over the limit",
+			body: "0123456789a",
+			want: mapper.ErrPayloadTooLarge,
+		},
+		{
+			name:   "declared length over the limit",
+			header: map[string][]string{mapper.ContentLength: {"11"}},
+			want:   mapper.ErrPayloadTooLarge,
+		},
+		{
+			name:   "declared length within the limit",
+			header: map[string][]string{mapper.ContentLength: {"10"}},
+			body:   "0123456789",
+		},
+		{
+			name:   "invalid declared length",
+			header: map[string][]string{mapper.ContentLength: {"invalid"}},
+			body:   "0123456789",
+		},
+		{
+			name: "multipart within its limit",
+			header: map[string][]string{
+				mapper.ContentType:   {"multipart/form-data; boundary=x"},
+				mapper.ContentLength: {"20"},
+			},
+		},
+		{
+			name: "multipart over its limit",
+			header: map[string][]string{
+				mapper.ContentType:   {"multipart/form-data; boundary=x"},
+				mapper.ContentLength: {"21"},
+			},
+			want: mapper.ErrPayloadTooLarge,
+		},
+		{
+			name:   "header over the limit",
+			header: map[string][]string{"X-Large": {string(bytes.Repeat([]byte("a"), 256))}},
+			want:   mapper.ErrHeaderTooLarge,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewLimiter(nil, nil, jsonpath.New())
+
+			var body *vo.Body
+			if tt.body != "" {
+				body = vo.NewBodyJson(bytes.NewBufferString(tt.body))
+			}
+			err := service.AllowSize(newTestLimiterRequest(tt.header, body), limiter)
+			if tt.want == nil && err != nil {
+				t.Errorf("AllowSize() err = %v, want nil", err)
+			} else if tt.want != nil && !errors.Contains(err, tt.want) {
+				t.Errorf("AllowSize() err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLimiterAcquireConcurrent(t *testing.T) {
+	tests := []struct {
+		name          string
+		gopenMax      *vo.MaxConcurrent
+		endpointMax   *vo.MaxConcurrent
+		otherEndpoint bool
+		releaseAfter  time.Duration
+		cancelAfter   time.Duration
+		wantErr       bool
+	}{
+		{
+			name: "without limits",
+		},
+		{
+			name:        "shed without queue",
+			endpointMax: vo.NewMaxConcurrent(1, 0, 0),
+			wantErr:     true,
+		},
+		{
+			name:          "another endpoint",
+			endpointMax:   vo.NewMaxConcurrent(1, 0, 0),
+			otherEndpoint: true,
+		},
+		{
+			name:        "queue timeout",
+			endpointMax: vo.NewMaxConcurrent(1, 1, vo.NewDuration(50*time.Millisecond)),
+			wantErr:     true,
+		},
+		{
+			name:         "slot released while waiting",
+			endpointMax:  vo.NewMaxConcurrent(1, 1, vo.NewDuration(time.Second)),
+			releaseAfter: 20 * time.Millisecond,
+		},
+		{
+			name:        "context canceled while waiting",
+			endpointMax: vo.NewMaxConcurrent(1, 1, vo.NewDuration(time.Second)),
+			cancelAfter: 20 * time.Millisecond,
+			wantErr:     true,
+		},
+		{
+			name:          "gateway limit shared by endpoints",
+			gopenMax:      vo.NewMaxConcurrent(1, 0, 0),
+			otherEndpoint: true,
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewLimiter(nil, nil, jsonpath.New())
+			gopen := vo.NewGopen(nil, tt.gopenMax, nil, nil, nil)
+			endpoint := newTestConcurrentEndpoint("/users", tt.endpointMax)
+			otherEndpoint := endpoint
+			if tt.otherEndpoint {
+				otherEndpoint = newTestConcurrentEndpoint("/orders", tt.endpointMax)
+			}
+
+			release, err := service.AcquireConcurrent(context.Background(), gopen, &endpoint)
+			if err != nil {
+				t.Fatalf("AcquireConcurrent() first err = %v", err)
+			}
+			if tt.releaseAfter > 0 {
+				time.AfterFunc(tt.releaseAfter, release)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.cancelAfter > 0 {
+				time.AfterFunc(tt.cancelAfter, cancel)
+			}
+
+			secondRelease, err := service.AcquireConcurrent(ctx, gopen, &otherEndpoint)
+			if tt.wantErr && !errors.Is(err, mapper.ErrMaxConcurrent) {
+				t.Fatalf("AcquireConcurrent() second err = %v, want %v", err, mapper.ErrMaxConcurrent)
+			} else if !tt.wantErr && err != nil {
+				t.Fatalf("AcquireConcurrent() second err = %v, want nil", err)
+			} else if !tt.wantErr {
+				secondRelease()
+			}
+		})
+	}
+}
+
+func TestLimiterAcquireConcurrentRelease(t *testing.T) {
+	service := NewLimiter(nil, nil, jsonpath.New())
+	gopen := vo.NewGopen(nil, vo.NewMaxConcurrent(1, 0, 0), nil, nil, nil)
+	endpoint := newTestConcurrentEndpoint("/users", vo.NewMaxConcurrent(1, 0, 0))
+
+	// the gateway and the endpoint slots are both released, so the next requests are accepted again
+	for i := 0; i < 3; i++ {
+		release, err := service.AcquireConcurrent(context.Background(), gopen, &endpoint)
+		if err != nil {
+			t.Fatalf("AcquireConcurrent() #%d err = %v", i, err)
+		}
+		release()
+	}
+}
+
+func TestLimiterAcquireConcurrentReloadedLimit(t *testing.T) {
+	service := NewLimiter(nil, nil, jsonpath.New())
+	gopen := vo.NewGopen(nil, nil, nil, nil, nil)
+	endpoint := newTestConcurrentEndpoint("/users", vo.NewMaxConcurrent(1, 0, 0))
+	reloadedEndpoint := newTestConcurrentEndpoint("/users", vo.NewMaxConcurrent(2, 0, 0))
+
+	release, err := service.AcquireConcurrent(context.Background(), gopen, &endpoint)
+	if err != nil {
+		t.Fatalf("AcquireConcurrent() err = %v", err)
+	}
+	defer release()
+
+	// the service is kept across reloads, the new limit must not inherit the capacity of the previous one
+	for i := 0; i < 2; i++ {
+		reloadedRelease, err := service.AcquireConcurrent(context.Background(), gopen, &reloadedEndpoint)
+		if err != nil {
+			t.Fatalf("AcquireConcurrent() reloaded #%d err = %v", i, err)
+		}
+		defer reloadedRelease()
+	}
+}
+
+func newTestRate(capacity int, every time.Duration, scope enum.RateScope, strategies []enum.RateKeyStrategy) vo.Rate {
+	return vo.NewRate(vo.NewDuration(every), capacity, scope, vo.NewRateKey(strategies, "X-Tenant", "sub"), false)
+}
+
+func newTestLimiterEndpoint(limiter vo.Limiter) vo.Endpoint {
+	return vo.NewEndpoint("/users", "GET", 0, limiter, nil, nil, nil, nil, nil, nil, nil)
+}
+
+func newTestLimiterRequest(header map[string][]string, body *vo.Body) *vo.HTTPRequest {
+	values := map[string][]string{mapper.XForwardedFor: {"10.0.0.1"}}
+	for key, value := range header {
+		values[key] = value
+	}
+	return vo.NewHTTPRequest(vo.NewURLPath("/users", nil), "/users", "GET", vo.NewHeader(values), vo.NewEmptyQuery(),
+		body)
+}
+
+func newTestConcurrentEndpoint(path string, maxConcurrent *vo.MaxConcurrent) vo.Endpoint {
+	return vo.NewEndpoint(path, "GET", 0, vo.NewLimiter(0, 0, 0, vo.Rate{}, maxConcurrent), nil, nil, nil, nil, nil,
+		nil, nil)
+}
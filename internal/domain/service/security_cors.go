@@ -21,6 +21,9 @@ import (
 	"github.com/tech4works/errors"
 	"github.com/tech4works/gopen-gateway/internal/domain/mapper"
 	"github.com/tech4works/gopen-gateway/internal/domain/model/vo"
+	"net/http"
+	"slices"
+	"strconv"
 	"strings"
 )
 
@@ -31,6 +34,9 @@ type SecurityCors interface {
 	ValidateOrigin(securityCors *vo.SecurityCors, request *vo.HTTPRequest) error
 	ValidateMethod(securityCors *vo.SecurityCors, request *vo.HTTPRequest) error
 	ValidateHeaders(securityCors *vo.SecurityCors, request *vo.HTTPRequest) error
+	ValidatePreflight(securityCors *vo.SecurityCors, request *vo.HTTPRequest) error
+	BuildHeader(securityCors *vo.SecurityCors, request *vo.HTTPRequest) vo.Header
+	BuildPreflightHeader(securityCors *vo.SecurityCors, request *vo.HTTPRequest) vo.Header
 }
 
 func NewSecurityCors() SecurityCors {
@@ -38,7 +44,9 @@ func NewSecurityCors() SecurityCors {
 }
 
 func (s securityCorsService) ValidateOrigin(securityCors *vo.SecurityCors, request *vo.HTTPRequest) error {
-	if securityCors.DisallowOrigin(request.ClientIP()) {
+	// requests without origin are not made by browsers, so there is nothing to check
+	origin := request.Header().Get(mapper.Origin)
+	if checker.IsNotEmpty(origin) && securityCors.DisallowOrigin(origin) {
 		return errors.New("Origin not mapped on security-cors.allow-origins")
 	}
 	return nil
@@ -54,15 +62,107 @@ func (s securityCorsService) ValidateMethod(securityCors *vo.SecurityCors, reque
 func (s securityCorsService) ValidateHeaders(securityCors *vo.SecurityCors, request *vo.HTTPRequest) error {
 	var headersNotAllowed []string
 	for _, key := range request.Header().Keys() {
-		if checker.NotEquals(key, mapper.XForwardedFor) && securityCors.DisallowHeader(key) {
+		if mapper.IsNotBrowserHeaderKey(key) && securityCors.DisallowHeader(key) {
 			headersNotAllowed = append(headersNotAllowed, key)
 		}
 	}
+	return s.buildHeadersNotAllowedErr(headersNotAllowed)
+}
+
+func (s securityCorsService) ValidatePreflight(securityCors *vo.SecurityCors, request *vo.HTTPRequest) error {
+	if err := s.ValidateOrigin(securityCors, request); checker.NonNil(err) {
+		return err
+	}
+
+	method := request.Header().Get(mapper.AccessControlRequestMethod)
+	if securityCors.DisallowMethod(method) {
+		return errors.New("Method not mapped on security-cors.allow-methods")
+	}
+
+	var headersNotAllowed []string
+	for _, key := range s.requestedHeaderKeys(request) {
+		if mapper.IsNotBrowserHeaderKey(key) && securityCors.DisallowHeader(key) {
+			headersNotAllowed = append(headersNotAllowed, key)
+		}
+	}
+	return s.buildHeadersNotAllowedErr(headersNotAllowed)
+}
+
+func (s securityCorsService) BuildHeader(securityCors *vo.SecurityCors, request *vo.HTTPRequest) vo.Header {
+	header := s.buildOriginHeader(securityCors, request)
+	if checker.IsNotEmpty(header) && checker.IsNotEmpty(securityCors.ExposeHeaders()) {
+		header[mapper.AccessControlExposeHeaders] = []string{strings.Join(securityCors.ExposeHeaders(), ", ")}
+	}
+	return vo.NewHeader(header)
+}
+
+func (s securityCorsService) BuildPreflightHeader(securityCors *vo.SecurityCors, request *vo.HTTPRequest) vo.Header {
+	header := s.buildOriginHeader(securityCors, request)
+	if checker.IsEmpty(header) {
+		return vo.NewHeader(header)
+	}
+
+	// without configured values we allow what the browser asked for, it was already validated
+	allowMethods := securityCors.AllowMethods()
+	if checker.IsEmpty(allowMethods) || slices.Contains(allowMethods, "*") {
+		allowMethods = []string{request.Header().Get(mapper.AccessControlRequestMethod)}
+	}
+	header[mapper.AccessControlAllowMethods] = []string{strings.Join(allowMethods, ", ")}
 
+	allowHeaders := securityCors.AllowHeaders()
+	if checker.IsEmpty(allowHeaders) || slices.Contains(allowHeaders, "*") {
+		allowHeaders = s.requestedHeaderKeys(request)
+	}
+	if checker.IsNotEmpty(allowHeaders) {
+		header[mapper.AccessControlAllowHeaders] = []string{strings.Join(allowHeaders, ", ")}
+	}
+
+	if checker.IsGreaterThan(securityCors.MaxAge(), 0) {
+		seconds := int(securityCors.MaxAge().Time().Seconds())
+		header[mapper.AccessControlMaxAge] = []string{strconv.Itoa(seconds)}
+	}
+	return vo.NewHeader(header)
+}
+
+func (s securityCorsService) buildOriginHeader(securityCors *vo.SecurityCors, request *vo.HTTPRequest,
+) map[string][]string {
+	header := map[string][]string{}
+
+	origin := request.Header().Get(mapper.Origin)
+	if checker.IsEmpty(origin) {
+		return header
+	}
+
+	// without an explicit allowed origin we answer with the wildcard, so credentials are never shared with any origin
+	if securityCors.AllowAnyOrigin() {
+		header[mapper.AccessControlAllowOrigin] = []string{"*"}
+		return header
+	} else if securityCors.DisallowOrigin(origin) {
+		return header
+	}
+
+	header[mapper.AccessControlAllowOrigin] = []string{origin}
+	header[mapper.Vary] = []string{mapper.Origin}
+	if securityCors.AllowCredentials() {
+		header[mapper.AccessControlAllowCredentials] = []string{"true"}
+	}
+	return header
+}
+
+func (s securityCorsService) requestedHeaderKeys(request *vo.HTTPRequest) []string {
+	var keys []string
+	for _, key := range strings.Split(request.Header().Get(mapper.AccessControlRequestHeaders), ",") {
+		if key = strings.TrimSpace(key); checker.IsNotEmpty(key) {
+			keys = append(keys, http.CanonicalHeaderKey(key))
+		}
+	}
+	return keys
+}
+
+func (s securityCorsService) buildHeadersNotAllowedErr(headersNotAllowed []string) error {
 	if checker.IsNotEmpty(headersNotAllowed) {
 		keys := strings.Join(headersNotAllowed, ", ")
 		return errors.Newf("Headers contain not mapped fields on security-cors.allow-headers: %s", keys)
 	}
-
 	return nil
 }
@@ -0,0 +1,167 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"github.com/tech4works/gopen-gateway/internal/domain/model/vo"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSecurityCorsValidatePreflight(t *testing.T) {
+	tests := []struct {
+		name         string
+		securityCors *vo.SecurityCors
+		header       map[string][]string
+		wantErr      bool
+	}{
+		{
+			name:         "without configured values",
+			securityCors: vo.NewSecurityCors(nil, nil, nil, nil, false, 0),
+			header:       newTestPreflightHeader("https://app.example.com", "DELETE", "X-Custom"),
+		},
+		{
+			name:         "allowed origin, method and headers",
+			securityCors: newTestSecurityCors(),
+			header:       newTestPreflightHeader("https://app.example.com", "POST", "content-type, x-api-key"),
+		},
+		{
+			name:         "origin not allowed",
+			securityCors: newTestSecurityCors(),
+			header:       newTestPreflightHeader("https://evil.com", "POST", ""),
+			wantErr:      true,
+		},
+		{
+			name:         "method not allowed",
+			securityCors: newTestSecurityCors(),
+			header:       newTestPreflightHeader("https://app.example.com", "DELETE", ""),
+			wantErr:      true,
+		},
+		{
+			name:         "header not allowed",
+			securityCors: newTestSecurityCors(),
+			header:       newTestPreflightHeader("https://app.example.com", "POST", "X-Custom"),
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewSecurityCors()
+			err := service.ValidatePreflight(tt.securityCors, newTestCorsRequest(tt.header))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidatePreflight() err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSecurityCorsBuildPreflightHeader(t *testing.T) {
+	tests := []struct {
+		name         string
+		securityCors *vo.SecurityCors
+		header       map[string][]string
+		want         map[string][]string
+	}{
+		{
+			name:         "without origin",
+			securityCors: newTestSecurityCors(),
+			header:       map[string][]string{},
+			want:         map[string][]string{},
+		},
+		{
+			name:         "configured values",
+			securityCors: newTestSecurityCors(),
+			header:       newTestPreflightHeader("https://app.example.com", "POST", "Content-Type"),
+			want: map[string][]string{
+				"Access-Control-Allow-Origin":  {"https://app.example.com"},
+				"Vary":                         {"Origin"},
+				"Access-Control-Allow-Methods": {"GET, POST"},
+				"Access-Control-Allow-Headers": {"Content-Type, X-Api-Key"},
+				"Access-Control-Max-Age":       {"600"},
+			},
+		},
+		{
+			name:         "requested values",
+			securityCors: vo.NewSecurityCors(nil, []string{"*"}, nil, nil, false, 0),
+			header:       newTestPreflightHeader("https://app.example.com", "DELETE", "x-custom, x-other"),
+			want: map[string][]string{
+				"Access-Control-Allow-Origin":  {"*"},
+				"Access-Control-Allow-Methods": {"DELETE"},
+				"Access-Control-Allow-Headers": {"X-Custom, X-Other"},
+			},
+		},
+		{
+			name:         "wildcard origin",
+			securityCors: vo.NewSecurityCors([]string{"*"}, []string{"GET"}, nil, nil, false, 0),
+			header:       newTestPreflightHeader("https://app.example.com", "GET", ""),
+			want: map[string][]string{
+				"Access-Control-Allow-Origin":  {"*"},
+				"Access-Control-Allow-Methods": {"GET"},
+			},
+		},
+		{
+			name: "credentials with an explicit origin",
+			securityCors: vo.NewSecurityCors([]string{"https://app.example.com"}, []string{"GET"}, nil, nil, true,
+				0),
+			header: newTestPreflightHeader("https://app.example.com", "GET", ""),
+			want: map[string][]string{
+				"Access-Control-Allow-Origin":      {"https://app.example.com"},
+				"Access-Control-Allow-Credentials": {"true"},
+				"Vary":                             {"Origin"},
+				"Access-Control-Allow-Methods":     {"GET"},
+			},
+		},
+		{
+			name: "credentials with an origin not allowed",
+			securityCors: vo.NewSecurityCors([]string{"https://app.example.com"}, []string{"GET"}, nil, nil, true,
+				0),
+			header: newTestPreflightHeader("https://evil.com", "GET", ""),
+			want:   map[string][]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewSecurityCors()
+			got := service.BuildPreflightHeader(tt.securityCors, newTestCorsRequest(tt.header))
+			if !reflect.DeepEqual(got.Copy(), tt.want) {
+				t.Errorf("BuildPreflightHeader() = %v, want %v", got.Copy(), tt.want)
+			}
+		})
+	}
+}
+
+func newTestSecurityCors() *vo.SecurityCors {
+	return vo.NewSecurityCors([]string{"https://*.example.com"}, []string{"GET", "POST"},
+		[]string{"Content-Type", "X-Api-Key"}, nil, false, vo.NewDuration(10*time.Minute))
+}
+
+func newTestPreflightHeader(origin, method, headers string) map[string][]string {
+	header := map[string][]string{
+		"Origin":                        {origin},
+		"Access-Control-Request-Method": {method},
+	}
+	if headers != "" {
+		header["Access-Control-Request-Headers"] = []string{headers}
+	}
+	return header
+}
+
+func newTestCorsRequest(header map[string][]string) *vo.HTTPRequest {
+	return vo.NewHTTPRequest(vo.NewURLPath("/users", nil), "/users", "OPTIONS", vo.NewHeader(header),
+		vo.NewEmptyQuery(), nil)
+}
@@ -0,0 +1,66 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"fmt"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/vo"
+	"sync"
+)
+
+type toggleService struct {
+	mutex    *sync.RWMutex
+	disabled map[string]bool
+}
+
+type Toggle interface {
+	Enable(endpoint *vo.Endpoint)
+	Disable(endpoint *vo.Endpoint)
+	IsDisabled(endpoint *vo.Endpoint) bool
+}
+
+func NewToggle() Toggle {
+	return &toggleService{
+		mutex:    &sync.RWMutex{},
+		disabled: map[string]bool{},
+	}
+}
+
+func (t *toggleService) Enable(endpoint *vo.Endpoint) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	delete(t.disabled, t.buildKey(endpoint))
+}
+
+func (t *toggleService) Disable(endpoint *vo.Endpoint) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.disabled[t.buildKey(endpoint)] = true
+}
+
+func (t *toggleService) IsDisabled(endpoint *vo.Endpoint) bool {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.disabled[t.buildKey(endpoint)]
+}
+
+func (t *toggleService) buildKey(endpoint *vo.Endpoint) string {
+	return fmt.Sprintf("%s:%s", endpoint.Method(), endpoint.Path())
+}
@@ -0,0 +1,77 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"github.com/tech4works/gopen-gateway/internal/domain/model/vo"
+	"testing"
+)
+
+func TestToggleIsDisabled(t *testing.T) {
+	users := newTestToggleEndpoint("GET", "/users")
+	createUser := newTestToggleEndpoint("POST", "/users")
+
+	tests := []struct {
+		name    string
+		disable []vo.Endpoint
+		enable  []vo.Endpoint
+		want    map[string]bool
+	}{
+		{
+			name: "enabled by default",
+			want: map[string]bool{"GET": false, "POST": false},
+		},
+		{
+			name:    "disabled only for the same method",
+			disable: []vo.Endpoint{users},
+			want:    map[string]bool{"GET": true, "POST": false},
+		},
+		{
+			name:    "enabled again",
+			disable: []vo.Endpoint{users, createUser},
+			enable:  []vo.Endpoint{users},
+			want:    map[string]bool{"GET": false, "POST": true},
+		},
+		{
+			name:   "enable without disable",
+			enable: []vo.Endpoint{users},
+			want:   map[string]bool{"GET": false, "POST": false},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewToggle()
+			for _, endpoint := range tt.disable {
+				service.Disable(&endpoint)
+			}
+			for _, endpoint := range tt.enable {
+				service.Enable(&endpoint)
+			}
+
+			for _, endpoint := range []vo.Endpoint{users, createUser} {
+				if got := service.IsDisabled(&endpoint); got != tt.want[endpoint.Method()] {
+					t.Errorf("IsDisabled(%s %s) = %v, want %v", endpoint.Method(), endpoint.Path(), got,
+						tt.want[endpoint.Method()])
+				}
+			}
+		})
+	}
+}
+
+func newTestToggleEndpoint(method, path string) vo.Endpoint {
+	return vo.NewEndpoint(path, method, 0, vo.Limiter{}, nil, nil, nil, nil, nil, nil, nil)
+}
@@ -18,6 +18,8 @@ package api
 
 import (
 	"bytes"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/tech4works/checker"
@@ -27,37 +29,46 @@ import (
 	"github.com/tech4works/gopen-gateway/internal/app/model/dto"
 	"github.com/tech4works/gopen-gateway/internal/domain/mapper"
 	"github.com/tech4works/gopen-gateway/internal/domain/model/vo"
-	"go.elastic.co/apm/v2"
 	"golang.org/x/net/context"
 	"io"
+	"regexp"
+	"strings"
 	"sync"
 	"time"
 )
 
+var requestIDRegex = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+var traceparentRegex = regexp.MustCompile(`^[0-9a-f]{2}-([0-9a-f]{32})-[0-9a-f]{16}-[0-9a-f]{2}$`)
+
 type Context struct {
-	startTime time.Time
-	mutex     *sync.RWMutex
-	engine    *gin.Context
-	gopen     *vo.Gopen
-	endpoint  *vo.Endpoint
-	request   *vo.HTTPRequest
-	response  *vo.HTTPResponse
+	startTime      time.Time
+	mutex          *sync.RWMutex
+	engine         *gin.Context
+	tracer         app.Tracer
+	gopen          *vo.Gopen
+	endpoint       *vo.Endpoint
+	request        *vo.HTTPRequest
+	response       *vo.HTTPResponse
+	responseHeader vo.Header
 }
 
-func newContext(gin *gin.Context, gopen *vo.Gopen, endpoint *vo.Endpoint) app.Context {
-	request := buildHTTPRequest(gin)
+func newContext(gin *gin.Context, tracer app.Tracer, gopen *vo.Gopen, endpoint *vo.Endpoint) app.Context {
+	request := buildHTTPRequest(gin, endpoint)
 	return &Context{
 		startTime: time.Now(),
 		mutex:     &sync.RWMutex{},
 		engine:    gin,
+		tracer:    tracer,
 		gopen:     gopen,
 		endpoint:  endpoint,
 		request:   request,
 	}
 }
 
-func buildHTTPRequest(gin *gin.Context) *vo.HTTPRequest {
+func buildHTTPRequest(gin *gin.Context, endpoint *vo.Endpoint) *vo.HTTPRequest {
 	gin.Request.Header.Add(mapper.XForwardedFor, gin.ClientIP())
+	gin.Request.Header.Set(mapper.XRequestId, buildRequestID(gin))
 	header := vo.NewHeader(gin.Request.Header)
 
 	query := vo.NewQuery(gin.Request.URL.Query())
@@ -72,14 +83,42 @@ func buildHTTPRequest(gin *gin.Context) *vo.HTTPRequest {
 	}
 	path := vo.NewURLPath(gin.FullPath(), ginParams)
 
-	bodyBytes, err := io.ReadAll(gin.Request.Body)
-	if checker.NonNil(err) {
-		panic(err)
+	body := vo.NewBody(gin.GetHeader(mapper.ContentType), gin.GetHeader(mapper.ContentEncoding), readBody(gin, endpoint))
+
+	return vo.NewHTTPRequest(path, url, gin.Request.Method, header, query, body)
+}
+
+func buildRequestID(gin *gin.Context) string {
+	// an id sent by the caller is kept so the gateway joins its chain, otherwise the trace id of the traceparent is
+	// used, and only when neither is present a new one is generated
+	requestID := gin.GetHeader(mapper.XRequestId)
+	if requestIDRegex.MatchString(requestID) {
+		return requestID
 	}
 
-	body := vo.NewBody(gin.GetHeader(mapper.ContentType), gin.GetHeader(mapper.ContentEncoding), bytes.NewBuffer(bodyBytes))
+	matches := traceparentRegex.FindStringSubmatch(gin.GetHeader(mapper.Traceparent))
+	if checker.IsLengthEquals(matches, 2) && checker.NotEquals(matches[1], strings.Repeat("0", 32)) {
+		return matches[1]
+	}
 
-	return vo.NewHTTPRequest(path, url, gin.Request.Method, header, query, body)
+	randomBytes := make([]byte, 16)
+	rand.Read(randomBytes)
+	return hex.EncodeToString(randomBytes)
+}
+
+func readBody(gin *gin.Context, endpoint *vo.Endpoint) *bytes.Buffer {
+	// the body is never buffered over the limit, if the declared length already exceeds it we don't read anything
+	// and the limiter middleware rejects the request, otherwise we read one extra byte to detect the excess
+	maxBodySize := int64(endpoint.Limiter().MaxBodySizeBy(gin.GetHeader(mapper.ContentType)))
+	if checker.IsGreaterThan(gin.Request.ContentLength, maxBodySize) {
+		return bytes.NewBuffer(nil)
+	}
+
+	bodyBytes, err := io.ReadAll(io.LimitReader(gin.Request.Body, maxBodySize+1))
+	if checker.NonNil(err) {
+		panic(err)
+	}
+	return bytes.NewBuffer(bodyBytes)
 }
 
 func (c *Context) Context() context.Context {
@@ -103,11 +142,11 @@ func (c *Context) Duration() time.Duration {
 }
 
 func (c *Context) TraceID() string {
-	tx := apm.TransactionFromContext(c.Context())
-	if checker.NonNil(tx) {
-		return tx.TraceContext().Trace.String()
+	traceID := c.tracer.TraceID(c.Context())
+	if checker.IsNotEmpty(traceID) {
+		return traceID
 	}
-	return "undefined"
+	return c.Request().RequestID()
 }
 
 func (c *Context) ClientIP() string {
@@ -126,10 +165,25 @@ func (c *Context) Request() *vo.HTTPRequest {
 	return c.request
 }
 
+func (c *Context) WithRequest(request *vo.HTTPRequest) {
+	c.request = request
+}
+
 func (c *Context) Response() *vo.HTTPResponse {
 	return c.response
 }
 
+func (c *Context) AddResponseHeader(header vo.Header) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	values := c.responseHeader.Copy()
+	for _, key := range header.Keys() {
+		values[key] = header.GetAll(key)
+	}
+	c.responseHeader = vo.NewHeader(values)
+}
+
 func (c *Context) Write(response *vo.HTTPResponse) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
@@ -145,7 +199,10 @@ func (c *Context) Write(response *vo.HTTPResponse) {
 		rawBodyBytes = response.Body().RawBytes()
 	}
 
+	// headers added by the context are only written, they don't belong to the response that is logged and cached
 	c.writeHeader(response.Header())
+	c.writeHeader(c.responseHeader)
+	c.engine.Header(mapper.XRequestId, c.request.RequestID())
 	if checker.IsNotEmpty(rawBodyBytes) {
 		c.writeBody(response.StatusCode(), contentType.String(), rawBodyBytes)
 	} else {
@@ -0,0 +1,83 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+)
+
+func TestBuildRequestID(t *testing.T) {
+	tests := []struct {
+		name   string
+		header map[string]string
+		want   string
+	}{
+		{
+			name:   "informed request id",
+			header: map[string]string{"X-Request-Id": "req-123"},
+			want:   "req-123",
+		},
+		{
+			name: "request id over the traceparent",
+			header: map[string]string{
+				"X-Request-Id": "req-123",
+				"Traceparent":  "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			},
+			want: "req-123",
+		},
+		{
+			name:   "trace id of the traceparent",
+			header: map[string]string{"Traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+			want:   "4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+		{
+			name: "invalid request id",
+			header: map[string]string{
+				"X-Request-Id": "req 123\n",
+				"Traceparent":  "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			},
+			want: "4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+		{
+			name:   "zero trace id",
+			header: map[string]string{"Traceparent": "00-00000000000000000000000000000000-00f067aa0ba902b7-01"},
+		},
+		{
+			name: "without headers",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/users", nil)
+			for key, value := range tt.header {
+				ctx.Request.Header.Set(key, value)
+			}
+
+			got := buildRequestID(ctx)
+			if tt.want != "" && got != tt.want {
+				t.Errorf("buildRequestID() = %s, want %s", got, tt.want)
+			} else if tt.want == "" && !regexp.MustCompile(`^[0-9a-f]{32}$`).MatchString(got) {
+				t.Errorf("buildRequestID() = %s, want a generated id", got)
+			}
+		})
+	}
+}
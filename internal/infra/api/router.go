@@ -25,14 +25,16 @@ import (
 
 type router struct {
 	engine *gin.Engine
+	tracer app.Tracer
 }
 
-func NewRouter() app.Router {
+func NewRouter(tracer app.Tracer) app.Router {
 	gin.SetMode(gin.ReleaseMode)
 	engine := gin.New()
 
 	return router{
 		engine: engine,
+		tracer: tracer,
 	}
 }
 
@@ -56,7 +58,7 @@ func (r router) buildEngineHandle(gopen *vo.Gopen, endpoint *vo.Endpoint, handle
 	return func(gin *gin.Context) {
 		ctx, ok := gin.Get("context")
 		if !ok {
-			ctx = newContext(gin, gopen, endpoint)
+			ctx = newContext(gin, r.tracer, gopen, endpoint)
 			gin.Set("context", ctx)
 		}
 		handle(ctx.(*Context))
@@ -26,19 +26,27 @@ import (
 	"github.com/tech4works/converter"
 	"github.com/tech4works/errors"
 	"github.com/tech4works/gopen-gateway/internal/app"
+	"github.com/tech4works/gopen-gateway/internal/app/factory"
 	"github.com/tech4works/gopen-gateway/internal/app/model/dto"
 	"github.com/tech4works/gopen-gateway/internal/app/server"
+	"github.com/tech4works/gopen-gateway/internal/domain"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/enum"
 	"github.com/tech4works/gopen-gateway/internal/infra/api"
 	"github.com/tech4works/gopen-gateway/internal/infra/cache"
 	"github.com/tech4works/gopen-gateway/internal/infra/convert"
 	"github.com/tech4works/gopen-gateway/internal/infra/http"
 	"github.com/tech4works/gopen-gateway/internal/infra/jsonpath"
+	"github.com/tech4works/gopen-gateway/internal/infra/jwt"
+	"github.com/tech4works/gopen-gateway/internal/infra/limiter"
 	"github.com/tech4works/gopen-gateway/internal/infra/log"
+	"github.com/tech4works/gopen-gateway/internal/infra/metrics"
 	"github.com/tech4works/gopen-gateway/internal/infra/nomenclature"
+	"github.com/tech4works/gopen-gateway/internal/infra/tracer"
 	"github.com/xeipuuv/gojsonschema"
 	"os"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -47,16 +55,32 @@ const jsonRuntimeUri = runtimeFolder + "/.json"
 const jsonSchemaUri = "file://./json-schema.json"
 
 type provider struct {
-	log app.BootLog
+	log          app.BootLog
+	metrics      app.Metrics
+	tracer       app.Tracer
+	tracerConfig *dto.Tracer
+	stores       *stores
+	httpServer   server.HTTP
+	mutex        *sync.Mutex
+}
+
+type stores struct {
+	store             domain.Store
+	limiterStore      domain.LimiterStore
+	localLimiterStore domain.LocalLimiterStore
+	storeConfig       *dto.Store
+	bucketsConfig     *dto.Buckets
 }
 
 func New() app.Boot {
-	return provider{
-		log: log.NewBoot(),
+	return &provider{
+		log:     log.NewBoot(),
+		metrics: metrics.NewPrometheus(),
+		mutex:   &sync.Mutex{},
 	}
 }
 
-func (p provider) Init() *dto.Gopen {
+func (p *provider) Init() *dto.Gopen {
 	if checker.IsEmpty(os.Getenv("GOPEN_ENV")) || checker.IsEmpty(os.Getenv("GOPEN_PORT")) {
 		panic("Please fill in the mandatory environment variables which are GOPEN_ENV and GOPEN_PORT!")
 	}
@@ -65,57 +89,48 @@ func (p provider) Init() *dto.Gopen {
 		panic("Please fill the environment variable GOPEN_PORT with numbers only!")
 	}
 
+	// the settings are loaded before anything is printed, so that every line follows the configured log
+	envErr := p.loadEnvs()
+	gopen, jsonErr := p.loadJson()
+	if checker.IsNil(jsonErr) {
+		log.Configure(gopen.Log)
+	}
+
 	p.log.PrintLogo()
 
 	p.log.PrintInfo("Loading Gopen envs...")
-	err := p.loadEnvs()
-	if checker.NonNil(err) {
-		p.log.PrintWarn(err)
+	if checker.NonNil(envErr) {
+		p.log.PrintWarn(envErr)
 	}
 
 	p.log.PrintInfo("Loading Gopen json...")
-	gopen, err := p.loadJson()
-	if checker.NonNil(err) {
-		panic(err)
+	if checker.NonNil(jsonErr) {
+		panic(jsonErr)
 	}
 
 	os.Setenv("ELASTIC_APM_ENVIRONMENT", os.Getenv("GOPEN_ENV"))
 	os.Setenv("ELASTIC_APM_SERVICE_VERSION", gopen.Version)
 
+	p.log.PrintInfo("Configuring tracer...")
+	p.tracer = p.buildTracer(gopen)
+	p.tracerConfig = gopen.Tracer
+
 	return gopen
 }
 
-func (p provider) Start(gopen *dto.Gopen) {
-	p.log.PrintInfo("Configuring cache store...")
-	store := cache.NewMemoryStore()
-	if checker.NonNil(gopen.Store) {
-		store = cache.NewRedisStore(gopen.Store.Redis.Address, gopen.Store.Redis.Password)
-	}
-	defer store.Close()
-
+func (p *provider) Start(gopen *dto.Gopen) {
 	err := p.writeRuntimeJson(gopen)
 	if checker.NonNil(err) {
 		p.log.PrintWarn(err)
 	}
 
-	p.log.PrintInfo("Building log providers...")
-	endpointLog := log.NewEndpoint()
-	backendLog := log.NewBackend()
-	httpLog := log.NewHTTPLog()
-
-	p.log.PrintInfo("Building server...")
-	router := api.NewRouter()
-	httpClient := http.NewClient()
-	jsonPath := jsonpath.New()
-	nConverter := convert.New()
-	nNomenclature := nomenclature.New()
-
-	httpServer := server.New(gopen, p.log, router, httpClient, endpointLog, backendLog, httpLog, jsonPath, nConverter,
-		store, nNomenclature)
+	nextStores := p.buildStores(gopen)
+	p.httpServer = p.buildServer(gopen, nextStores)
+	p.stores = nextStores
 
 	if gopen.HotReload {
 		p.log.PrintInfo("Configuring watcher...")
-		watcher, err := p.initWatcher(gopen, httpServer)
+		watcher, err := p.initWatcher()
 		if checker.NonNil(err) {
 			p.log.PrintWarn("Error configure watcher:", err)
 		} else {
@@ -123,68 +138,249 @@ func (p provider) Start(gopen *dto.Gopen) {
 		}
 	}
 
-	httpServer.ListenAndServe()
+	p.httpServer.ListenAndServe()
 }
 
-func (p provider) Stop() {
+func (p *provider) Reload() (err error) {
+	// the reload can be triggered by the watcher and by the admin at the same time
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	// the readiness answers reloading for the whole reload, the current generation at the end is the swapped one
+	p.httpServer.MarkReloading(true)
+	defer p.httpServer.MarkReloading(false)
+
+	defer func() {
+		if r := recover(); checker.NonNil(r) {
+			p.log.PrintError("Error reload server:", r)
+			p.log.PrintWarn("Keeping current server!")
+			err = errors.New("Error reload server:", r)
+		}
+	}()
+
 	p.log.SkipLine()
+	p.log.PrintTitle("RELOAD")
 
-	p.log.PrintInfo("Removing runtime json...")
+	p.log.PrintInfo("Reloading Gopen envs...")
+	err = p.loadEnvs()
+	if checker.NonNil(err) {
+		p.log.PrintWarn(err)
+	}
 
-	err := p.removeRuntimeJson()
+	p.log.PrintInfo("Reloading Gopen json...")
+	gopen, err := p.loadJson()
 	if checker.NonNil(err) {
-		p.log.PrintWarn("Error to remove runtime json!")
+		p.log.PrintError("Error reload Gopen json:", err)
+		p.log.PrintWarn("Keeping current server!")
+		return err
 	}
 
-	p.log.PrintTitle("STOPPED")
-}
+	// the tracer wraps the listener, so it is shared by every generation and only built on start
+	if checker.NotEquals(gopen.Tracer, p.tracerConfig) {
+		p.log.PrintWarn("Tracer changes are only applied on restart!")
+	}
+
+	nextStores := p.buildStores(gopen)
+	nextServer := p.buildServer(gopen, nextStores)
 
-func (p provider) restart(oldGopen *dto.Gopen, oldServer server.HTTP) {
+	// the stores opened for a reload that doesn't complete are closed, the ones kept from the current server are not
 	defer func() {
 		if r := recover(); checker.NonNil(r) {
-			errorDetails := errors.Details(r.(error))
-			p.log.PrintError("Error restart server:", errorDetails.Cause())
-
-			p.recovery(oldGopen)
+			p.closeStores(nextStores, p.stores)
+			panic(r)
 		}
 	}()
 
-	p.log.SkipLine()
-	p.log.PrintTitle("RESTART")
-
 	ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Minute)
 	defer cancel()
 
-	p.log.PrintInfo("Shutting down current server...")
-	err := oldServer.Shutdown(ctx)
+	err = p.httpServer.Reload(ctx, nextServer)
 	if checker.NonNil(err) {
-		p.log.PrintWarnf("Error shutdown current server: %s!", err)
-		return
+		p.log.PrintError("Error reload server:", err)
+		p.log.PrintWarn("Keeping current server!")
+		if shutdownErr := nextServer.Shutdown(ctx); checker.NonNil(shutdownErr) {
+			p.log.PrintWarn("Error close next server:", shutdownErr)
+		}
+		p.closeStores(nextStores, p.stores)
+		return err
 	}
+	p.closeStores(p.stores, nextStores)
+	p.stores = nextStores
 
-	p.log.PrintInfo("Reloading Gopen envs...")
-	err = p.loadEnvs()
+	log.Configure(gopen.Log)
+
+	err = p.writeRuntimeJson(gopen)
 	if checker.NonNil(err) {
 		p.log.PrintWarn(err)
 	}
 
-	p.log.PrintInfo("Reloading Gopen json...")
-	gopen, err := p.loadJson()
+	p.log.PrintTitle("RELOADED")
+
+	return nil
+}
+
+func (p *provider) Stop() {
+	p.log.SkipLine()
+
+	p.log.PrintInfo("Removing runtime json...")
+
+	err := p.removeRuntimeJson()
 	if checker.NonNil(err) {
-		panic(err)
+		p.log.PrintWarn("Error to remove runtime json!")
 	}
 
-	p.Start(gopen)
+	p.log.PrintInfo("Flushing tracer...")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err = p.tracer.Shutdown(ctx)
+	if checker.NonNil(err) {
+		p.log.PrintWarn("Error to flush tracer:", err)
+	}
+
+	p.log.PrintTitle("STOPPED")
 }
 
-func (p provider) recovery(oldGopen *dto.Gopen) {
-	p.log.SkipLine()
-	p.log.PrintTitle("RECOVERY")
+func (p *provider) buildStores(gopen *dto.Gopen) *stores {
+	var bucketsConfig *dto.Buckets
+	if checker.NonNil(gopen.Limiter) {
+		bucketsConfig = gopen.Limiter.Buckets
+	}
+	result := &stores{
+		storeConfig:   gopen.Store,
+		bucketsConfig: bucketsConfig,
+	}
+
+	// on reload the stores with an unchanged config are kept, so the cache and the limiter counters are not reset
+	if checker.NonNil(p.stores) && checker.Equals(p.stores.storeConfig, gopen.Store) {
+		p.log.PrintInfo("Keeping cache and limiter stores...")
+		result.store = p.stores.store
+		result.limiterStore = p.stores.limiterStore
+	} else {
+		p.log.PrintInfo("Configuring cache store...")
+		result.store = cache.NewMemoryStore(p.tracer)
+		if checker.NonNil(gopen.Store) {
+			result.store = cache.NewRedisStore(gopen.Store.Redis.Address, gopen.Store.Redis.Password, p.tracer)
+		}
+
+		p.log.PrintInfo("Configuring limiter store...")
+		if checker.NonNil(gopen.Store) {
+			result.limiterStore = limiter.NewRedisStore(gopen.Store.Redis.Address, gopen.Store.Redis.Password,
+				p.tracer)
+		}
+	}
 
-	go p.Start(oldGopen)
+	if checker.NonNil(p.stores) && checker.Equals(p.stores.bucketsConfig, bucketsConfig) {
+		p.log.PrintInfo("Keeping local limiter store...")
+		result.localLimiterStore = p.stores.localLimiterStore
+	} else {
+		result.localLimiterStore = p.buildLocalLimiterStore(gopen)
+	}
+
+	return result
+}
+
+func (p *provider) buildServer(gopen *dto.Gopen, nextStores *stores) server.HTTP {
+	// an invalid config panics while the server is built, the stores opened until then must not be leaked
+	defer func() {
+		if r := recover(); checker.NonNil(r) {
+			p.closeStores(nextStores, p.stores)
+			panic(r)
+		}
+	}()
+
+	p.log.PrintInfo("Building log providers...")
+	endpointLog := log.NewEndpoint()
+	backendLog := log.NewBackend()
+	httpLog := log.NewHTTPLog()
+
+	p.log.PrintInfo("Building server...")
+	router := api.NewRouter(p.tracer)
+	adminRouter := api.NewRouter(p.tracer)
+	httpClient := http.NewClient(p.tracer)
+	jsonPath := jsonpath.New()
+	nConverter := convert.New()
+	nNomenclature := nomenclature.New()
+	nJWT := jwt.New()
+
+	return server.New(gopen, p.httpServer, p, p.log, router, adminRouter, httpClient, endpointLog, backendLog, httpLog,
+		jsonPath, nConverter, nextStores.store, nextStores.limiterStore, nextStores.localLimiterStore, nNomenclature,
+		nJWT, p.metrics, p.tracer)
+}
+
+func (p *provider) closeStores(target, keep *stores) {
+	// only the stores of the target that are not shared with the kept ones are closed
+	if checker.IsNil(keep) {
+		keep = &stores{}
+	}
+	if target.store != keep.store {
+		if err := target.store.Close(); checker.NonNil(err) {
+			p.log.PrintWarn("Error close cache store:", err)
+		}
+	}
+	if checker.NonNil(target.limiterStore) && target.limiterStore != keep.limiterStore {
+		if err := target.limiterStore.Close(); checker.NonNil(err) {
+			p.log.PrintWarn("Error close limiter store:", err)
+		}
+	}
+	if target.localLimiterStore != keep.localLimiterStore {
+		if err := target.localLimiterStore.Close(); checker.NonNil(err) {
+			p.log.PrintWarn("Error close local limiter store:", err)
+		}
+	}
+}
+
+func (p *provider) buildTracer(gopen *dto.Gopen) app.Tracer {
+	if checker.IsNil(gopen.Tracer) {
+		return tracer.NewAPM()
+	}
+
+	switch gopen.Tracer.Provider {
+	case enum.TracerProviderNone:
+		return tracer.NewNoop()
+	case enum.TracerProviderOTel:
+		serviceName := "gopen-gateway"
+		if checker.IsNotEmpty(gopen.Tracer.ServiceName) {
+			serviceName = gopen.Tracer.ServiceName
+		}
+		sampleRatio := 1.0
+		if checker.NonNil(gopen.Tracer.SampleRatio) {
+			sampleRatio = *gopen.Tracer.SampleRatio
+		}
+		var endpoint string
+		var headers map[string]string
+		if checker.NonNil(gopen.Tracer.OTLP) {
+			endpoint = gopen.Tracer.OTLP.Endpoint
+			headers = gopen.Tracer.OTLP.Headers
+		}
+
+		otelTracer, err := tracer.NewOTel(serviceName, gopen.Version, endpoint, headers, sampleRatio)
+		if checker.NonNil(err) {
+			p.log.PrintWarn("Error configure OpenTelemetry tracer, tracing disabled:", err)
+			return tracer.NewNoop()
+		}
+		return otelTracer
+	default:
+		return tracer.NewAPM()
+	}
+}
+
+func (p *provider) buildLocalLimiterStore(gopen *dto.Gopen) domain.LocalLimiterStore {
+	var ttl time.Duration
+	var maxEntries int
+	if checker.NonNil(gopen.Limiter) && checker.NonNil(gopen.Limiter.Buckets) {
+		if checker.NonNil(gopen.Limiter.Buckets.TTL) {
+			ttl = gopen.Limiter.Buckets.TTL.Time()
+		}
+		if checker.NonNil(gopen.Limiter.Buckets.MaxEntries) {
+			maxEntries = *gopen.Limiter.Buckets.MaxEntries
+		}
+	}
+	return limiter.NewMemoryStore(ttl, maxEntries)
 }
 
-func (p provider) initWatcher(oldGopen *dto.Gopen, oldServer server.HTTP) (*fsnotify.Watcher, error) {
+func (p *provider) initWatcher() (*fsnotify.Watcher, error) {
 	watcher, err := fsnotify.NewWatcher()
 	if checker.NonNil(err) {
 		return nil, err
@@ -203,7 +399,7 @@ func (p provider) initWatcher(oldGopen *dto.Gopen, oldServer server.HTTP) (*fsno
 				if !ok || checker.NotEquals(ev.Op, fsnotify.Chmod) {
 					continue
 				}
-				p.restart(oldGopen, oldServer)
+				p.Reload()
 			}
 		}
 	}()
@@ -218,7 +414,7 @@ func (p provider) initWatcher(oldGopen *dto.Gopen, oldServer server.HTTP) (*fsno
 	return watcher, nil
 }
 
-func (p provider) loadEnvs() (err error) {
+func (p *provider) loadEnvs() (err error) {
 	gopenEnvUri := p.buildEnvUri()
 
 	if err = godotenv.Overload(gopenEnvUri); checker.NonNil(err) {
@@ -228,7 +424,7 @@ func (p provider) loadEnvs() (err error) {
 	return err
 }
 
-func (p provider) loadJson() (*dto.Gopen, error) {
+func (p *provider) loadJson() (*dto.Gopen, error) {
 	gopenJsonUri := p.buildJsonUri()
 
 	gopenJsonBytes, err := os.ReadFile(gopenJsonUri)
@@ -250,7 +446,7 @@ func (p provider) loadJson() (*dto.Gopen, error) {
 	return &gopen, nil
 }
 
-func (p provider) fillEnvValues(gopenJsonBytes []byte) []byte {
+func (p *provider) fillEnvValues(gopenJsonBytes []byte) []byte {
 	// todo: aceitar campos não string receber variável de ambiente também
 	//  foi pensado que talvez utilizar campos string e any para isso, convertendo para o tipo desejado apenas
 	//  quando objeto de valor for montado
@@ -272,7 +468,7 @@ func (p provider) fillEnvValues(gopenJsonBytes []byte) []byte {
 	return converter.ToBytes(gopenJsonStr)
 }
 
-func (p provider) validateJsonBySchema(jsonBytes []byte) error {
+func (p *provider) validateJsonBySchema(jsonBytes []byte) error {
 	schemaLoader := gojsonschema.NewReferenceLoader(jsonSchemaUri)
 	documentLoader := gojsonschema.NewBytesLoader(jsonBytes)
 
@@ -290,7 +486,7 @@ func (p provider) validateJsonBySchema(jsonBytes []byte) error {
 	return err
 }
 
-func (p provider) writeRuntimeJson(gopen *dto.Gopen) error {
+func (p *provider) writeRuntimeJson(gopen *dto.Gopen) error {
 	if _, err := os.Stat(runtimeFolder); os.IsNotExist(err) {
 		err = os.MkdirAll(runtimeFolder, 0755)
 		if checker.NonNil(err) {
@@ -298,15 +494,15 @@ func (p provider) writeRuntimeJson(gopen *dto.Gopen) error {
 		}
 	}
 
-	gopenJsonBytes, err := json.MarshalIndent(gopen, "", "\t")
+	gopenJsonBytes, err := json.MarshalIndent(factory.BuildRuntimeGopen(*gopen), "", "\t")
 	if checker.IsNil(err) {
-		err = os.WriteFile(jsonRuntimeUri, gopenJsonBytes, 0644)
+		err = os.WriteFile(jsonRuntimeUri, gopenJsonBytes, 0600)
 	}
 
 	return err
 }
 
-func (p provider) removeRuntimeJson() error {
+func (p *provider) removeRuntimeJson() error {
 	err := os.Remove(runtimeFolder)
 	if errors.IsNot(err, os.ErrNotExist) {
 		err = nil
@@ -314,10 +510,10 @@ func (p provider) removeRuntimeJson() error {
 	return err
 }
 
-func (p provider) buildEnvUri() string {
+func (p *provider) buildEnvUri() string {
 	return fmt.Sprintf("./gopen/%s/.env", os.Getenv("GOPEN_ENV"))
 }
 
-func (p provider) buildJsonUri() string {
+func (p *provider) buildJsonUri() string {
 	return fmt.Sprintf("./gopen/%s/.json", os.Getenv("GOPEN_ENV"))
 }
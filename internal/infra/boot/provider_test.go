@@ -0,0 +1,175 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package boot
+
+import (
+	"github.com/tech4works/gopen-gateway/internal/app/model/dto"
+	"github.com/tech4works/gopen-gateway/internal/domain"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/vo"
+	"github.com/tech4works/gopen-gateway/internal/infra/log"
+	"github.com/tech4works/gopen-gateway/internal/infra/tracer"
+	"testing"
+	"time"
+)
+
+type fakeStore struct {
+	domain.Store
+	closed bool
+}
+
+type fakeLimiterStore struct {
+	domain.LimiterStore
+	closed bool
+}
+
+type fakeLocalLimiterStore struct {
+	domain.LocalLimiterStore
+	closed bool
+}
+
+func (f *fakeStore) Close() error {
+	f.closed = true
+	return nil
+}
+
+func (f *fakeLimiterStore) Close() error {
+	f.closed = true
+	return nil
+}
+
+func (f *fakeLocalLimiterStore) Close() error {
+	f.closed = true
+	return nil
+}
+
+func TestProviderBuildStores(t *testing.T) {
+	tests := []struct {
+		name                  string
+		previous              *dto.Gopen
+		next                  *dto.Gopen
+		wantStoreKept         bool
+		wantLocalLimiterKept  bool
+		wantLimiterStoreEmpty bool
+	}{
+		{
+			name:                  "first build",
+			next:                  newTestStoresGopen("", 0),
+			wantLimiterStoreEmpty: true,
+		},
+		{
+			name:                  "unchanged memory stores",
+			previous:              newTestStoresGopen("", 0),
+			next:                  newTestStoresGopen("", 0),
+			wantStoreKept:         true,
+			wantLocalLimiterKept:  true,
+			wantLimiterStoreEmpty: true,
+		},
+		{
+			name:                 "unchanged redis",
+			previous:             newTestStoresGopen("localhost:6379", 0),
+			next:                 newTestStoresGopen("localhost:6379", 0),
+			wantStoreKept:        true,
+			wantLocalLimiterKept: true,
+		},
+		{
+			name:                 "changed redis",
+			previous:             newTestStoresGopen("localhost:6379", 0),
+			next:                 newTestStoresGopen("localhost:6380", 0),
+			wantLocalLimiterKept: true,
+		},
+		{
+			name:                  "redis removed",
+			previous:              newTestStoresGopen("localhost:6379", 0),
+			next:                  newTestStoresGopen("", 0),
+			wantLocalLimiterKept:  true,
+			wantLimiterStoreEmpty: true,
+		},
+		{
+			name:          "changed buckets",
+			previous:      newTestStoresGopen("localhost:6379", time.Minute),
+			next:          newTestStoresGopen("localhost:6379", time.Hour),
+			wantStoreKept: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &provider{log: log.NewBoot(), tracer: tracer.NewNoop()}
+			var previous *stores
+			if tt.previous != nil {
+				previous = p.buildStores(tt.previous)
+				p.stores = previous
+			}
+
+			got := p.buildStores(tt.next)
+			if tt.wantLimiterStoreEmpty != (got.limiterStore == nil) {
+				t.Errorf("buildStores() limiter store = %v, want empty %v", got.limiterStore, tt.wantLimiterStoreEmpty)
+			}
+			if previous == nil {
+				return
+			}
+			if storeKept := got.store == previous.store; storeKept != tt.wantStoreKept {
+				t.Errorf("buildStores() store kept = %v, want %v", storeKept, tt.wantStoreKept)
+			}
+			if tt.wantStoreKept && got.limiterStore != previous.limiterStore {
+				t.Errorf("buildStores() limiter store not kept with the store")
+			}
+			if localKept := got.localLimiterStore == previous.localLimiterStore; localKept != tt.wantLocalLimiterKept {
+				t.Errorf("buildStores() local limiter store kept = %v, want %v", localKept, tt.wantLocalLimiterKept)
+			}
+		})
+	}
+}
+
+func TestProviderCloseStores(t *testing.T) {
+	store := &fakeStore{}
+	limiterStore := &fakeLimiterStore{}
+	localLimiterStore := &fakeLocalLimiterStore{}
+	nextLocalLimiterStore := &fakeLocalLimiterStore{}
+
+	p := &provider{log: log.NewBoot()}
+	p.closeStores(
+		&stores{store: store, limiterStore: limiterStore, localLimiterStore: localLimiterStore},
+		&stores{store: store, limiterStore: limiterStore, localLimiterStore: nextLocalLimiterStore},
+	)
+
+	if store.closed || limiterStore.closed {
+		t.Errorf("closeStores() closed a store kept by the next server")
+	}
+	if !localLimiterStore.closed {
+		t.Errorf("closeStores() kept a store replaced by the next server")
+	}
+	if nextLocalLimiterStore.closed {
+		t.Errorf("closeStores() closed a store of the next server")
+	}
+
+	p.closeStores(&stores{store: store, limiterStore: limiterStore, localLimiterStore: nextLocalLimiterStore}, nil)
+	if !store.closed || !limiterStore.closed || !nextLocalLimiterStore.closed {
+		t.Errorf("closeStores() without kept stores must close every store")
+	}
+}
+
+func newTestStoresGopen(redisAddress string, bucketsTTL time.Duration) *dto.Gopen {
+	gopen := &dto.Gopen{}
+	if redisAddress != "" {
+		gopen.Store = &dto.Store{Redis: &dto.Redis{Address: redisAddress}}
+	}
+	if bucketsTTL > 0 {
+		ttl := vo.NewDuration(bucketsTTL)
+		gopen.Limiter = &dto.Limiter{Buckets: &dto.Buckets{TTL: &ttl}}
+	}
+	return gopen
+}
@@ -24,32 +24,33 @@ import (
 	"github.com/tech4works/converter"
 	"github.com/tech4works/decompressor"
 	"github.com/tech4works/errors"
+	"github.com/tech4works/gopen-gateway/internal/app"
 	"github.com/tech4works/gopen-gateway/internal/domain"
 	"github.com/tech4works/gopen-gateway/internal/domain/mapper"
 	"github.com/tech4works/gopen-gateway/internal/domain/model/vo"
-	"go.elastic.co/apm/v2"
+	"regexp"
+	"strings"
 )
 
 type memoryStore struct {
 	ttlCache *ttlcache.Cache
+	tracer   app.Tracer
 }
 
-func NewMemoryStore() domain.Store {
+func NewMemoryStore(tracer app.Tracer) domain.Store {
 	ttlCache := ttlcache.NewCache()
 	ttlCache.SkipTTLExtensionOnHit(true)
 	return &memoryStore{
 		ttlCache: ttlCache,
+		tracer:   tracer,
 	}
 }
 
 func (m memoryStore) Set(ctx context.Context, key string, cacheResponse *vo.CacheResponse) error {
-	span, _ := apm.StartSpan(ctx, "Write", "cache")
-	if checker.NonNil(span) {
-		span.Context.SetLabel("cache", "GLOBAL")
-		span.Context.SetLabel("key", key)
-
-		defer span.End()
-	}
+	_, span := m.tracer.StartSpan(ctx, "Write", "cache")
+	span.SetLabel("cache", "LOCAL")
+	span.SetLabel("key", key)
+	defer span.End()
 
 	b64, err := compressor.ToGzipBase64WithErr(cacheResponse)
 	if checker.NonNil(err) {
@@ -63,14 +64,33 @@ func (m memoryStore) Del(_ context.Context, key string) error {
 	return m.ttlCache.Remove(key)
 }
 
-func (m memoryStore) Get(ctx context.Context, key string) (*vo.CacheResponse, error) {
-	span, _ := apm.StartSpan(ctx, "Read", "cache")
-	if checker.NonNil(span) {
-		span.Context.SetLabel("cache", "LOCAL")
-		span.Context.SetLabel("key", key)
+func (m memoryStore) DelByPattern(_ context.Context, pattern string) (int, error) {
+	regex, err := m.compilePattern(pattern)
+	if checker.NonNil(err) {
+		return 0, err
+	}
 
-		defer span.End()
+	count := 0
+	for _, key := range m.ttlCache.GetKeys() {
+		if !regex.MatchString(key) {
+			continue
+		}
+		err = m.ttlCache.Remove(key)
+		if errors.Is(err, ttlcache.ErrNotFound) {
+			continue
+		} else if checker.NonNil(err) {
+			return count, err
+		}
+		count++
 	}
+	return count, nil
+}
+
+func (m memoryStore) Get(ctx context.Context, key string) (*vo.CacheResponse, error) {
+	_, span := m.tracer.StartSpan(ctx, "Read", "cache")
+	span.SetLabel("cache", "LOCAL")
+	span.SetLabel("key", key)
+	defer span.End()
 
 	value, err := m.ttlCache.Get(key)
 	if errors.Is(err, ttlcache.ErrNotFound) {
@@ -93,6 +113,18 @@ func (m memoryStore) Get(ctx context.Context, key string) (*vo.CacheResponse, er
 	return &cacheResponse, nil
 }
 
+func (m memoryStore) Ping(ctx context.Context) error {
+	return nil
+}
+
 func (m memoryStore) Close() error {
 	return nil
 }
+
+func (m memoryStore) compilePattern(pattern string) (*regexp.Regexp, error) {
+	// same glob syntax accepted by the redis store, * matches any sequence and ? a single character
+	expr := regexp.QuoteMeta(pattern)
+	expr = strings.ReplaceAll(expr, `\*`, ".*")
+	expr = strings.ReplaceAll(expr, `\?`, ".")
+	return regexp.Compile("^" + expr + "$")
+}
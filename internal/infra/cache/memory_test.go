@@ -0,0 +1,73 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cache
+
+import (
+	"context"
+	"github.com/tech4works/gopen-gateway/internal/infra/tracer"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreDelByPattern(t *testing.T) {
+	keys := []string{"GET:/users", "GET:/users/1", "GET:/users/2", "GET:/orders", "POST:/users"}
+
+	tests := []struct {
+		name     string
+		pattern  string
+		want     int
+		wantKeys []string
+	}{
+		{"exact key", "GET:/orders", 1, []string{"GET:/users", "GET:/users/1", "GET:/users/2", "POST:/users"}},
+		{"star", "GET:/users*", 3, []string{"GET:/orders", "POST:/users"}},
+		{"question mark", "GET:/users/?", 2, []string{"GET:/orders", "GET:/users", "POST:/users"}},
+		{"regex characters are literal", "GET:/users.*", 0, keys},
+		{"no match", "DELETE:*", 0, keys},
+		{"everything", "*", len(keys), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newTestMemoryStore(t, keys)
+
+			got, err := store.DelByPattern(context.Background(), tt.pattern)
+			if err != nil {
+				t.Fatalf("DelByPattern() err = %v, want nil", err)
+			} else if got != tt.want {
+				t.Errorf("DelByPattern() = %d, want %d", got, tt.want)
+			}
+
+			remaining := store.ttlCache.GetKeys()
+			slices.Sort(remaining)
+			wantKeys := slices.Clone(tt.wantKeys)
+			slices.Sort(wantKeys)
+			if !slices.Equal(remaining, wantKeys) {
+				t.Errorf("remaining keys = %v, want %v", remaining, wantKeys)
+			}
+		})
+	}
+}
+
+func newTestMemoryStore(t *testing.T, keys []string) *memoryStore {
+	store := NewMemoryStore(tracer.NewNoop()).(*memoryStore)
+	for _, key := range keys {
+		if err := store.ttlCache.SetWithTTL(key, "value", time.Minute); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return store
+}
@@ -24,59 +24,72 @@ import (
 	"github.com/tech4works/converter"
 	"github.com/tech4works/decompressor"
 	"github.com/tech4works/errors"
+	"github.com/tech4works/gopen-gateway/internal/app"
 	"github.com/tech4works/gopen-gateway/internal/domain"
 	"github.com/tech4works/gopen-gateway/internal/domain/mapper"
 	"github.com/tech4works/gopen-gateway/internal/domain/model/vo"
-	"go.elastic.co/apm/v2"
+	"strings"
 )
 
 type redisStore struct {
 	client *redis.Client
+	tracer app.Tracer
 }
 
-func NewRedisStore(address, password string) domain.Store {
+func NewRedisStore(address, password string, tracer app.Tracer) domain.Store {
 	return &redisStore{
 		client: redis.NewClient(&redis.Options{
 			Addr:     address,
 			Password: password,
 		}),
+		tracer: tracer,
 	}
 }
 
 func (r redisStore) Set(ctx context.Context, key string, cacheResponse *vo.CacheResponse) error {
-	span, _ := apm.StartSpan(ctx, "Write", "cache")
-	if checker.NonNil(span) {
-		span.Context.SetLabel("cache", "GLOBAL")
-		span.Context.SetLabel("key", key)
-
-		defer span.End()
-	}
+	ctx, span := r.tracer.StartSpan(ctx, "Write", "cache")
+	span.SetLabel("cache", "GLOBAL")
+	span.SetLabel("key", key)
+	defer span.End()
 
 	b64, err := compressor.ToGzipBase64WithErr(cacheResponse)
 	if checker.NonNil(err) {
 		return err
 	}
 
-	return r.client.Set(ctx, key, b64, cacheResponse.Duration.Time()).Err()
+	return r.client.Set(ctx, r.buildKey(key), b64, cacheResponse.Duration.Time()).Err()
 }
 
 func (r redisStore) Del(ctx context.Context, key string) error {
-	return r.client.Del(ctx, key).Err()
+	return r.client.Del(ctx, r.buildKey(key)).Err()
 }
 
-func (r redisStore) Get(ctx context.Context, key string) (*vo.CacheResponse, error) {
-	span, _ := apm.StartSpan(ctx, "Read", "cache")
-	if checker.NonNil(span) {
-		span.Context.SetLabel("cache", "GLOBAL")
-		span.Context.SetLabel("key", key)
+func (r redisStore) DelByPattern(ctx context.Context, pattern string) (int, error) {
+	// only * and ? are wildcards, the other glob characters of redis are matched literally
+	escaper := strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`)
+	iterator := r.client.Scan(ctx, 0, r.buildKey(escaper.Replace(pattern)), 100).Iterator()
 
-		defer span.End()
+	var keys []string
+	for iterator.Next(ctx) {
+		keys = append(keys, iterator.Val())
+	}
+	if err := iterator.Err(); checker.NonNil(err) {
+		return 0, err
+	} else if checker.IsEmpty(keys) {
+		return 0, nil
 	}
 
-	span.Context.SetLabel("cache", "GLOBAL")
-	span.Context.SetLabel("key", key)
+	count, err := r.client.Del(ctx, keys...).Result()
+	return int(count), err
+}
 
-	cacheGzipBase64, err := r.client.Get(ctx, key).Result()
+func (r redisStore) Get(ctx context.Context, key string) (*vo.CacheResponse, error) {
+	ctx, span := r.tracer.StartSpan(ctx, "Read", "cache")
+	span.SetLabel("cache", "GLOBAL")
+	span.SetLabel("key", key)
+	defer span.End()
+
+	cacheGzipBase64, err := r.client.Get(ctx, r.buildKey(key)).Result()
 	if errors.Is(err, redis.Nil) {
 		return nil, mapper.NewErrCacheNotFound()
 	} else if checker.NonNil(err) {
@@ -97,6 +110,14 @@ func (r redisStore) Get(ctx context.Context, key string) (*vo.CacheResponse, err
 	return &cacheResponse, nil
 }
 
+func (r redisStore) Ping(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
+}
+
 func (r redisStore) Close() error {
 	return r.client.Close()
 }
+
+func (r redisStore) buildKey(key string) string {
+	return "gopen:cache:" + key
+}
@@ -18,35 +18,90 @@ package http
 
 import (
 	"context"
+	"crypto/tls"
 	"github.com/tech4works/checker"
 	"github.com/tech4works/gopen-gateway/internal/app"
 	"github.com/tech4works/gopen-gateway/internal/domain/model/vo"
-	"go.elastic.co/apm/module/apmhttp/v2"
-	"go.elastic.co/apm/v2"
 	"io"
+	gonet "net"
 	net "net/http"
+	"sync"
+	"time"
 )
 
 type client struct {
+	mutex      *sync.Mutex
+	transports map[vo.Transport]*net.Transport
+	tracer     app.Tracer
 }
 
-func NewClient() app.HTTPClient {
-	return client{}
+func NewClient(tracer app.Tracer) app.HTTPClient {
+	return client{
+		mutex:      &sync.Mutex{},
+		transports: map[vo.Transport]*net.Transport{},
+		tracer:     tracer,
+	}
 }
 
-func (c client) MakeRequest(ctx context.Context, request *vo.HTTPBackendRequest) (*net.Response, error) {
+func (c client) MakeRequest(ctx context.Context, transport vo.Transport, request *vo.HTTPBackendRequest) (
+	*net.Response, error) {
 	httpRequest, err := c.buildNetHTTPRequest(ctx, request)
 	if checker.NonNil(err) {
 		return nil, err
 	}
 
-	netClient := &net.Client{}
-	tx := apm.TransactionFromContext(ctx)
-	if checker.NonNil(tx) {
-		netClient.Transport = apmhttp.WrapRoundTripper(net.DefaultTransport)
+	netClient := &net.Client{Transport: c.tracer.RoundTripper(ctx, c.getTransport(transport))}
+	return netClient.Do(httpRequest)
+}
+
+func (c client) CloseIdleConnections() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, netTransport := range c.transports {
+		netTransport.CloseIdleConnections()
 	}
+}
 
-	return netClient.Do(httpRequest)
+func (c client) getTransport(transport vo.Transport) *net.Transport {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	// backends with the same settings share the same pool of connections
+	netTransport, ok := c.transports[transport]
+	if !ok {
+		netTransport = c.buildNetTransport(transport)
+		c.transports[transport] = netTransport
+	}
+	return netTransport
+}
+
+func (c client) buildNetTransport(transport vo.Transport) *net.Transport {
+	keepAlive := 30 * time.Second
+	if !transport.KeepAlive() {
+		keepAlive = -1
+	}
+	dialer := &gonet.Dialer{
+		Timeout:   transport.DialTimeout(),
+		KeepAlive: keepAlive,
+	}
+
+	netTransport := &net.Transport{
+		Proxy:                 net.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     transport.HTTP2(),
+		MaxIdleConnsPerHost:   transport.MaxIdleConnsPerHost(),
+		IdleConnTimeout:       transport.IdleConnTimeout(),
+		TLSHandshakeTimeout:   transport.TLSHandshakeTimeout(),
+		ResponseHeaderTimeout: transport.ResponseHeaderTimeout(),
+		ExpectContinueTimeout: time.Second,
+		DisableKeepAlives:     !transport.KeepAlive(),
+	}
+	if !transport.HTTP2() {
+		// a non-nil empty map disables the automatic HTTP/2 upgrade
+		netTransport.TLSNextProto = map[string]func(string, *tls.Conn) net.RoundTripper{}
+	}
+	return netTransport
 }
 
 func (c client) buildNetHTTPRequest(ctx context.Context, request *vo.HTTPBackendRequest) (*net.Request, error) {
@@ -65,5 +120,5 @@ func (c client) buildNetHTTPRequest(ctx context.Context, request *vo.HTTPBackend
 	netReq.Header = header.Http()
 	netReq.URL.RawQuery = query.Encode()
 
-	return apmhttp.RequestWithContext(ctx, netReq), nil
+	return netReq, nil
 }
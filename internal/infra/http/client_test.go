@@ -0,0 +1,115 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package http
+
+import (
+	"github.com/tech4works/gopen-gateway/internal/domain/model/vo"
+	"github.com/tech4works/gopen-gateway/internal/infra/tracer"
+	"testing"
+	"time"
+)
+
+func TestClientGetTransport(t *testing.T) {
+	tests := []struct {
+		name     string
+		first    vo.Transport
+		second   vo.Transport
+		wantSame bool
+	}{
+		{
+			name:     "zero value",
+			first:    vo.Transport{},
+			second:   vo.Transport{},
+			wantSame: true,
+		},
+		{
+			name:     "same settings",
+			first:    newTestTransport(10, true, false),
+			second:   newTestTransport(10, true, false),
+			wantSame: true,
+		},
+		{
+			name:     "different settings",
+			first:    newTestTransport(10, true, false),
+			second:   newTestTransport(20, true, false),
+			wantSame: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClient(tracer.NewNoop()).(client)
+
+			first := c.getTransport(tt.first)
+			second := c.getTransport(tt.second)
+			if (first == second) != tt.wantSame {
+				t.Errorf("getTransport() same = %t, want %t", first == second, tt.wantSame)
+			}
+		})
+	}
+}
+
+func TestClientBuildNetTransport(t *testing.T) {
+	tests := []struct {
+		name                  string
+		transport             vo.Transport
+		wantMaxIdleConns      int
+		wantIdleConnTimeout   time.Duration
+		wantDisableKeepAlives bool
+		wantHTTP2             bool
+	}{
+		{
+			name:                  "zero value",
+			transport:             vo.Transport{},
+			wantMaxIdleConns:      100,
+			wantIdleConnTimeout:   90 * time.Second,
+			wantDisableKeepAlives: true,
+		},
+		{
+			name:                "keep alive and http2",
+			transport:           newTestTransport(10, true, true),
+			wantMaxIdleConns:    10,
+			wantIdleConnTimeout: 30 * time.Second,
+			wantHTTP2:           true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClient(tracer.NewNoop()).(client)
+
+			got := c.buildNetTransport(tt.transport)
+			if got.MaxIdleConnsPerHost != tt.wantMaxIdleConns {
+				t.Errorf("buildNetTransport() MaxIdleConnsPerHost = %d, want %d", got.MaxIdleConnsPerHost,
+					tt.wantMaxIdleConns)
+			}
+			if got.IdleConnTimeout != tt.wantIdleConnTimeout {
+				t.Errorf("buildNetTransport() IdleConnTimeout = %s, want %s", got.IdleConnTimeout,
+					tt.wantIdleConnTimeout)
+			}
+			if got.DisableKeepAlives != tt.wantDisableKeepAlives {
+				t.Errorf("buildNetTransport() DisableKeepAlives = %t, want %t", got.DisableKeepAlives,
+					tt.wantDisableKeepAlives)
+			}
+			if got.ForceAttemptHTTP2 != tt.wantHTTP2 || (got.TLSNextProto == nil) != tt.wantHTTP2 {
+				t.Errorf("buildNetTransport() http2 = %t, want %t", got.ForceAttemptHTTP2, tt.wantHTTP2)
+			}
+		})
+	}
+}
+
+func newTestTransport(maxIdleConnsPerHost int, keepAlive, http2 bool) vo.Transport {
+	return vo.NewTransport(maxIdleConnsPerHost, vo.NewDuration(30*time.Second), 0, 0, 0, keepAlive, http2)
+}
@@ -0,0 +1,62 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwt
+
+import (
+	"encoding/json"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/tech4works/checker"
+	"github.com/tech4works/errors"
+	"github.com/tech4works/gopen-gateway/internal/domain"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/vo"
+)
+
+type provider struct {
+}
+
+func New() domain.JWT {
+	return provider{}
+}
+
+func (p provider) Verify(token string, config *vo.JWT) (string, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (any, error) {
+		return p.findKey(token, config.Keys())
+	}, jwt.WithValidMethods(config.Algorithms()), jwt.WithoutClaimsValidation())
+	if checker.NonNil(err) {
+		return "", err
+	}
+
+	bs, err := json.Marshal(claims)
+	if checker.NonNil(err) {
+		return "", err
+	}
+	return string(bs), nil
+}
+
+func (p provider) findKey(token *jwt.Token, keys []vo.JWTKey) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	for _, key := range keys {
+		if checker.IsNotEmpty(kid) && checker.IsNotEmpty(key.ID()) && checker.NotEquals(kid, key.ID()) {
+			continue
+		}
+		if key.IsCompatible(token.Method.Alg()) {
+			return key.Value(), nil
+		}
+	}
+	return nil, errors.Newf("No jwt key found for alg %s and kid %s!", token.Method.Alg(), kid)
+}
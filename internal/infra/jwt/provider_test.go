@@ -0,0 +1,129 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/vo"
+	"testing"
+)
+
+func TestProviderVerify(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	otherRSAKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	secretKey := vo.NewJWTKey("", []byte("s3cret"))
+	rsaPublicKey := vo.NewJWTKey("", &rsaKey.PublicKey)
+	jwksKeys := []vo.JWTKey{vo.NewJWTKey("key-1", &rsaKey.PublicKey), vo.NewJWTKey("key-2", &otherRSAKey.PublicKey)}
+
+	tests := []struct {
+		name    string
+		config  *vo.JWT
+		token   string
+		wantErr bool
+	}{
+		{
+			name:   "hs256 with secret",
+			config: newTestJWT(nil, secretKey),
+			token:  newTestToken(jwt.SigningMethodHS256, "", []byte("s3cret")),
+		},
+		{
+			name:    "hs256 with another secret",
+			config:  newTestJWT(nil, secretKey),
+			token:   newTestToken(jwt.SigningMethodHS256, "", []byte("other")),
+			wantErr: true,
+		},
+		{
+			name:   "rs256 with pem",
+			config: newTestJWT(nil, rsaPublicKey),
+			token:  newTestToken(jwt.SigningMethodRS256, "", rsaKey),
+		},
+		{
+			name:   "es256 with pem",
+			config: newTestJWT(nil, vo.NewJWTKey("", &ecKey.PublicKey)),
+			token:  newTestToken(jwt.SigningMethodES256, "", ecKey),
+		},
+		{
+			name:    "hs256 against a rsa key set",
+			config:  newTestJWT([]string{"HS256", "RS256"}, rsaPublicKey),
+			token:   newTestToken(jwt.SigningMethodHS256, "", []byte("s3cret")),
+			wantErr: true,
+		},
+		{
+			name:    "none algorithm",
+			config:  newTestJWT(nil, secretKey),
+			token:   newTestToken(jwt.SigningMethodNone, "", jwt.UnsafeAllowNoneSignatureType),
+			wantErr: true,
+		},
+		{
+			name:    "algorithm not configured",
+			config:  newTestJWT([]string{"RS256"}, secretKey, rsaPublicKey),
+			token:   newTestToken(jwt.SigningMethodHS256, "", []byte("s3cret")),
+			wantErr: true,
+		},
+		{
+			name:   "jwks with kid",
+			config: newTestJWT(nil, jwksKeys...),
+			token:  newTestToken(jwt.SigningMethodRS256, "key-1", rsaKey),
+		},
+		{
+			name:    "jwks with another kid",
+			config:  newTestJWT(nil, jwksKeys...),
+			token:   newTestToken(jwt.SigningMethodRS256, "key-2", rsaKey),
+			wantErr: true,
+		},
+		{
+			name:    "jwks with unknown kid",
+			config:  newTestJWT(nil, jwksKeys...),
+			token:   newTestToken(jwt.SigningMethodRS256, "key-3", rsaKey),
+			wantErr: true,
+		},
+		{
+			name:    "malformed token",
+			config:  newTestJWT(nil, secretKey),
+			token:   "not.a.token",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := New().Verify(tt.token, tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Verify() err = %v, wantErr %v", err, tt.wantErr)
+			} else if !tt.wantErr && claims != `{"sub":"user-1"}` {
+				t.Errorf("Verify() = %s, want %s", claims, `{"sub":"user-1"}`)
+			}
+		})
+	}
+}
+
+func newTestJWT(algorithms []string, keys ...vo.JWTKey) *vo.JWT {
+	return vo.NewJWT(algorithms, keys, "", nil, nil, 0)
+}
+
+func newTestToken(method jwt.SigningMethod, kid string, signKey any) string {
+	token := jwt.NewWithClaims(method, jwt.MapClaims{"sub": "user-1"})
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, _ := token.SignedString(signKey)
+	return signed
+}
@@ -0,0 +1,190 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package limiter
+
+import (
+	"container/list"
+	"context"
+	"github.com/tech4works/checker"
+	"github.com/tech4works/gopen-gateway/internal/domain"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/vo"
+	timerate "golang.org/x/time/rate"
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const memoryShards = 32
+
+type memoryStore struct {
+	shards     []*memoryShard
+	ttl        time.Duration
+	maxEntries int
+	evictions  *atomic.Uint64
+	done       chan struct{}
+	closeOnce  *sync.Once
+}
+
+type memoryShard struct {
+	mutex    *sync.Mutex
+	buckets  map[string]*list.Element
+	lruOrder *list.List
+}
+
+type memoryBucket struct {
+	key      string
+	limiter  *timerate.Limiter
+	lastSeen time.Time
+}
+
+func NewMemoryStore(ttl time.Duration, maxEntries int) domain.LocalLimiterStore {
+	if checker.IsLessThanOrEqual(ttl, 0) {
+		ttl = 10 * time.Minute
+	}
+	if checker.IsLessThanOrEqual(maxEntries, 0) {
+		maxEntries = 100000
+	}
+
+	shards := make([]*memoryShard, memoryShards)
+	for i := range shards {
+		shards[i] = &memoryShard{
+			mutex:    &sync.Mutex{},
+			buckets:  map[string]*list.Element{},
+			lruOrder: list.New(),
+		}
+	}
+
+	store := &memoryStore{
+		shards:     shards,
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		evictions:  &atomic.Uint64{},
+		done:       make(chan struct{}),
+		closeOnce:  &sync.Once{},
+	}
+	go store.evictIdleLoop()
+
+	return store
+}
+
+func (m *memoryStore) Allow(_ context.Context, key string, rate vo.Rate) (*vo.RateState, error) {
+	shard := m.findShard(key)
+
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+
+	now := time.Now()
+
+	var bucket *memoryBucket
+	if element, ok := shard.buckets[key]; ok {
+		bucket = element.Value.(*memoryBucket)
+		shard.lruOrder.MoveToFront(element)
+	} else {
+		bucket = &memoryBucket{
+			key:     key,
+			limiter: timerate.NewLimiter(timerate.Every(rate.EveryTime()), rate.Capacity()),
+		}
+		shard.buckets[key] = shard.lruOrder.PushFront(bucket)
+		m.evictOverflow(shard)
+	}
+	bucket.lastSeen = now
+
+	return vo.NewRateState(rate, bucket.limiter.AllowN(now, 1), bucket.limiter.TokensAt(now)), nil
+}
+
+func (m *memoryStore) Stats() vo.LimiterStats {
+	buckets := 0
+	for _, shard := range m.shards {
+		shard.mutex.Lock()
+		buckets += len(shard.buckets)
+		shard.mutex.Unlock()
+	}
+	return vo.NewLimiterStats(buckets, m.evictions.Load())
+}
+
+func (m *memoryStore) Reset(_ context.Context) (int, error) {
+	count := 0
+	for _, shard := range m.shards {
+		shard.mutex.Lock()
+		count += len(shard.buckets)
+		shard.buckets = map[string]*list.Element{}
+		shard.lruOrder.Init()
+		shard.mutex.Unlock()
+	}
+	return count, nil
+}
+
+func (m *memoryStore) Ping(ctx context.Context) error {
+	return nil
+}
+
+func (m *memoryStore) Close() error {
+	m.closeOnce.Do(func() {
+		close(m.done)
+	})
+	return nil
+}
+
+func (m *memoryStore) findShard(key string) *memoryShard {
+	hash := fnv.New32a()
+	hash.Write([]byte(key))
+	return m.shards[hash.Sum32()%memoryShards]
+}
+
+func (m *memoryStore) evictOverflow(shard *memoryShard) {
+	// the cap is split evenly between the shards, the least recently used buckets leave first
+	maxShardEntries := max(1, m.maxEntries/memoryShards)
+	for checker.IsGreaterThan(shard.lruOrder.Len(), maxShardEntries) {
+		m.remove(shard, shard.lruOrder.Back())
+	}
+}
+
+func (m *memoryStore) evictIdleLoop() {
+	ticker := time.NewTicker(max(time.Second, m.ttl/2))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.done:
+			return
+		case <-ticker.C:
+			m.evictIdle()
+		}
+	}
+}
+
+func (m *memoryStore) evictIdle() {
+	deadline := time.Now().Add(-m.ttl)
+	for _, shard := range m.shards {
+		shard.mutex.Lock()
+		// the list is ordered by the last access, so we stop at the first bucket that is still in use
+		for element := shard.lruOrder.Back(); checker.NonNil(element); element = shard.lruOrder.Back() {
+			if element.Value.(*memoryBucket).lastSeen.After(deadline) {
+				break
+			}
+			m.remove(shard, element)
+		}
+		shard.mutex.Unlock()
+	}
+}
+
+func (m *memoryStore) remove(shard *memoryShard, element *list.Element) {
+	shard.lruOrder.Remove(element)
+	delete(shard.buckets, element.Value.(*memoryBucket).key)
+	m.evictions.Add(1)
+}
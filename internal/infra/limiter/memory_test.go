@@ -0,0 +1,158 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package limiter
+
+import (
+	"context"
+	"fmt"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/vo"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreAllow(t *testing.T) {
+	tests := []struct {
+		name          string
+		rate          vo.Rate
+		wantAllowed   []bool
+		wantRemaining []int
+	}{
+		{
+			name:          "allows up to the capacity",
+			rate:          newTestRate(time.Minute, 2),
+			wantAllowed:   []bool{true, true, false},
+			wantRemaining: []int{1, 0, 0},
+		},
+		{
+			name:          "capacity of one",
+			rate:          newTestRate(time.Minute, 1),
+			wantAllowed:   []bool{true, false, false},
+			wantRemaining: []int{0, 0, 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewMemoryStore(0, 0)
+			defer store.Close()
+
+			for i := range tt.wantAllowed {
+				state, err := store.Allow(context.Background(), "key", tt.rate)
+				if err != nil {
+					t.Fatalf("Allow() #%d err = %v", i, err)
+				} else if state.Allowed() != tt.wantAllowed[i] {
+					t.Fatalf("Allow() #%d allowed = %v, want %v", i, state.Allowed(), tt.wantAllowed[i])
+				} else if state.Remaining() != tt.wantRemaining[i] {
+					t.Errorf("Allow() #%d remaining = %d, want %d", i, state.Remaining(), tt.wantRemaining[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryStoreEvictOverflow(t *testing.T) {
+	tests := []struct {
+		name          string
+		maxEntries    int
+		keysPerShard  int
+		wantBuckets   int
+		wantEvictions uint64
+	}{
+		{
+			name:         "within the cap",
+			maxEntries:   memoryShards * 4,
+			keysPerShard: 2,
+			wantBuckets:  memoryShards * 2,
+		},
+		{
+			name:          "over the cap",
+			maxEntries:    memoryShards,
+			keysPerShard:  4,
+			wantBuckets:   memoryShards,
+			wantEvictions: memoryShards * 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewMemoryStore(0, tt.maxEntries).(*memoryStore)
+			defer store.Close()
+
+			// the cap is applied per shard, so every shard receives the same number of keys
+			for shard := range store.shards {
+				for i := 0; i < tt.keysPerShard; i++ {
+					store.Allow(context.Background(), newTestShardKey(store, shard, i), newTestRate(time.Minute, 1))
+				}
+			}
+
+			stats := store.Stats()
+			if stats.Buckets() != tt.wantBuckets {
+				t.Errorf("Stats() buckets = %d, want %d", stats.Buckets(), tt.wantBuckets)
+			} else if stats.Evictions() != tt.wantEvictions {
+				t.Errorf("Stats() evictions = %d, want %d", stats.Evictions(), tt.wantEvictions)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreEvictOverflowKeepsRecentlyUsed(t *testing.T) {
+	store := NewMemoryStore(0, memoryShards*2).(*memoryStore)
+	defer store.Close()
+
+	rate := newTestRate(time.Minute, 1)
+	first, second, third := newTestShardKey(store, 0, 0), newTestShardKey(store, 0, 1), newTestShardKey(store, 0, 2)
+	store.Allow(context.Background(), first, rate)
+	store.Allow(context.Background(), second, rate)
+	// the first key is used again, so the second one is the least recently used when the third arrives
+	store.Allow(context.Background(), first, rate)
+	store.Allow(context.Background(), third, rate)
+
+	shard := store.shards[0]
+	if _, ok := shard.buckets[first]; !ok {
+		t.Errorf("evictOverflow() removed the recently used key %s", first)
+	} else if _, ok = shard.buckets[second]; ok {
+		t.Errorf("evictOverflow() kept the least recently used key %s", second)
+	}
+}
+
+func TestMemoryStoreEvictIdle(t *testing.T) {
+	store := NewMemoryStore(50*time.Millisecond, 0).(*memoryStore)
+	defer store.Close()
+
+	rate := newTestRate(time.Minute, 1)
+	store.Allow(context.Background(), "idle", rate)
+	time.Sleep(100 * time.Millisecond)
+	store.Allow(context.Background(), "active", rate)
+
+	store.evictIdle()
+
+	if stats := store.Stats(); stats.Buckets() != 1 || stats.Evictions() != 1 {
+		t.Fatalf("evictIdle() buckets = %d, evictions = %d, want 1 and 1", stats.Buckets(), stats.Evictions())
+	}
+	// an evicted bucket starts full again
+	state, _ := store.Allow(context.Background(), "idle", rate)
+	if !state.Allowed() {
+		t.Errorf("Allow() after eviction allowed = false, want true")
+	}
+}
+
+func newTestShardKey(store *memoryStore, shard, index int) string {
+	for i := 0; ; i++ {
+		key := fmt.Sprintf("key-%d-%d-%d", shard, index, i)
+		if store.findShard(key) == store.shards[shard] {
+			return key
+		}
+	}
+}
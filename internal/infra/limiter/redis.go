@@ -0,0 +1,151 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package limiter
+
+import (
+	"context"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"github.com/tech4works/checker"
+	"github.com/tech4works/errors"
+	"github.com/tech4works/gopen-gateway/internal/app"
+	"github.com/tech4works/gopen-gateway/internal/domain"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/vo"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// tokenBucketScript refills one token every interval up to the capacity and takes one token when available, the
+// redis clock is used so that every gateway replica shares the same time reference, the tokens are returned as text
+// because redis truncates lua numbers to integers
+var tokenBucketScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local interval = tonumber(ARGV[2])
+if interval <= 0 then
+	return {1, tostring(capacity)}
+end
+
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'timestamp')
+local tokens = tonumber(state[1]) or capacity
+local timestamp = tonumber(state[2]) or now
+
+tokens = math.min(capacity, tokens + math.max(0, now - timestamp) / interval)
+
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'timestamp', now)
+redis.call('PEXPIRE', KEYS[1], math.max(1, math.ceil(capacity * interval)))
+
+return {allowed, tostring(tokens)}
+`)
+
+type redisStore struct {
+	client           redis.UniversalClient
+	failureCooldown  time.Duration
+	unavailableUntil *atomic.Int64
+	tracer           app.Tracer
+}
+
+func NewRedisStore(address, password string, tracer app.Tracer) domain.LimiterStore {
+	// the limiter is called on every request, so a slow or unavailable redis must fail fast to the local limiter
+	return NewRedisStoreByClient(redis.NewClient(&redis.Options{
+		Addr:         address,
+		Password:     password,
+		DialTimeout:  500 * time.Millisecond,
+		ReadTimeout:  200 * time.Millisecond,
+		WriteTimeout: 200 * time.Millisecond,
+		MaxRetries:   -1,
+	}), 5*time.Second, tracer)
+}
+
+func NewRedisStoreByClient(client redis.UniversalClient, failureCooldown time.Duration, tracer app.Tracer,
+) domain.LimiterStore {
+	return &redisStore{
+		client:           client,
+		failureCooldown:  failureCooldown,
+		unavailableUntil: &atomic.Int64{},
+		tracer:           tracer,
+	}
+}
+
+func (r redisStore) Allow(ctx context.Context, key string, rate vo.Rate) (*vo.RateState, error) {
+	// after a failure redis is skipped during the cooldown, otherwise every request would wait for the timeouts
+	// of an unavailable redis before falling back to the local limiter
+	if checker.IsGreaterThan(r.unavailableUntil.Load(), time.Now().UnixNano()) {
+		return nil, errors.Newf("Redis skipped for %s after a failure!", r.failureCooldown)
+	}
+
+	ctx, span := r.tracer.StartSpan(ctx, "Allow", "limiter")
+	span.SetLabel("key", key)
+	defer span.End()
+
+	interval := float64(rate.EveryTime()) / float64(time.Millisecond)
+	result, err := tokenBucketScript.Run(ctx, r.client, []string{r.buildKey(key)}, rate.Capacity(), interval).Slice()
+	if checker.NonNil(err) {
+		// a request canceled by the client says nothing about redis
+		if checker.IsNil(ctx.Err()) {
+			r.unavailableUntil.Store(time.Now().Add(r.failureCooldown).UnixNano())
+		}
+		return nil, err
+	} else if !checker.IsLengthEquals(result, 2) {
+		return nil, errors.Newf("Unexpected limiter script result: %v", result)
+	}
+
+	allowed, _ := result[0].(int64)
+	tokens, err := strconv.ParseFloat(fmt.Sprint(result[1]), 64)
+	if checker.NonNil(err) {
+		return nil, err
+	}
+	return vo.NewRateState(rate, checker.Equals(allowed, int64(1)), tokens), nil
+}
+
+func (r redisStore) Reset(ctx context.Context) (int, error) {
+	iterator := r.client.Scan(ctx, 0, r.buildKey("*"), 100).Iterator()
+
+	var keys []string
+	for iterator.Next(ctx) {
+		keys = append(keys, iterator.Val())
+	}
+	if err := iterator.Err(); checker.NonNil(err) {
+		return 0, err
+	} else if checker.IsEmpty(keys) {
+		return 0, nil
+	}
+
+	count, err := r.client.Del(ctx, keys...).Result()
+	return int(count), err
+}
+
+func (r redisStore) Ping(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
+}
+
+func (r redisStore) Close() error {
+	return r.client.Close()
+}
+
+func (r redisStore) buildKey(key string) string {
+	return "gopen:limiter:" + key
+}
@@ -0,0 +1,146 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package limiter
+
+import (
+	"context"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/tech4works/errors"
+	"github.com/tech4works/gopen-gateway/internal/domain/mapper"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/enum"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/vo"
+	"github.com/tech4works/gopen-gateway/internal/domain/service"
+	"github.com/tech4works/gopen-gateway/internal/infra/tracer"
+	"testing"
+	"time"
+)
+
+func TestRedisStoreAllow(t *testing.T) {
+	tests := []struct {
+		name    string
+		rate    vo.Rate
+		advance time.Duration
+		want    []bool
+	}{
+		{
+			name: "allows up to the capacity and denies after it",
+			rate: newTestRate(time.Second, 2),
+			want: []bool{true, true, false, false},
+		},
+		{
+			name:    "refills one token every interval",
+			rate:    newTestRate(time.Second, 1),
+			advance: time.Second,
+			want:    []bool{true, true, true},
+		},
+		{
+			name:    "doesn't refill before the interval",
+			rate:    newTestRate(time.Second, 1),
+			advance: 500 * time.Millisecond,
+			want:    []bool{true, false, true},
+		},
+		{
+			name: "allows everything without an interval",
+			rate: newTestRate(0, 1),
+			want: []bool{true, true, true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := miniredis.RunT(t)
+			now := time.Now()
+			server.SetTime(now)
+
+			store := NewRedisStoreByClient(redis.NewClient(&redis.Options{Addr: server.Addr()}), 0, tracer.NewNoop())
+			for i, want := range tt.want {
+				state, err := store.Allow(context.Background(), "key", tt.rate)
+				if err != nil {
+					t.Fatalf("Allow() #%d err = %v", i, err)
+				} else if state.Allowed() != want {
+					t.Fatalf("Allow() #%d allowed = %v, want %v", i, state.Allowed(), want)
+				}
+				now = now.Add(tt.advance)
+				server.SetTime(now)
+			}
+		})
+	}
+}
+
+func TestRedisStoreAllowFallbackToLocal(t *testing.T) {
+	server := miniredis.RunT(t)
+	address := server.Addr()
+	server.Close()
+
+	limiterService := service.NewLimiter(NewRedisStore(address, "", tracer.NewNoop()), NewMemoryStore(0, 0), nil)
+	endpoint := vo.NewEndpoint("/users", "GET", 0, vo.NewLimiter(0, 0, 0, newTestRate(time.Second, 1), nil), nil,
+		nil, nil, nil, nil, nil, nil)
+	request := vo.NewHTTPRequest(vo.NewURLPath("/users", nil), "/users", "GET",
+		vo.NewHeader(map[string][]string{mapper.XForwardedFor: {"127.0.0.1"}}), vo.NewEmptyQuery(), nil)
+
+	_, err := limiterService.AllowRate(context.Background(), &endpoint, request)
+	if !errors.Is(err, mapper.ErrLimiterStore) {
+		t.Fatalf("AllowRate() err = %v, want %v", err, mapper.ErrLimiterStore)
+	}
+
+	state, err := limiterService.AllowRateLocal(&endpoint, request)
+	if err != nil || !state.Allowed() {
+		t.Fatalf("AllowRateLocal() = %v, %v, want allowed", state, err)
+	}
+	_, err = limiterService.AllowRateLocal(&endpoint, request)
+	if !errors.Contains(err, mapper.ErrTooManyRequests) {
+		t.Fatalf("AllowRateLocal() err = %v, want %v", err, mapper.ErrTooManyRequests)
+	}
+}
+
+func TestRedisStoreAllowFailureCooldown(t *testing.T) {
+	tests := []struct {
+		name     string
+		cooldown time.Duration
+		wait     time.Duration
+		wantErr  bool
+	}{
+		{"skipped during the cooldown", time.Minute, 0, true},
+		{"used again after the cooldown", 50 * time.Millisecond, 100 * time.Millisecond, false},
+		{"without cooldown", 0, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := miniredis.RunT(t)
+			store := NewRedisStoreByClient(redis.NewClient(&redis.Options{Addr: server.Addr()}), tt.cooldown,
+				tracer.NewNoop())
+
+			server.SetError("LOADING Redis is loading the dataset in memory")
+			if _, err := store.Allow(context.Background(), "key", newTestRate(time.Second, 1)); err == nil {
+				t.Fatalf("Allow() with redis failing err = nil, want an error")
+			}
+			server.SetError("")
+			time.Sleep(tt.wait)
+
+			_, err := store.Allow(context.Background(), "key", newTestRate(time.Second, 1))
+			if tt.wantErr && err == nil {
+				t.Errorf("Allow() after the failure err = nil, want redis skipped")
+			} else if !tt.wantErr && err != nil {
+				t.Errorf("Allow() after the failure err = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func newTestRate(every time.Duration, capacity int) vo.Rate {
+	return vo.NewRate(vo.NewDuration(every), capacity, enum.RateScopeGlobal, vo.NewRateKey(nil, "", ""), false)
+}
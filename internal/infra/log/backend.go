@@ -40,7 +40,7 @@ func (b backendLog) PrintRequest(executeData dto.ExecuteEndpoint, backend *vo.Ba
 		text += fmt.Sprintf(" | body.content-type: %s | body.size: %s", body.ContentType().String(), body.SizeInByteUnit())
 	}
 
-	Printf(InfoLevel, backend.Type().Abbreviation(), b.prefix(executeData, backend, request), text)
+	Printf(InfoLevel, backend.Type().Abbreviation(), b.fields(executeData, backend, request), text)
 }
 
 func (b backendLog) PrintResponse(executeData dto.ExecuteEndpoint, backend *vo.Backend, request *vo.HTTPBackendRequest,
@@ -49,50 +49,52 @@ func (b backendLog) PrintResponse(executeData dto.ExecuteEndpoint, backend *vo.B
 		return
 	}
 
-	statusCode := response.StatusCode()
-	statusCodeText := BuildStatusCodeText(statusCode)
+	fields := b.fields(executeData, backend, request)
+	fields.Status = response.StatusCode().Code()
+	fields.Duration = duration
 
-	Printf(InfoLevel, backend.Type().Abbreviation(), b.prefix(executeData, backend, request),
-		"RES status-code:%v| duration: %vms", statusCodeText, duration.Milliseconds())
+	Print(InfoLevel, backend.Type().Abbreviation(), fields, "RES")
 }
 
 func (b backendLog) PrintInfof(executeData dto.ExecuteEndpoint, backend *vo.Backend, request *vo.HTTPBackendRequest,
 	format string, msg ...any) {
-	Printf(InfoLevel, backend.Type().Abbreviation(), b.prefix(executeData, backend, request), format, msg...)
+	Printf(InfoLevel, backend.Type().Abbreviation(), b.fields(executeData, backend, request), format, msg...)
 }
 
 func (b backendLog) PrintInfo(executeData dto.ExecuteEndpoint, backend *vo.Backend, request *vo.HTTPBackendRequest,
 	msg ...any) {
-	Print(InfoLevel, backend.Type().Abbreviation(), b.prefix(executeData, backend, request), msg...)
+	Print(InfoLevel, backend.Type().Abbreviation(), b.fields(executeData, backend, request), msg...)
 }
 
 func (b backendLog) PrintWarnf(executeData dto.ExecuteEndpoint, backend *vo.Backend, request *vo.HTTPBackendRequest,
 	format string, msg ...any) {
-	Printf(WarnLevel, backend.Type().Abbreviation(), b.prefix(executeData, backend, request), format, msg...)
+	Printf(WarnLevel, backend.Type().Abbreviation(), b.fields(executeData, backend, request), format, msg...)
 }
 
 func (b backendLog) PrintWarn(executeData dto.ExecuteEndpoint, backend *vo.Backend, request *vo.HTTPBackendRequest,
 	msg ...any) {
-	Print(WarnLevel, backend.Type().Abbreviation(), b.prefix(executeData, backend, request), msg...)
+	Print(WarnLevel, backend.Type().Abbreviation(), b.fields(executeData, backend, request), msg...)
 }
 
 func (b backendLog) PrintErrorf(executeData dto.ExecuteEndpoint, backend *vo.Backend, request *vo.HTTPBackendRequest,
 	format string, msg ...any) {
-	Printf(ErrorLevel, backend.Type().Abbreviation(), b.prefix(executeData, backend, request), format, msg...)
+	Printf(ErrorLevel, backend.Type().Abbreviation(), b.fields(executeData, backend, request), format, msg...)
 }
 
 func (b backendLog) PrintError(executeData dto.ExecuteEndpoint, backend *vo.Backend, request *vo.HTTPBackendRequest,
 	msg ...any) {
-	Print(ErrorLevel, backend.Type().Abbreviation(), b.prefix(executeData, backend, request), msg...)
+	Print(ErrorLevel, backend.Type().Abbreviation(), b.fields(executeData, backend, request), msg...)
 }
 
-func (b backendLog) prefix(executeData dto.ExecuteEndpoint, backend *vo.Backend, request *vo.HTTPBackendRequest) string {
-	path := backend.Path()
-	traceID := BuildTraceIDText(executeData.TraceID)
-	ip := executeData.ClientIP
-
-	method := BuildMethodText(request.Method())
-	url := BuildUriText(request.FullPath())
-
-	return fmt.Sprintf("[%s | %s | %s |%s| %s]", path, ip, traceID, method, url)
+func (b backendLog) fields(executeData dto.ExecuteEndpoint, backend *vo.Backend, request *vo.HTTPBackendRequest,
+) Fields {
+	return Fields{
+		TraceID:   executeData.TraceID,
+		RequestID: executeData.Request.RequestID(),
+		ClientIP:  executeData.ClientIP,
+		Endpoint:  executeData.Endpoint.Path(),
+		Backend:   backend.Path(),
+		Method:    request.Method(),
+		URL:       request.FullPath(),
+	}
 }
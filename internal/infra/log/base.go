@@ -17,28 +17,130 @@
 package log
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/tech4works/checker"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/enum"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/vo"
+	"regexp"
+	"strings"
+	"time"
 )
 
-func Print(lvl level, tag, prefix string, msg ...any) {
-	tagText := BuildTagText(tag)
-	levelText := BuildLevelText(lvl)
+var styleRegex = regexp.MustCompile(`\x1b\[[0-9;]*m`)
 
-	if checker.IsNotEmpty(prefix) {
-		fmt.Printf("[%s] %s %s %s", tagText, levelText, prefix, fmt.Sprintln(msg...))
+type Fields struct {
+	TraceID   string
+	RequestID string
+	ClientIP  string
+	Endpoint  string
+	Backend   string
+	Method    string
+	URL       string
+	Status    int
+	Duration  time.Duration
+	Consumer  string
+}
+
+type entry struct {
+	Time       time.Time `json:"time"`
+	Level      string    `json:"level"`
+	Tag        string    `json:"tag"`
+	TraceID    string    `json:"trace-id,omitempty"`
+	RequestID  string    `json:"request-id,omitempty"`
+	ClientIP   string    `json:"client-ip,omitempty"`
+	Endpoint   string    `json:"endpoint,omitempty"`
+	Backend    string    `json:"backend,omitempty"`
+	Method     string    `json:"method,omitempty"`
+	URL        string    `json:"url,omitempty"`
+	Status     int       `json:"status,omitempty"`
+	DurationMs *int64    `json:"duration-ms,omitempty"`
+	Consumer   string    `json:"consumer,omitempty"`
+	Message    string    `json:"message,omitempty"`
+}
+
+func Print(lvl level, tag string, fields Fields, msg ...any) {
+	write(lvl, tag, fields, strings.TrimSuffix(fmt.Sprintln(msg...), "\n"))
+}
+
+func Printf(lvl level, tag string, fields Fields, format string, msg ...any) {
+	write(lvl, tag, fields, fmt.Sprintf(format, msg...))
+}
+
+func write(lvl level, tag string, fields Fields, message string) {
+	config := currentConfig()
+	if !lvl.IsEnabled(config.level) {
+		return
+	}
+
+	var line string
+	if checker.Equals(config.format, enum.LogFormatJSON) {
+		line = buildJSONLine(lvl, tag, fields, message)
 	} else {
-		fmt.Printf("[%s] %s %s", tagText, levelText, fmt.Sprintln(msg...))
+		line = buildTextLine(lvl, tag, fields, message)
 	}
+	config.writer.Write([]byte(line))
 }
 
-func Printf(lvl level, tag, prefix, format string, msg ...any) {
-	tagText := BuildTagText(tag)
-	levelText := BuildLevelText(lvl)
+func buildTextLine(lvl level, tag string, fields Fields, message string) string {
+	text := message
+	if checker.IsGreaterThan(fields.Status, 0) {
+		statusCodeText := BuildStatusCodeText(vo.NewStatusCode(fields.Status))
+		result := fmt.Sprintf("status-code:%s| duration: %vms", statusCodeText, fields.Duration.Milliseconds())
+		text = strings.TrimSpace(fmt.Sprint(text, " ", result))
+	}
+	if checker.IsNotEmpty(fields.Consumer) {
+		text += fmt.Sprintf(" | consumer: %s", fields.Consumer)
+	}
 
+	prefix := buildTextPrefix(fields)
 	if checker.IsNotEmpty(prefix) {
-		fmt.Printf("[%s] %s %s %s\n", tagText, levelText, prefix, fmt.Sprintf(format, msg...))
-	} else {
-		fmt.Printf("[%s] %s %s\n", tagText, levelText, fmt.Sprintf(format, msg...))
+		return fmt.Sprintf("[%s] %s %s %s\n", BuildTagText(tag), BuildLevelText(lvl), prefix, text)
+	}
+	return fmt.Sprintf("[%s] %s %s\n", BuildTagText(tag), BuildLevelText(lvl), text)
+}
+
+func buildTextPrefix(fields Fields) string {
+	path := fields.Endpoint
+	if checker.IsNotEmpty(fields.Backend) {
+		path = fields.Backend
+	}
+	if checker.IsEmpty(path) {
+		return ""
+	}
+
+	traceID := BuildTraceIDText(fields.TraceID)
+	method := BuildMethodText(fields.Method)
+	url := BuildUriText(fields.URL)
+
+	return fmt.Sprintf("[%s | %s | %s |%s| %s]", path, fields.ClientIP, traceID, method, url)
+}
+
+func buildJSONLine(lvl level, tag string, fields Fields, message string) string {
+	logEntry := entry{
+		Time:      time.Now(),
+		Level:     lvl.Name(),
+		Tag:       tag,
+		TraceID:   fields.TraceID,
+		RequestID: fields.RequestID,
+		ClientIP:  fields.ClientIP,
+		Endpoint:  fields.Endpoint,
+		Backend:   fields.Backend,
+		Method:    fields.Method,
+		URL:       fields.URL,
+		Status:    fields.Status,
+		Consumer:  fields.Consumer,
+		// the styles are only meaningful on a terminal, they would pollute the parsed message
+		Message: strings.TrimSpace(styleRegex.ReplaceAllString(message, "")),
+	}
+	if checker.IsGreaterThan(fields.Status, 0) {
+		durationMs := fields.Duration.Milliseconds()
+		logEntry.DurationMs = &durationMs
+	}
+
+	bytes, err := json.Marshal(logEntry)
+	if checker.NonNil(err) {
+		return fmt.Sprintf("{\"level\":%q,\"tag\":%q,\"message\":%q}\n", lvl.Name(), tag, err.Error())
 	}
+	return string(bytes) + "\n"
 }
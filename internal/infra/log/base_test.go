@@ -0,0 +1,158 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/enum"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLevelIsEnabled(t *testing.T) {
+	tests := []struct {
+		name    string
+		level   level
+		minimum level
+		want    bool
+	}{
+		{"debug on info", DebugLevel, InfoLevel, false},
+		{"info on info", InfoLevel, InfoLevel, true},
+		{"warn on info", WarnLevel, InfoLevel, true},
+		{"info on warn", InfoLevel, WarnLevel, false},
+		{"error on warn", ErrorLevel, WarnLevel, true},
+		{"debug on debug", DebugLevel, DebugLevel, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.level.IsEnabled(tt.minimum); got != tt.want {
+				t.Errorf("IsEnabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildJSONLine(t *testing.T) {
+	tests := []struct {
+		name           string
+		fields         Fields
+		message        string
+		wantMessage    string
+		wantDurationMs *int64
+	}{
+		{
+			name:        "message only",
+			message:     "Starting gateway",
+			wantMessage: "Starting gateway",
+		},
+		{
+			name:        "styled message",
+			message:     "\x1b[32mStarted\x1b[0m ",
+			wantMessage: "Started",
+		},
+		{
+			name: "request fields",
+			fields: Fields{
+				TraceID:   "abc",
+				RequestID: "req-1",
+				ClientIP:  "10.0.0.1",
+				Endpoint:  "/users",
+				Method:    "GET",
+				URL:       "/users?id=1",
+				Status:    200,
+				Duration:  15 * time.Millisecond,
+				Consumer:  "mobile",
+			},
+			message:        "Finished",
+			wantMessage:    "Finished",
+			wantDurationMs: newTestInt64(15),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line := buildJSONLine(WarnLevel, "ENDPOINT", tt.fields, tt.message)
+			if !strings.HasSuffix(line, "\n") {
+				t.Fatalf("buildJSONLine() = %q, want a line", line)
+			}
+
+			var got entry
+			if err := json.Unmarshal([]byte(line), &got); err != nil {
+				t.Fatalf("buildJSONLine() err = %v", err)
+			}
+			if got.Level != "warn" || got.Tag != "ENDPOINT" || got.Message != tt.wantMessage {
+				t.Errorf("buildJSONLine() = %+v, want level warn, tag ENDPOINT and message %q", got, tt.wantMessage)
+			} else if got.TraceID != tt.fields.TraceID || got.RequestID != tt.fields.RequestID ||
+				got.Consumer != tt.fields.Consumer ||
+				got.Status != tt.fields.Status {
+				t.Errorf("buildJSONLine() fields = %+v, want %+v", got, tt.fields)
+			} else if (got.DurationMs == nil) != (tt.wantDurationMs == nil) ||
+				(got.DurationMs != nil && *got.DurationMs != *tt.wantDurationMs) {
+				t.Errorf("buildJSONLine() duration-ms = %v, want %v", got.DurationMs, tt.wantDurationMs)
+			}
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		name   string
+		level  level
+		format enum.LogFormat
+		write  level
+		want   string
+	}{
+		{"text", InfoLevel, enum.LogFormatText, InfoLevel, "Started"},
+		{"json", InfoLevel, enum.LogFormatJSON, ErrorLevel, `"level":"error"`},
+		{"below the level", WarnLevel, enum.LogFormatJSON, InfoLevel, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buffer := newTestConfig(t, tt.level, tt.format)
+
+			Print(tt.write, "APP", Fields{}, "Started")
+
+			got := buffer.String()
+			if tt.want == "" && got != "" {
+				t.Errorf("Print() = %q, want nothing", got)
+			} else if !strings.Contains(got, tt.want) {
+				t.Errorf("Print() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func newTestConfig(t *testing.T, lvl level, format enum.LogFormat) *bytes.Buffer {
+	buffer := &bytes.Buffer{}
+
+	mutex.Lock()
+	previous := current
+	current = config{level: lvl, format: format, writer: buffer}
+	mutex.Unlock()
+
+	t.Cleanup(func() {
+		mutex.Lock()
+		current = previous
+		mutex.Unlock()
+	})
+	return buffer
+}
+
+func newTestInt64(value int64) *int64 {
+	return &value
+}
@@ -33,7 +33,11 @@ func NewBoot() app.BootLog {
 }
 
 func (l bootLog) PrintLogo() {
-	fmt.Printf(` 
+	// the logo and the blank lines are only for humans, they would break a json lines output
+	if IsJSON() {
+		return
+	}
+	fmt.Fprintf(currentConfig().writer, ` 
  ######    #######  ########  ######## ##    ##
 ##    ##  ##     ## ##     ## ##       ###   ##
 ##        ##     ## ##     ## ##       ####  ##
@@ -54,25 +58,28 @@ func (l bootLog) PrintTitle(title string) {
 }
 
 func (l bootLog) PrintInfo(msg ...any) {
-	Print(InfoLevel, l.tag, "", msg...)
+	Print(InfoLevel, l.tag, Fields{}, msg...)
 }
 
 func (l bootLog) PrintInfof(format string, msg ...any) {
-	Printf(InfoLevel, l.tag, "", format, msg...)
+	Printf(InfoLevel, l.tag, Fields{}, format, msg...)
 }
 
 func (l bootLog) PrintWarn(msg ...any) {
-	Print(WarnLevel, l.tag, "", msg...)
+	Print(WarnLevel, l.tag, Fields{}, msg...)
 }
 
 func (l bootLog) PrintWarnf(format string, msg ...any) {
-	Printf(WarnLevel, l.tag, "", format, msg...)
+	Printf(WarnLevel, l.tag, Fields{}, format, msg...)
 }
 
 func (l bootLog) PrintError(msg ...any) {
-	Print(ErrorLevel, l.tag, "", msg...)
+	Print(ErrorLevel, l.tag, Fields{}, msg...)
 }
 
 func (l bootLog) SkipLine() {
-	fmt.Println()
+	if IsJSON() {
+		return
+	}
+	fmt.Fprintln(currentConfig().writer)
 }
@@ -0,0 +1,102 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package log
+
+import (
+	"github.com/tech4works/checker"
+	"github.com/tech4works/gopen-gateway/internal/app/model/dto"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/enum"
+	"gopkg.in/natefinch/lumberjack.v2"
+	"io"
+	"math"
+	"os"
+	"sync"
+	"time"
+)
+
+type config struct {
+	level  level
+	format enum.LogFormat
+	writer io.Writer
+	file   *lumberjack.Logger
+}
+
+var (
+	mutex   = &sync.RWMutex{}
+	current = newDefaultConfig()
+)
+
+func Configure(log *dto.Log) {
+	next := newDefaultConfig()
+	if checker.NonNil(log) {
+		next.level = newLevel(log.Level)
+		if log.Format.IsEnumValid() {
+			next.format = log.Format
+		}
+		if checker.NonNil(log.File) {
+			next.file = newFile(log.File)
+			next.writer = io.MultiWriter(os.Stdout, next.file)
+		}
+	}
+
+	mutex.Lock()
+	previous := current
+	current = next
+	mutex.Unlock()
+
+	if checker.NonNil(previous.file) {
+		previous.file.Close()
+	}
+}
+
+func IsJSON() bool {
+	return checker.Equals(currentConfig().format, enum.LogFormatJSON)
+}
+
+func currentConfig() config {
+	mutex.RLock()
+	defer mutex.RUnlock()
+
+	return current
+}
+
+func newDefaultConfig() config {
+	return config{
+		level:  InfoLevel,
+		format: enum.LogFormatText,
+		writer: os.Stdout,
+	}
+}
+
+func newFile(file *dto.LogFile) *lumberjack.Logger {
+	// lumberjack works with megabytes and days, so the configured values are rounded up
+	maxSize := 100
+	if checker.NonNil(file.MaxSize) {
+		maxSize = int(math.Ceil(float64(*file.MaxSize) / (1 << 20)))
+	}
+	var maxAge int
+	if checker.NonNil(file.MaxAge) {
+		maxAge = int(math.Ceil(float64(file.MaxAge.Time()) / float64(24*time.Hour)))
+	}
+	return &lumberjack.Logger{
+		Filename:   file.Path,
+		MaxSize:    maxSize,
+		MaxBackups: file.MaxBackups,
+		MaxAge:     maxAge,
+		Compress:   file.Compress,
+	}
+}
@@ -0,0 +1,97 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package log
+
+import (
+	"github.com/tech4works/gopen-gateway/internal/app/model/dto"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/vo"
+	"testing"
+	"time"
+)
+
+func TestNewFile(t *testing.T) {
+	maxSize := vo.NewBytes("1500KB")
+	maxAge := vo.NewDuration(36 * time.Hour)
+
+	tests := []struct {
+		name        string
+		file        *dto.LogFile
+		wantMaxSize int
+		wantMaxAge  int
+	}{
+		{
+			name:        "defaults",
+			file:        &dto.LogFile{Path: "gopen.log"},
+			wantMaxSize: 100,
+		},
+		{
+			name:        "rounded up",
+			file:        &dto.LogFile{Path: "gopen.log", MaxSize: &maxSize, MaxAge: &maxAge},
+			wantMaxSize: 2,
+			wantMaxAge:  2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newFile(tt.file)
+			if got.Filename != tt.file.Path {
+				t.Errorf("newFile() filename = %s, want %s", got.Filename, tt.file.Path)
+			} else if got.MaxSize != tt.wantMaxSize {
+				t.Errorf("newFile() max size = %d, want %d", got.MaxSize, tt.wantMaxSize)
+			} else if got.MaxAge != tt.wantMaxAge {
+				t.Errorf("newFile() max age = %d, want %d", got.MaxAge, tt.wantMaxAge)
+			}
+		})
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	tests := []struct {
+		name       string
+		log        *dto.Log
+		wantLevel  level
+		wantFormat bool
+	}{
+		{
+			name:      "without config",
+			wantLevel: InfoLevel,
+		},
+		{
+			name:       "level and format",
+			log:        &dto.Log{Level: "DEBUG", Format: "JSON"},
+			wantLevel:  DebugLevel,
+			wantFormat: true,
+		},
+		{
+			name:      "invalid values",
+			log:       &dto.Log{Level: "TRACE", Format: "XML"},
+			wantLevel: InfoLevel,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			newTestConfig(t, InfoLevel, "")
+
+			Configure(tt.log)
+			if got := currentConfig().level; got != tt.wantLevel {
+				t.Errorf("Configure() level = %s, want %s", got, tt.wantLevel)
+			} else if IsJSON() != tt.wantFormat {
+				t.Errorf("Configure() json = %v, want %v", IsJSON(), tt.wantFormat)
+			}
+		})
+	}
+}
@@ -17,7 +17,6 @@
 package log
 
 import (
-	"fmt"
 	"github.com/tech4works/gopen-gateway/internal/app"
 	"github.com/tech4works/gopen-gateway/internal/domain/model/vo"
 )
@@ -34,37 +33,38 @@ func NewEndpoint() app.EndpointLog {
 
 func (e endpointLog) PrintInfof(endpoint *vo.Endpoint, request *vo.HTTPRequest, clientIP, traceID, format string,
 	msg ...any) {
-	Printf(InfoLevel, e.tag, e.prefix(endpoint, request, clientIP, traceID), format, msg...)
+	Printf(InfoLevel, e.tag, e.fields(endpoint, request, clientIP, traceID), format, msg...)
 }
 
 func (e endpointLog) PrintInfo(endpoint *vo.Endpoint, request *vo.HTTPRequest, clientIP, traceID string, msg ...any) {
-	Print(InfoLevel, e.tag, e.prefix(endpoint, request, clientIP, traceID), msg...)
+	Print(InfoLevel, e.tag, e.fields(endpoint, request, clientIP, traceID), msg...)
 }
 
 func (e endpointLog) PrintWarnf(endpoint *vo.Endpoint, request *vo.HTTPRequest, clientIP, traceID, format string,
 	msg ...any) {
-	Printf(WarnLevel, e.tag, e.prefix(endpoint, request, clientIP, traceID), format, msg...)
+	Printf(WarnLevel, e.tag, e.fields(endpoint, request, clientIP, traceID), format, msg...)
 }
 
 func (e endpointLog) PrintWarn(endpoint *vo.Endpoint, request *vo.HTTPRequest, clientIP, traceID string, msg ...any) {
-	Print(WarnLevel, e.tag, e.prefix(endpoint, request, clientIP, traceID), msg...)
+	Print(WarnLevel, e.tag, e.fields(endpoint, request, clientIP, traceID), msg...)
 }
 
 func (e endpointLog) PrintErrorf(endpoint *vo.Endpoint, request *vo.HTTPRequest, clientIP, traceID, format string,
 	msg ...any) {
-	Printf(ErrorLevel, e.tag, e.prefix(endpoint, request, clientIP, traceID), format, msg...)
+	Printf(ErrorLevel, e.tag, e.fields(endpoint, request, clientIP, traceID), format, msg...)
 }
 
 func (e endpointLog) PrintError(endpoint *vo.Endpoint, request *vo.HTTPRequest, clientIP, traceID string, msg ...any) {
-	Print(ErrorLevel, e.tag, e.prefix(endpoint, request, clientIP, traceID), msg...)
+	Print(ErrorLevel, e.tag, e.fields(endpoint, request, clientIP, traceID), msg...)
 }
 
-func (e endpointLog) prefix(endpoint *vo.Endpoint, request *vo.HTTPRequest, clientIP, traceID string) string {
-	path := endpoint.Path()
-	traceIDText := BuildTraceIDText(traceID)
-
-	method := BuildMethodText(request.Method())
-	url := BuildUriText(request.Url())
-
-	return fmt.Sprintf("[%s | %s | %s |%s| %s]", path, clientIP, traceIDText, method, url)
+func (e endpointLog) fields(endpoint *vo.Endpoint, request *vo.HTTPRequest, clientIP, traceID string) Fields {
+	return Fields{
+		TraceID:   traceID,
+		RequestID: request.RequestID(),
+		ClientIP:  clientIP,
+		Endpoint:  endpoint.Path(),
+		Method:    request.Method(),
+		URL:       request.Url(),
+	}
 }
@@ -37,25 +37,27 @@ func (a httpLog) PrintRequest(ctx app.Context) {
 		text += fmt.Sprintf(" | body.content-type: %s | body.size: %s", body.ContentType().String(), body.SizeInByteUnit())
 	}
 
-	Print(InfoLevel, "REQ", a.prefix(ctx), text)
+	Print(InfoLevel, "REQ", a.fields(ctx), text)
 }
 
 func (a httpLog) PrintResponse(ctx app.Context) {
-	statusCode := BuildStatusCodeText(ctx.Response().StatusCode())
-	duration := ctx.Duration().Milliseconds()
-
-	prefix := a.prefix(ctx)
+	fields := a.fields(ctx)
+	fields.Status = ctx.Response().StatusCode().Code()
+	fields.Duration = ctx.Duration()
+	if ctx.Request().HasConsumer() {
+		fields.Consumer = ctx.Request().Consumer().Name()
+	}
 
-	Printf(InfoLevel, "RES", prefix, "status-code:%s| duration: %vms", statusCode, duration)
+	Print(InfoLevel, "RES", fields)
 }
 
-func (a httpLog) prefix(ctx app.Context) string {
-	path := ctx.Request().Path().Raw()
-	traceID := BuildTraceIDText(ctx.TraceID())
-	ip := ctx.ClientIP()
-
-	method := BuildMethodText(ctx.Request().Method())
-	url := BuildUriText(ctx.Request().Url())
-
-	return fmt.Sprintf("[%s | %s | %s |%s| %s]", path, ip, traceID, method, url)
+func (a httpLog) fields(ctx app.Context) Fields {
+	return Fields{
+		TraceID:   ctx.TraceID(),
+		RequestID: ctx.Request().RequestID(),
+		ClientIP:  ctx.ClientIP(),
+		Endpoint:  ctx.Request().Path().Raw(),
+		Method:    ctx.Request().Method(),
+		URL:       ctx.Request().Url(),
+	}
 }
@@ -18,6 +18,7 @@ package log
 
 import (
 	"fmt"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/enum"
 )
 
 type level string
@@ -29,6 +30,36 @@ const (
 	ErrorLevel level = "ERR"
 )
 
+func newLevel(logLevel enum.LogLevel) level {
+	switch logLevel {
+	case enum.LogLevelDebug:
+		return DebugLevel
+	case enum.LogLevelWarn:
+		return WarnLevel
+	case enum.LogLevelError:
+		return ErrorLevel
+	default:
+		return InfoLevel
+	}
+}
+
+func (l level) IsEnabled(minimum level) bool {
+	return l.severity() >= minimum.severity()
+}
+
+func (l level) Name() string {
+	switch l {
+	case DebugLevel:
+		return "debug"
+	case WarnLevel:
+		return "warn"
+	case ErrorLevel:
+		return "error"
+	default:
+		return "info"
+	}
+}
+
 func (l level) String() string {
 	return fmt.Sprint(l.color(), string(l), "\x1b[0m")
 }
@@ -47,3 +78,16 @@ func (l level) color() string {
 		return "\x1b[0m"
 	}
 }
+
+func (l level) severity() int {
+	switch l {
+	case DebugLevel:
+		return 0
+	case WarnLevel:
+		return 2
+	case ErrorLevel:
+		return 3
+	default:
+		return 1
+	}
+}
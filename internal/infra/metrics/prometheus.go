@@ -0,0 +1,205 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metrics
+
+import (
+	"bytes"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/common/expfmt"
+	"github.com/tech4works/checker"
+	"github.com/tech4works/converter"
+	"github.com/tech4works/gopen-gateway/internal/app"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/vo"
+	"time"
+)
+
+const namespace = "gopen"
+
+type prometheusMetrics struct {
+	registry          *prometheus.Registry
+	requests          *prometheus.CounterVec
+	requestDuration   *prometheus.HistogramVec
+	inFlight          *prometheus.GaugeVec
+	backendDuration   *prometheus.HistogramVec
+	backendErrors     *prometheus.CounterVec
+	backendInFlight   *prometheus.GaugeVec
+	cacheHits         *prometheus.CounterVec
+	cacheMisses       *prometheus.CounterVec
+	cacheWrites       *prometheus.CounterVec
+	limiterRejections *prometheus.CounterVec
+	timeouts          *prometheus.CounterVec
+	panicsRecovered   *prometheus.CounterVec
+}
+
+func NewPrometheus() app.Metrics {
+	endpointLabels := []string{"endpoint", "method"}
+	requestLabels := []string{"endpoint", "method", "status"}
+
+	p := prometheusMetrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Total of requests handled by endpoint, method and status code.",
+		}, requestLabels),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Duration of requests handled by endpoint, method and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, requestLabels),
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "http_requests_in_flight",
+			Help:      "Requests currently being handled by endpoint and method.",
+		}, endpointLabels),
+		backendDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "backend_request_duration_seconds",
+			Help:      "Duration of backend calls by host and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"host", "status"}),
+		backendErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "backend_errors_total",
+			Help:      "Total of backend calls that failed without a response by host.",
+		}, []string{"host"}),
+		backendInFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "backend_requests_in_flight",
+			Help:      "Backend calls currently in progress by host.",
+		}, []string{"host"}),
+		cacheHits: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_hits_total",
+			Help:      "Total of responses served from cache by endpoint and method.",
+		}, endpointLabels),
+		cacheMisses: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_misses_total",
+			Help:      "Total of cacheable requests not found in cache by endpoint and method.",
+		}, endpointLabels),
+		cacheWrites: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_writes_total",
+			Help:      "Total of responses written to cache by endpoint and method.",
+		}, endpointLabels),
+		limiterRejections: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "limiter_rejections_total",
+			Help:      "Total of requests rejected by the limiter by endpoint, method and reason.",
+		}, []string{"endpoint", "method", "reason"}),
+		timeouts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "timeouts_total",
+			Help:      "Total of requests that reached the endpoint timeout by endpoint and method.",
+		}, endpointLabels),
+		panicsRecovered: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "panics_recovered_total",
+			Help:      "Total of panics recovered while handling requests by endpoint and method.",
+		}, endpointLabels),
+	}
+	p.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		p.requests,
+		p.requestDuration,
+		p.inFlight,
+		p.backendDuration,
+		p.backendErrors,
+		p.backendInFlight,
+		p.cacheHits,
+		p.cacheMisses,
+		p.cacheWrites,
+		p.limiterRejections,
+		p.timeouts,
+		p.panicsRecovered,
+	)
+	return p
+}
+
+func (p prometheusMetrics) IncrementInFlight(endpoint *vo.Endpoint) {
+	p.inFlight.WithLabelValues(endpoint.Path(), endpoint.Method()).Inc()
+}
+
+func (p prometheusMetrics) DecrementInFlight(endpoint *vo.Endpoint) {
+	p.inFlight.WithLabelValues(endpoint.Path(), endpoint.Method()).Dec()
+}
+
+func (p prometheusMetrics) ObserveRequest(endpoint *vo.Endpoint, statusCode vo.StatusCode, duration time.Duration) {
+	status := converter.ToString(statusCode.Code())
+	p.requests.WithLabelValues(endpoint.Path(), endpoint.Method(), status).Inc()
+	p.requestDuration.WithLabelValues(endpoint.Path(), endpoint.Method(), status).Observe(duration.Seconds())
+}
+
+func (p prometheusMetrics) IncrementBackendInFlight(host string) {
+	p.backendInFlight.WithLabelValues(host).Inc()
+}
+
+func (p prometheusMetrics) DecrementBackendInFlight(host string) {
+	p.backendInFlight.WithLabelValues(host).Dec()
+}
+
+func (p prometheusMetrics) ObserveBackendRequest(host string, statusCode vo.StatusCode, duration time.Duration,
+	err error) {
+	if checker.NonNil(err) {
+		p.backendErrors.WithLabelValues(host).Inc()
+	}
+	p.backendDuration.WithLabelValues(host, converter.ToString(statusCode.Code())).Observe(duration.Seconds())
+}
+
+func (p prometheusMetrics) IncrementCacheHit(endpoint *vo.Endpoint) {
+	p.cacheHits.WithLabelValues(endpoint.Path(), endpoint.Method()).Inc()
+}
+
+func (p prometheusMetrics) IncrementCacheMiss(endpoint *vo.Endpoint) {
+	p.cacheMisses.WithLabelValues(endpoint.Path(), endpoint.Method()).Inc()
+}
+
+func (p prometheusMetrics) IncrementCacheWrite(endpoint *vo.Endpoint) {
+	p.cacheWrites.WithLabelValues(endpoint.Path(), endpoint.Method()).Inc()
+}
+
+func (p prometheusMetrics) IncrementLimiterRejection(endpoint *vo.Endpoint, reason string) {
+	p.limiterRejections.WithLabelValues(endpoint.Path(), endpoint.Method(), reason).Inc()
+}
+
+func (p prometheusMetrics) IncrementTimeout(endpoint *vo.Endpoint) {
+	p.timeouts.WithLabelValues(endpoint.Path(), endpoint.Method()).Inc()
+}
+
+func (p prometheusMetrics) IncrementPanicRecovered(endpoint *vo.Endpoint) {
+	p.panicsRecovered.WithLabelValues(endpoint.Path(), endpoint.Method()).Inc()
+}
+
+func (p prometheusMetrics) Export() (string, error) {
+	families, err := p.registry.Gather()
+	if checker.NonNil(err) {
+		return "", err
+	}
+
+	var buffer bytes.Buffer
+	for _, family := range families {
+		_, err = expfmt.MetricFamilyToText(&buffer, family)
+		if checker.NonNil(err) {
+			return "", err
+		}
+	}
+	return buffer.String(), nil
+}
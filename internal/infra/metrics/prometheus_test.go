@@ -0,0 +1,117 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metrics
+
+import (
+	"github.com/tech4works/errors"
+	"github.com/tech4works/gopen-gateway/internal/app"
+	"github.com/tech4works/gopen-gateway/internal/domain/model/vo"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPrometheusExport(t *testing.T) {
+	endpoint := vo.NewEndpointStatic("/users", "GET")
+
+	tests := []struct {
+		name    string
+		observe func(metrics app.Metrics)
+		want    []string
+	}{
+		{
+			name: "request",
+			observe: func(metrics app.Metrics) {
+				metrics.ObserveRequest(&endpoint, vo.NewStatusCode(200), 100*time.Millisecond)
+				metrics.ObserveRequest(&endpoint, vo.NewStatusCode(200), 200*time.Millisecond)
+				metrics.ObserveRequest(&endpoint, vo.NewStatusCode(504), time.Second)
+			},
+			want: []string{
+				`gopen_http_requests_total{endpoint="/users",method="GET",status="200"} 2`,
+				`gopen_http_requests_total{endpoint="/users",method="GET",status="504"} 1`,
+				`gopen_http_request_duration_seconds_count{endpoint="/users",method="GET",status="200"} 2`,
+			},
+		},
+		{
+			name: "in flight",
+			observe: func(metrics app.Metrics) {
+				metrics.IncrementInFlight(&endpoint)
+				metrics.IncrementInFlight(&endpoint)
+				metrics.DecrementInFlight(&endpoint)
+			},
+			want: []string{`gopen_http_requests_in_flight{endpoint="/users",method="GET"} 1`},
+		},
+		{
+			name: "backend request",
+			observe: func(metrics app.Metrics) {
+				metrics.IncrementBackendInFlight("http://localhost:8080")
+				metrics.ObserveBackendRequest("http://localhost:8080", vo.NewStatusCode(200), time.Millisecond, nil)
+				metrics.ObserveBackendRequest("http://localhost:8080", vo.NewStatusCode(502), time.Millisecond,
+					errors.New("connection refused"))
+			},
+			want: []string{
+				`gopen_backend_requests_in_flight{host="http://localhost:8080"} 1`,
+				`gopen_backend_errors_total{host="http://localhost:8080"} 1`,
+				`gopen_backend_request_duration_seconds_count{host="http://localhost:8080",status="200"} 1`,
+				`gopen_backend_request_duration_seconds_count{host="http://localhost:8080",status="502"} 1`,
+			},
+		},
+		{
+			name: "cache",
+			observe: func(metrics app.Metrics) {
+				metrics.IncrementCacheMiss(&endpoint)
+				metrics.IncrementCacheWrite(&endpoint)
+				metrics.IncrementCacheHit(&endpoint)
+				metrics.IncrementCacheHit(&endpoint)
+			},
+			want: []string{
+				`gopen_cache_hits_total{endpoint="/users",method="GET"} 2`,
+				`gopen_cache_misses_total{endpoint="/users",method="GET"} 1`,
+				`gopen_cache_writes_total{endpoint="/users",method="GET"} 1`,
+			},
+		},
+		{
+			name: "limiter, timeout and panic",
+			observe: func(metrics app.Metrics) {
+				metrics.IncrementLimiterRejection(&endpoint, "rate")
+				metrics.IncrementTimeout(&endpoint)
+				metrics.IncrementPanicRecovered(&endpoint)
+			},
+			want: []string{
+				`gopen_limiter_rejections_total{endpoint="/users",method="GET",reason="rate"} 1`,
+				`gopen_timeouts_total{endpoint="/users",method="GET"} 1`,
+				`gopen_panics_recovered_total{endpoint="/users",method="GET"} 1`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics := NewPrometheus()
+			tt.observe(metrics)
+
+			got, err := metrics.Export()
+			if err != nil {
+				t.Fatalf("Export() err = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("Export() doesn't contain %s", want)
+				}
+			}
+		})
+	}
+}
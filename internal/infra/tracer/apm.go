@@ -0,0 +1,83 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tracer
+
+import (
+	"context"
+	"github.com/tech4works/checker"
+	"github.com/tech4works/gopen-gateway/internal/app"
+	"go.elastic.co/apm/module/apmhttp/v2"
+	"go.elastic.co/apm/v2"
+	"net/http"
+)
+
+type apmTracer struct {
+}
+
+type apmSpan struct {
+	span *apm.Span
+}
+
+func NewAPM() app.Tracer {
+	return apmTracer{}
+}
+
+func (a apmTracer) Handler(handler http.Handler) http.Handler {
+	return apmhttp.Wrap(handler)
+}
+
+func (a apmTracer) RoundTripper(ctx context.Context, roundTripper http.RoundTripper) http.RoundTripper {
+	if checker.IsNil(apm.TransactionFromContext(ctx)) {
+		return roundTripper
+	}
+	return apmhttp.WrapRoundTripper(roundTripper)
+}
+
+func (a apmTracer) StartSpan(ctx context.Context, name, spanType string) (context.Context, app.Span) {
+	span, ctx := apm.StartSpan(ctx, name, spanType)
+	return ctx, apmSpan{span: span}
+}
+
+func (a apmTracer) TraceID(ctx context.Context) string {
+	tx := apm.TransactionFromContext(ctx)
+	if checker.NonNil(tx) {
+		return tx.TraceContext().Trace.String()
+	}
+	return ""
+}
+
+func (a apmTracer) CaptureError(ctx context.Context, err error) {
+	tx := apm.TransactionFromContext(ctx)
+	if checker.NonNil(tx) {
+		apmErr := apm.DefaultTracer().NewError(err)
+		apmErr.SetTransaction(tx)
+		apmErr.Send()
+	}
+}
+
+func (a apmTracer) Shutdown(ctx context.Context) error {
+	apm.DefaultTracer().Flush(ctx.Done())
+	return nil
+}
+
+func (a apmSpan) SetLabel(key string, value any) {
+	a.span.Context.SetLabel(key, value)
+}
+
+func (a apmSpan) End() {
+	a.span.End()
+}
@@ -0,0 +1,62 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tracer
+
+import (
+	"context"
+	"github.com/tech4works/gopen-gateway/internal/app"
+	"net/http"
+)
+
+type noopTracer struct {
+}
+
+type noopSpan struct {
+}
+
+func NewNoop() app.Tracer {
+	return noopTracer{}
+}
+
+func (n noopTracer) Handler(handler http.Handler) http.Handler {
+	return handler
+}
+
+func (n noopTracer) RoundTripper(_ context.Context, roundTripper http.RoundTripper) http.RoundTripper {
+	return roundTripper
+}
+
+func (n noopTracer) StartSpan(ctx context.Context, _, _ string) (context.Context, app.Span) {
+	return ctx, noopSpan{}
+}
+
+func (n noopTracer) TraceID(_ context.Context) string {
+	return ""
+}
+
+func (n noopTracer) CaptureError(_ context.Context, _ error) {
+}
+
+func (n noopTracer) Shutdown(_ context.Context) error {
+	return nil
+}
+
+func (n noopSpan) SetLabel(_ string, _ any) {
+}
+
+func (n noopSpan) End() {
+}
@@ -0,0 +1,135 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tracer
+
+import (
+	"context"
+	"fmt"
+	"github.com/tech4works/checker"
+	"github.com/tech4works/gopen-gateway/internal/app"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+)
+
+type otelTracer struct {
+	provider *sdktrace.TracerProvider
+	tracer   trace.Tracer
+}
+
+type otelSpan struct {
+	span trace.Span
+}
+
+func NewOTel(serviceName, serviceVersion, endpoint string, headers map[string]string, sampleRatio float64) (
+	app.Tracer, error) {
+	// without an endpoint the exporter follows the OTEL_EXPORTER_OTLP_* environment variables
+	var options []otlptracehttp.Option
+	if checker.IsNotEmpty(endpoint) {
+		options = append(options, otlptracehttp.WithEndpointURL(endpoint))
+	}
+	if checker.IsNotEmpty(headers) {
+		options = append(options, otlptracehttp.WithHeaders(headers))
+	}
+
+	exporter, err := otlptracehttp.New(context.Background(), options...)
+	if checker.NonNil(err) {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+		sdktrace.WithResource(resource.NewSchemaless(
+			semconv.ServiceName(serviceName),
+			semconv.ServiceVersion(serviceVersion),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{},
+		propagation.Baggage{}))
+
+	return otelTracer{
+		provider: provider,
+		tracer:   provider.Tracer("github.com/tech4works/gopen-gateway"),
+	}, nil
+}
+
+func (o otelTracer) Handler(handler http.Handler) http.Handler {
+	return otelhttp.NewHandler(handler, "gopen", otelhttp.WithSpanNameFormatter(
+		func(_ string, r *http.Request) string {
+			return fmt.Sprint(r.Method, " ", r.URL.Path)
+		}),
+	)
+}
+
+func (o otelTracer) RoundTripper(_ context.Context, roundTripper http.RoundTripper) http.RoundTripper {
+	// the transport creates the backend call span and propagates the W3C traceparent to the backend
+	return otelhttp.NewTransport(roundTripper)
+}
+
+func (o otelTracer) StartSpan(ctx context.Context, name, spanType string) (context.Context, app.Span) {
+	ctx, span := o.tracer.Start(ctx, name, trace.WithAttributes(attribute.String("type", spanType)))
+	return ctx, otelSpan{span: span}
+}
+
+func (o otelTracer) TraceID(ctx context.Context) string {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if spanContext.HasTraceID() {
+		return spanContext.TraceID().String()
+	}
+	return ""
+}
+
+func (o otelTracer) CaptureError(ctx context.Context, err error) {
+	span := trace.SpanFromContext(ctx)
+	span.RecordError(err, trace.WithStackTrace(true))
+	span.SetStatus(codes.Error, err.Error())
+}
+
+func (o otelTracer) Shutdown(ctx context.Context) error {
+	return o.provider.Shutdown(ctx)
+}
+
+func (o otelSpan) SetLabel(key string, value any) {
+	switch v := value.(type) {
+	case string:
+		o.span.SetAttributes(attribute.String(key, v))
+	case bool:
+		o.span.SetAttributes(attribute.Bool(key, v))
+	case int:
+		o.span.SetAttributes(attribute.Int(key, v))
+	case int64:
+		o.span.SetAttributes(attribute.Int64(key, v))
+	case float64:
+		o.span.SetAttributes(attribute.Float64(key, v))
+	default:
+		o.span.SetAttributes(attribute.String(key, fmt.Sprint(v)))
+	}
+}
+
+func (o otelSpan) End() {
+	o.span.End()
+}
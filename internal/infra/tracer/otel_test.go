@@ -0,0 +1,135 @@
+/*
+ * Copyright 2024 Tech4Works
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tracer
+
+import (
+	"context"
+	"github.com/tech4works/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOTelStartSpan(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		want  attribute.Value
+	}{
+		{"string", "acme", attribute.StringValue("acme")},
+		{"bool", true, attribute.BoolValue(true)},
+		{"int", 3, attribute.IntValue(3)},
+		{"float", 0.5, attribute.Float64Value(0.5)},
+		{"other", []int{1}, attribute.StringValue("[1]")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracer, recorder := newTestOTelTracer()
+
+			ctx, span := tracer.StartSpan(context.Background(), "Allow", "limiter")
+			span.SetLabel("key", tt.value)
+			span.End()
+
+			spans := recorder.Ended()
+			if len(spans) != 1 {
+				t.Fatalf("StartSpan() ended spans = %d, want 1", len(spans))
+			} else if spans[0].Name() != "Allow" {
+				t.Errorf("StartSpan() name = %s, want Allow", spans[0].Name())
+			} else if tracer.TraceID(ctx) != spans[0].SpanContext().TraceID().String() {
+				t.Errorf("TraceID() = %s, want %s", tracer.TraceID(ctx), spans[0].SpanContext().TraceID())
+			}
+
+			attributes := map[attribute.Key]attribute.Value{}
+			for _, kv := range spans[0].Attributes() {
+				attributes[kv.Key] = kv.Value
+			}
+			if attributes["type"] != attribute.StringValue("limiter") {
+				t.Errorf("StartSpan() type = %v, want limiter", attributes["type"].Emit())
+			} else if attributes["key"] != tt.want {
+				t.Errorf("SetLabel() = %v, want %v", attributes["key"].Emit(), tt.want.Emit())
+			}
+		})
+	}
+}
+
+func TestOTelCaptureError(t *testing.T) {
+	tracer, recorder := newTestOTelTracer()
+
+	ctx, span := tracer.StartSpan(context.Background(), "GET /users", "request")
+	tracer.CaptureError(ctx, errors.New("backend unavailable"))
+	span.End()
+
+	got := recorder.Ended()[0]
+	if got.Status().Code != codes.Error {
+		t.Errorf("CaptureError() status = %v, want %v", got.Status().Code, codes.Error)
+	} else if len(got.Events()) != 1 || got.Events()[0].Name != "exception" {
+		t.Errorf("CaptureError() events = %v, want one exception", got.Events())
+	}
+}
+
+func TestOTelTraceIDWithoutSpan(t *testing.T) {
+	tracer, _ := newTestOTelTracer()
+	if got := tracer.TraceID(context.Background()); got != "" {
+		t.Errorf("TraceID() = %s, want empty", got)
+	}
+}
+
+func TestOTelRoundTripper(t *testing.T) {
+	tracer, recorder := newTestOTelTracer()
+
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("Traceparent")
+	}))
+	defer server.Close()
+
+	ctx, span := tracer.StartSpan(context.Background(), "GET /users", "request")
+	request, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	client := &http.Client{Transport: tracer.RoundTripper(ctx, http.DefaultTransport)}
+	response, err := client.Do(request)
+	if err != nil {
+		t.Fatalf("RoundTripper() err = %v", err)
+	}
+	response.Body.Close()
+	span.End()
+
+	// the backend receives the trace of the request, so its spans are linked to the gateway ones
+	traceID := tracer.TraceID(ctx)
+	if len(traceparent) < 36 || traceparent[3:35] != traceID {
+		t.Errorf("RoundTripper() traceparent = %s, want trace id %s", traceparent, traceID)
+	} else if len(recorder.Ended()) != 2 {
+		t.Errorf("RoundTripper() ended spans = %d, want 2", len(recorder.Ended()))
+	}
+}
+
+func newTestOTelTracer() (otelTracer, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	// like NewOTel, the provider and the propagator are global so that the instrumented transport uses them
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return otelTracer{
+		provider: provider,
+		tracer:   provider.Tracer("test"),
+	}, recorder
+}